@@ -0,0 +1,132 @@
+// cmd/admin は運用者向けの管理コマンドです
+//
+//	go run ./cmd/admin dlq list [-limit 20]
+//	go run ./cmd/admin dlq redrive [-patch '{"payload":{...}}'] <message-id>...
+//	go run ./cmd/admin dlq redrive -all [-patch '...']
+//	go run ./cmd/admin dlq purge -yes
+//
+// API と同じ環境変数 (SQS_QUEUE_URL / SQS_DLQ_URL / AWS_REGION / AWS_ENDPOINT) を使います。結果は JSON で標準出力に書き出す
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"my-portfolio-2025/internal/app/models"
+	"my-portfolio-2025/internal/app/service"
+	"my-portfolio-2025/internal/infrastructure/aws"
+	"my-portfolio-2025/internal/infrastructure/queue"
+
+	"github.com/joho/godotenv"
+)
+
+const usage = `usage: admin <command> [arguments]
+
+commands:
+  dlq list [-limit N]                    DLQ のメッセージを表示する
+  dlq redrive [-patch JSON] <id>...      指定したメッセージをメインキューへ戻す
+  dlq redrive -all [-patch JSON]         すべてのメッセージをメインキューへ戻す
+  dlq purge -yes                         DLQ のメッセージをすべて削除する
+`
+
+func main() {
+	_ = godotenv.Load()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := run(ctx, os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, args []string) error {
+	if len(args) < 2 || args[0] != "dlq" {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	return runDLQ(ctx, args[1], args[2:])
+}
+
+// newDeadLetterService は環境変数のメインキューと DLQ から DeadLetterService を作成します
+func newDeadLetterService(ctx context.Context) (service.DeadLetterService, error) {
+	mainClient, err := aws.NewSQSClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	dlqClient, err := aws.NewSQSDeadLetterClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return service.NewDeadLetterService(queue.NewSQSDeadLetterQueue(dlqClient), queue.NewSQSQueue(mainClient)), nil
+}
+
+func runDLQ(ctx context.Context, command string, args []string) error {
+	flags := flag.NewFlagSet("dlq "+command, flag.ExitOnError)
+	limit := flags.Int("limit", 20, "表示するメッセージの最大数 (最大100)")
+	all := flags.Bool("all", false, "すべてのメッセージを戻す")
+	patch := flags.String("patch", "", "戻す前に本文へ適用する JSON Merge Patch")
+	yes := flags.Bool("yes", false, "確認なしで削除する")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	switch command {
+	case "list", "redrive", "purge":
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	svc, err := newDeadLetterService(ctx)
+	if err != nil {
+		return err
+	}
+
+	switch command {
+	case "list":
+		list, err := svc.List(ctx, *limit)
+		if err != nil {
+			return err
+		}
+		return printJSON(list)
+
+	case "redrive":
+		req := &models.DeadLetterRedriveRequest{IDs: flags.Args(), All: *all}
+		if *patch != "" {
+			req.Patch = json.RawMessage(*patch)
+		}
+		result, err := svc.Redrive(ctx, req)
+		if result != nil {
+			if printErr := printJSON(result); printErr != nil {
+				return printErr
+			}
+		}
+		if err != nil {
+			return err
+		}
+		if len(result.Failed) > 0 || len(result.NotFound) > 0 {
+			return errors.New("some messages were not redriven")
+		}
+		return nil
+
+	default: // purge
+		if !*yes {
+			return errors.New("purge deletes every message in the DLQ; pass -yes to confirm")
+		}
+		return svc.Purge(ctx)
+	}
+}
+
+func printJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
@@ -2,11 +2,18 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
 	"net/url"
 	"os"
+	"os/signal"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
 	"time"
 
 	"my-portfolio-2025/internal/app/handler"
@@ -15,10 +22,17 @@ import (
 	"my-portfolio-2025/internal/app/router"
 	"my-portfolio-2025/internal/app/service"
 	"my-portfolio-2025/internal/infrastructure/aws"
+	"my-portfolio-2025/internal/infrastructure/chat"
+	"my-portfolio-2025/internal/infrastructure/egress"
+	"my-portfolio-2025/internal/infrastructure/mail"
+	"my-portfolio-2025/internal/infrastructure/queue"
 	"my-portfolio-2025/internal/infrastructure/redis"
+	"my-portfolio-2025/internal/infrastructure/webpush"
+	"my-portfolio-2025/pkg/utils"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
+	goredis "github.com/redis/go-redis/v9"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
@@ -45,6 +59,184 @@ func loadEnv() {
 	}
 }
 
+// splitEnvList はカンマ区切りの環境変数をスライスに変換します
+func splitEnvList(key string) []string {
+	var values []string
+	for _, v := range strings.Split(os.Getenv(key), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// escalationPolicyFromEnv は期限切れタスクの督促の方針を環境変数から読み込みます
+//   - OVERDUE_MAX_NOTIFICATIONS: 1タスクあたりの overdue 通知の上限 (0 で督促しない)
+//   - OVERDUE_ESCALATION_CHANNEL: 督促が続いた場合に追加する配信チャネル (例: email, chat)
+//   - OVERDUE_ESCALATE_AFTER: エスカレーション先にも送り始める overdue 通知の件数目
+func escalationPolicyFromEnv() service.EscalationPolicy {
+	policy := service.DefaultEscalationPolicy()
+	if v := os.Getenv("OVERDUE_MAX_NOTIFICATIONS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			policy.MaxNotifications = n
+		} else {
+			slog.Warn("Invalid OVERDUE_MAX_NOTIFICATIONS; using default", "value", v, "default", policy.MaxNotifications)
+		}
+	}
+	if v := os.Getenv("OVERDUE_ESCALATE_AFTER"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 1 {
+			policy.EscalateAfter = n
+		} else {
+			slog.Warn("Invalid OVERDUE_ESCALATE_AFTER; using default", "value", v, "default", policy.EscalateAfter)
+		}
+	}
+	if v := os.Getenv("OVERDUE_ESCALATION_CHANNEL"); v != "" {
+		if slices.Contains(models.NotificationChannels, v) {
+			policy.EscalationChannel = v
+		} else {
+			slog.Warn("Unknown OVERDUE_ESCALATION_CHANNEL; escalation is disabled", "value", v)
+		}
+	}
+	return policy
+}
+
+// workerPoolConfigFromEnv はワーカーの並列処理の設定を環境変数から読み込みます
+// WORKER_CONCURRENCY / WORKER_BATCH_SIZE / WORKER_VISIBILITY_TIMEOUT_SECONDS (未設定・不正な値は既定値)
+func workerPoolConfigFromEnv() service.WorkerPoolConfig {
+	cfg := service.DefaultWorkerPoolConfig()
+	if v := os.Getenv("WORKER_CONCURRENCY"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 1 {
+			cfg.Concurrency = n
+		} else {
+			slog.Warn("Invalid WORKER_CONCURRENCY; using default", "value", v, "default", cfg.Concurrency)
+		}
+	}
+	if v := os.Getenv("WORKER_BATCH_SIZE"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 1 && n <= 10 {
+			cfg.BatchSize = n
+		} else {
+			slog.Warn("Invalid WORKER_BATCH_SIZE; using default", "value", v, "default", cfg.BatchSize)
+		}
+	}
+	if v := os.Getenv("WORKER_VISIBILITY_TIMEOUT_SECONDS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			cfg.VisibilityTimeout = time.Duration(n) * time.Second
+		} else {
+			slog.Warn("Invalid WORKER_VISIBILITY_TIMEOUT_SECONDS; using default", "value", v, "default", cfg.VisibilityTimeout)
+		}
+	}
+	return cfg
+}
+
+// leaderLeaseTTLFromEnv はリーダーのリースの期限を環境変数 LEADER_LEASE_TTL_SECONDS から読み込みます (未設定・不正な値は既定値)
+func leaderLeaseTTLFromEnv() time.Duration {
+	ttl := service.DefaultLeaderLeaseTTL
+	if v := os.Getenv("LEADER_LEASE_TTL_SECONDS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 3 {
+			ttl = time.Duration(n) * time.Second
+		} else {
+			slog.Warn("Invalid LEADER_LEASE_TTL_SECONDS; using default", "value", v, "default", ttl)
+		}
+	}
+	return ttl
+}
+
+// 停止時に処理中のリクエスト・メッセージを待つ時間の既定値
+// (ECS が SIGTERM から SIGKILL まで待つ 30 秒より短くする)
+const defaultShutdownTimeout = 25 * time.Second
+
+// shutdownTimeoutFromEnv は停止時の猶予を環境変数 SHUTDOWN_TIMEOUT_SECONDS から読み込みます (未設定・不正な値は既定値)
+func shutdownTimeoutFromEnv() time.Duration {
+	timeout := defaultShutdownTimeout
+	if v := os.Getenv("SHUTDOWN_TIMEOUT_SECONDS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 1 {
+			timeout = time.Duration(n) * time.Second
+		} else {
+			slog.Warn("Invalid SHUTDOWN_TIMEOUT_SECONDS; using default", "value", v, "default", timeout)
+		}
+	}
+	return timeout
+}
+
+// registerScheduledJobs は定期ジョブを登録します (cron 式は JST)
+func registerScheduledJobs(scheduler service.SchedulerService, workerService *service.WorkerService, outbox *service.Outbox) error {
+	jobs := []service.ScheduledJob{
+		{
+			Name:        "purge_processed_jobs",
+			Schedule:    "0 * * * *",
+			Description: "保持期間 (14日) を過ぎた処理済みジョブの台帳を削除する",
+			MissedRuns:  models.MissedRunOnce,
+			Run:         workerService.PurgeProcessedJobs,
+		},
+		{
+			Name:        "purge_outbox",
+			Schedule:    "30 * * * *",
+			Description: "保持期間 (7日) を過ぎた送信済みの outbox のジョブを削除する",
+			MissedRuns:  models.MissedRunOnce,
+			Run:         outbox.PurgeSent,
+		},
+		{
+			// 送信時刻はユーザーのタイムゾーンの 8:00。UTC+5:45 などの時差にも合うよう15分ごとに確認する
+			Name:        "send_digests",
+			Schedule:    "*/15 * * * *",
+			Description: "送信時刻を過ぎたユーザーへダイジェストメールを送る",
+			MissedRuns:  models.MissedRunOnce,
+			Run:         workerService.SendDigests,
+		},
+	}
+	for _, job := range jobs {
+		if err := scheduler.Register(job); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// queueFromEnv はワーカーのジョブキューを環境変数 QUEUE_BACKEND (sqs / redis / postgres / memory、未設定は sqs) から作成します
+// redis / postgres はキューを QUEUE_NAME (未設定は notifications) で分ける。SQS は SQS_QUEUE_URL のキューを使う
+func queueFromEnv(ctx context.Context, db *gorm.DB, rdb *goredis.Client) (queue.Queue, error) {
+	name := os.Getenv("QUEUE_NAME")
+	if name == "" {
+		name = "notifications"
+	}
+
+	switch backend := os.Getenv("QUEUE_BACKEND"); backend {
+	case "", queue.BackendSQS:
+		client, err := aws.NewSQSClient(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return queue.NewSQSQueue(client), nil
+	case queue.BackendRedis:
+		return queue.NewRedisStreamQueue(rdb, name), nil
+	case queue.BackendPostgres:
+		return queue.NewPostgresQueue(db, name), nil
+	case queue.BackendMemory:
+		slog.Warn("Using in-memory job queue; jobs are lost on restart and are not shared between processes")
+		return queue.NewMemoryQueue(), nil
+	default:
+		return nil, fmt.Errorf("unknown QUEUE_BACKEND %q", backend)
+	}
+}
+
+// deadLetterServiceFromEnv は SQS の DLQ (環境変数 SQS_DLQ_URL) を管理する DeadLetterService を作成します
+// SQS 以外のバックエンドには DLQ が無いため、その場合と SQS_DLQ_URL が未設定の場合は nil を返す
+func deadLetterServiceFromEnv(ctx context.Context, target queue.Queue) service.DeadLetterService {
+	if backend := os.Getenv("QUEUE_BACKEND"); (backend != "" && backend != queue.BackendSQS) || target == nil {
+		return nil
+	}
+	if os.Getenv("SQS_DLQ_URL") == "" {
+		slog.Warn("SQS_DLQ_URL is not set; dead-letter queue admin is disabled")
+		return nil
+	}
+	client, err := aws.NewSQSDeadLetterClient(ctx)
+	if err != nil {
+		slog.Error("SQS dead-letter queue initialization failed", "error", err)
+		return nil
+	}
+	return service.NewDeadLetterService(queue.NewSQSDeadLetterQueue(client), target)
+}
+
 // setupDatabase はDB接続の確立、テスト、マイグレーションを行います
 func setupDatabase() *gorm.DB {
 	slog.Info("Starting database connection...")
@@ -71,7 +263,9 @@ func setupDatabase() *gorm.DB {
 
 	for i := 1; i <= maxRetries; i++ {
 
-		db, err = gorm.Open(postgres.Open(dbURI), &gorm.Config{})
+		// タイムゾーンなしの timestamp 列 (tasks.updated_at など) に常に JST で書き込むよう、
+		// 自動で設定される時刻をプロセスの TZ に依存させない
+		db, err = gorm.Open(postgres.Open(dbURI), &gorm.Config{NowFunc: utils.NowJST})
 		if err == nil {
 			if sqlDB, pingErr := db.DB(); pingErr == nil {
 				if pingErr = sqlDB.Ping(); pingErr == nil {
@@ -92,7 +286,7 @@ func setupDatabase() *gorm.DB {
 	}
 
 	// マイグレーション
-	if err := db.AutoMigrate(&models.User{}, &models.Task{}, &models.Notification{}); err != nil {
+	if err := db.AutoMigrate(&models.User{}, &models.Task{}, &models.Notification{}, &models.NotificationPreference{}, &models.NotificationRule{}, &models.WebhookSubscription{}, &models.WebhookDelivery{}, &models.ChatIntegration{}, &models.PushSubscription{}, &models.TaskReminder{}, &models.TaskEscalation{}, &models.OutboxMessage{}, &models.ProcessedJob{}, &models.ScheduledJobRun{}, &queue.PostgresMessage{}); err != nil {
 		slog.Error("Database migration failed", "error", err)
 		os.Exit(1)
 	}
@@ -108,8 +302,10 @@ func main() {
 	loadEnv()
 
 	// 2. 基盤 Context と DB 初期化
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+	// SIGINT / SIGTERM で ctx がキャンセルされ、停止処理を始める (docs/graceful-shutdown.md)
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	shutdownTimeout := shutdownTimeoutFromEnv()
 
 	db := setupDatabase()
 
@@ -122,11 +318,10 @@ func main() {
 		os.Exit(1)
 	}
 
-	// SQS
-	queueName := os.Getenv("SQS_QUEUE_NAME")
-	sqsClient, err := aws.NewSQSClient(ctx, queueName)
+	// ジョブキュー (docs/queue-backends.md)
+	jobQueue, err := queueFromEnv(ctx, db, rdb)
 	if err != nil {
-		slog.Error("SQS initialization failed", "error", err)
+		slog.Error("Job queue initialization failed", "error", err)
 		// 本番ワーカーモードなら Exit、APIモードなら続行などの判断が可能
 	}
 
@@ -135,37 +330,147 @@ func main() {
 	userRepo := repository.NewUserRepository(db)
 	taskRepo := repository.NewTaskRepository(db)
 	notiRepo := repository.NewNotificationRepository(db)
+	prefRepo := repository.NewNotificationPreferenceRepository(db)
+	webhookRepo := repository.NewWebhookRepository(db)
+	chatRepo := repository.NewChatIntegrationRepository(db)
+	pushRepo := repository.NewPushSubscriptionRepository(db)
+	reminderRepo := repository.NewTaskReminderRepository(db)
+	escalationRepo := repository.NewTaskEscalationRepository(db)
+	outboxRepo := repository.NewOutboxRepository(db)
+	processedJobRepo := repository.NewProcessedJobRepository(db)
+	scheduledJobRunRepo := repository.NewScheduledJobRunRepository(db)
 
 	// Hub & Services
+	// Hub は停止時に WebSocket の切断を処理し終えるまで動かすため、ctx とは別に止める
 	hub := service.NewNotificationHub(rdb)
-	go hub.Run(ctx)
+	hubCtx, stopHub := context.WithCancel(context.WithoutCancel(ctx))
+	hubStopped := make(chan struct{})
+	go func() {
+		defer close(hubStopped)
+		hub.Run(hubCtx)
+	}()
 
 	authService := service.NewAuthService(userRepo)
-	notiService := service.NewNotificationService(notiRepo)
+	notiService := service.NewNotificationService(notiRepo, hub)
+	prefService := service.NewNotificationPreferenceService(prefRepo)
+	reminderService := service.NewReminderService(reminderRepo, taskRepo, prefService)
+	escalationService := service.NewEscalationService(escalationRepo, taskRepo, escalationPolicyFromEnv())
+
+	// キューへ送るジョブは DB の変更と同じトランザクションで outbox に書き込み、ワーカーのリレーがキューへ送る
+	var relayQueue service.JobQueue
+	if jobQueue != nil {
+		relayQueue = jobQueue
+	}
+	outbox := service.NewOutbox(repository.NewTransactor(db), outboxRepo, relayQueue)
+
+	// Webhook の配信ジョブは outbox (キュー) 経由でワーカーが実行する
+	webhookService := service.NewWebhookService(webhookRepo, outbox)
+	chatService := service.NewChatIntegrationService(chatRepo, chat.NewClient(egress.Default))
+
+	// Web Push は VAPID 鍵が設定されている場合のみ有効にする (鍵は cmd/vapid-keygen で作成)
+	var pushSender service.PushSender
+	if vapidCfg, ok := webpush.ConfigFromEnv(); ok {
+		client, err := webpush.NewClient(vapidCfg)
+		if err != nil {
+			slog.Error("Invalid VAPID configuration", "error", err)
+			os.Exit(1)
+		}
+		pushSender = client
+	} else {
+		slog.Warn("VAPID keys are not configured; web push is disabled")
+	}
+	pushService := service.NewPushService(pushRepo, pushSender)
+
+	// 配信チャネル (SMTP が設定されている場合のみメールを有効にする)
+	notifiers := []service.Notifier{
+		service.NewWebSocketNotifier(hub),
+		service.NewWebhookNotifier(webhookService),
+		service.NewChatNotifier(chatService),
+		service.NewPushNotifier(pushService, hub),
+	}
+	var digestService service.DigestService
+	if smtpCfg, ok := mail.ConfigFromEnv(); ok {
+		mailer := mail.NewSMTPSender(smtpCfg)
+		notifiers = append(notifiers, service.NewEmailNotifier(userRepo, mailer))
+		digestService = service.NewDigestService(prefRepo, notiRepo, userRepo, mailer)
+	} else {
+		slog.Warn("SMTP is not configured; email notifications are disabled")
+	}
 
 	// WorkerService
-	workerService := service.NewWorkerService(sqsClient, taskRepo, notiService, hub)
+	workerService := service.NewWorkerService(jobQueue, outbox, processedJobRepo, taskRepo, notiService, prefService, digestService, webhookService, reminderService, escalationService, notifiers...)
+
+	// タスク監視ループはリースを取得できた1つのレプリカだけで実行する (docs/leader-election.md)
+	taskWatcherLease := service.NewRedisLeaderLease(rdb, service.TaskWatcherLeaseName)
+
+	// 定期ジョブ (docs/scheduled-jobs.md)。ワーカーが実行し、API は一覧・手動実行の依頼に使う
+	scheduler := service.NewSchedulerService(scheduledJobRunRepo, func(name string) service.LeaderLease {
+		return service.NewRedisLeaderLease(rdb, name)
+	}, leaderLeaseTTLFromEnv())
+	if err := registerScheduledJobs(scheduler, workerService, outbox); err != nil {
+		slog.Error("Failed to register scheduled jobs", "error", err)
+		os.Exit(1)
+	}
 
 	// Task/Auth Handler dependencies
-	taskService := service.NewTaskService(taskRepo, workerService)
+	// タスクのイベントは WebSocket へ配信し、リマインダーと督促も期限に合わせて設定し直す
+	// Webhook へはタスクの変更と同じトランザクションで outbox に記録したイベントから、ワーカーが配信する
+	taskEvents := service.TaskEventPublishers{hub, reminderService, escalationService}
+	taskService := service.NewTaskService(taskRepo, workerService, taskEvents, outbox)
+	syncService := service.NewSyncService(taskRepo, notiRepo, taskEvents, outbox)
 
 	authHandler := handler.NewAuthController(authService)
 	taskHandler := handler.NewTaskHandler(taskService)
-	notificationHandler := handler.NewNotificationHandler(notiService, hub)
+	reminderHandler := handler.NewReminderHandler(reminderService)
+	wsTicketService := service.NewWSTicketService(rdb)
+
+	// DLQ の確認・再投入 (docs/dead-letter-queue.md)
+	var deadLetterHandler *handler.DeadLetterHandler
+	if deadLetterService := deadLetterServiceFromEnv(ctx, jobQueue); deadLetterService != nil {
+		deadLetterHandler = handler.NewDeadLetterHandler(deadLetterService)
+	}
+	notificationActionService := service.NewNotificationActionService(notiService, taskService)
+	notificationHandler := handler.NewNotificationHandler(notiService, taskService, notificationActionService, hub, wsTicketService, splitEnvList("WS_ALLOWED_ORIGINS"))
+	syncHandler := handler.NewSyncHandler(syncService)
+	preferenceHandler := handler.NewNotificationPreferenceHandler(prefService)
+	webhookHandler := handler.NewWebhookHandler(webhookService)
+	chatHandler := handler.NewChatIntegrationHandler(chatService)
+	pushHandler := handler.NewPushHandler(pushService)
+	scheduledJobHandler := handler.NewScheduledJobHandler(scheduler)
 
 	// 5. 実行モードの判定
 	mode := os.Getenv("MODE")
 
 	if mode == "worker" {
 		slog.Info("Starting in WORKER mode")
-		if sqsClient == nil {
-			slog.Error("Worker mode requires a valid SQS client")
+		if jobQueue == nil {
+			slog.Error("Worker mode requires a valid job queue")
 			os.Exit(1)
 		}
 
-		go workerService.StartTaskWatcher(ctx)
-		slog.Info("Worker service is polling SQS")
-		workerService.StartWorker(ctx) // 無限ループ
+		var background sync.WaitGroup
+		background.Add(3)
+		go func() {
+			defer background.Done()
+			outbox.Run(ctx)
+		}()
+		election := service.NewLeaderElection(taskWatcherLease, leaderLeaseTTLFromEnv())
+		go func() {
+			defer background.Done()
+			election.Run(ctx, workerService.StartTaskWatcher)
+		}()
+		go func() {
+			defer background.Done()
+			scheduler.Run(ctx)
+		}()
+
+		// ctx がキャンセルされるまで受信を続け、停止時は処理中のメッセージを shutdownTimeout まで待つ
+		cfg := workerPoolConfigFromEnv()
+		cfg.DrainTimeout = shutdownTimeout
+		slog.Info("Worker service is polling the job queue")
+		workerService.StartWorker(ctx, cfg)
+		slog.Info("Worker stopped receiving; waiting for background loops")
+		background.Wait()
 
 	} else {
 		slog.Info("Starting in API server mode")
@@ -175,7 +480,13 @@ func main() {
 			gin.SetMode(gin.ReleaseMode)
 		}
 
-		r := router.SetupRouter(authHandler, taskHandler, notificationHandler, rdb)
+		// 管理用エンドポイント (/admin) は ADMIN_API_TOKEN が設定されている場合のみ有効にする
+		adminToken := os.Getenv("ADMIN_API_TOKEN")
+		if adminToken == "" {
+			slog.Warn("ADMIN_API_TOKEN is not set; admin endpoints are disabled")
+		}
+
+		r := router.SetupRouter(authHandler, taskHandler, reminderHandler, notificationHandler, syncHandler, preferenceHandler, webhookHandler, chatHandler, pushHandler, deadLetterHandler, scheduledJobHandler, wsTicketService, rdb, adminToken)
 
 		// ヘルスチェック (slog を活用)
 		r.GET("/health", func(c *gin.Context) {
@@ -185,7 +496,15 @@ func main() {
 				c.JSON(http.StatusInternalServerError, gin.H{"status": "error"})
 				return
 			}
-			c.JSON(http.StatusOK, gin.H{"status": "ok"})
+			// タスク監視ループを実行中のワーカー (取得できなくても API の稼働には影響しないため status は ok のまま)
+			watcher := gin.H{"leader": nil}
+			if holder, err := taskWatcherLease.Holder(c.Request.Context()); err != nil {
+				slog.Warn("Healthcheck: failed to read task watcher leader", "error", err)
+				watcher["error"] = "unavailable"
+			} else if holder != "" {
+				watcher["leader"] = holder
+			}
+			c.JSON(http.StatusOK, gin.H{"status": "ok", "task_watcher": watcher})
 		})
 
 		port := os.Getenv("PORT")
@@ -193,10 +512,51 @@ func main() {
 			port = "8080"
 		}
 
-		slog.Info("API server starting", "port", port, "env", os.Getenv("APP_ENV"))
-		if err := r.Run(":" + port); err != nil {
+		srv := &http.Server{Addr: ":" + port, Handler: r}
+		serverErr := make(chan error, 1)
+		go func() {
+			slog.Info("API server starting", "port", port, "env", os.Getenv("APP_ENV"))
+			if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				serverErr <- err
+			}
+		}()
+
+		select {
+		case err := <-serverErr:
 			slog.Error("Server stopped unexpectedly", "error", err)
 			os.Exit(1)
+		case <-ctx.Done():
+		}
+
+		// 新しい接続の受け付けを止めて処理中のリクエストを待つ。WebSocket は Shutdown の対象外のため、Hub から close フレームを送る
+		slog.Info("Shutting down API server", "timeout", shutdownTimeout)
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+
+		var closing sync.WaitGroup
+		closing.Add(1)
+		go func() {
+			defer closing.Done()
+			if err := hub.Shutdown(shutdownCtx); err != nil {
+				slog.Warn("WebSocket clients did not disconnect in time", "error", err)
+			}
+		}()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			slog.Warn("HTTP server did not shut down cleanly", "error", err)
+		}
+		closing.Wait()
+	}
+
+	// 6. 後片付け
+	stopHub()
+	<-hubStopped
+	if err := rdb.Close(); err != nil {
+		slog.Warn("Failed to close Redis client", "error", err)
+	}
+	if sqlDB, err := db.DB(); err == nil {
+		if err := sqlDB.Close(); err != nil {
+			slog.Warn("Failed to close database", "error", err)
 		}
 	}
+	slog.Info("Shutdown complete")
 }
@@ -0,0 +1,25 @@
+// cmd/vapid-keygen は Web Push 用の VAPID 鍵ペアを作成し、環境変数の形式で出力します
+//
+//	go run ./cmd/vapid-keygen >> .env
+//
+// 鍵を作り直すと既存のブラウザの購読は全て無効になるため、環境ごとに一度だけ作成してください
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"my-portfolio-2025/internal/infrastructure/webpush"
+)
+
+func main() {
+	publicKey, privateKey, err := webpush.GenerateKeys()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("VAPID_PUBLIC_KEY=%s\n", publicKey)
+	fmt.Printf("VAPID_PRIVATE_KEY=%s\n", privateKey)
+	fmt.Println("VAPID_SUBJECT=mailto:admin@example.com")
+}
@@ -11,6 +11,7 @@ var (
 	ErrForbidden    = errors.New("access forbidden")      // 403用
 	ErrUnauthorized = errors.New("unauthorized")          // 401用
 	ErrValidation   = errors.New("validation failed")     // 400用
+	ErrConflict     = errors.New("conflict")              // 409用
 	ErrInternal     = errors.New("internal server error") // 500用
 )
 
@@ -0,0 +1,162 @@
+// internal/app/handler/chat_integration_handler.go
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"my-portfolio-2025/internal/app/apperr"
+	"my-portfolio-2025/internal/app/models"
+	"my-portfolio-2025/internal/app/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ChatIntegrationHandler はチャット連携 (Slack / Teams) の管理エンドポイントを処理します
+type ChatIntegrationHandler struct {
+	chatService service.ChatIntegrationService
+}
+
+// NewChatIntegrationHandler は ChatIntegrationHandler の新しいインスタンスを作成します
+func NewChatIntegrationHandler(s service.ChatIntegrationService) *ChatIntegrationHandler {
+	return &ChatIntegrationHandler{chatService: s}
+}
+
+// handleError: 他のハンドラーと共通のエラーハンドリング方針
+func (h *ChatIntegrationHandler) handleError(c *gin.Context, err error) {
+	var status int
+	var msg string
+
+	switch {
+	case errors.Is(err, apperr.ErrValidation):
+		status = http.StatusBadRequest
+		msg = err.Error()
+	case errors.Is(err, apperr.ErrNotFound):
+		status = http.StatusNotFound
+		msg = "指定されたチャット連携が見つかりません"
+	case errors.Is(err, apperr.ErrUnauthorized):
+		status = http.StatusUnauthorized
+		msg = "認証が必要です"
+	default:
+		slog.Error("Chat integration handler error", "error", err)
+		status = http.StatusInternalServerError
+		msg = "サーバー内部エラーが発生しました"
+	}
+
+	c.JSON(status, gin.H{"error": msg})
+}
+
+// parseIDs はログインユーザーとパスの :id を取り出します
+func (h *ChatIntegrationHandler) parseIDs(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	userID := getUserIDFromContext(c)
+	if userID == uuid.Nil {
+		h.handleError(c, apperr.ErrUnauthorized)
+		return uuid.Nil, uuid.Nil, false
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		h.handleError(c, fmt.Errorf("%w: invalid chat integration id", apperr.ErrValidation))
+		return uuid.Nil, uuid.Nil, false
+	}
+	return userID, id, true
+}
+
+// Create はチャット連携を登録します
+// POST /integrations/chat
+func (h *ChatIntegrationHandler) Create(c *gin.Context) {
+	userID := getUserIDFromContext(c)
+	if userID == uuid.Nil {
+		h.handleError(c, apperr.ErrUnauthorized)
+		return
+	}
+
+	var req models.ChatIntegrationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.handleError(c, fmt.Errorf("%w: %v", apperr.ErrValidation, err))
+		return
+	}
+
+	integration, err := h.chatService.Create(c.Request.Context(), userID, &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, integration)
+}
+
+// List は登録済みのチャット連携一覧を返します
+// GET /integrations/chat
+func (h *ChatIntegrationHandler) List(c *gin.Context) {
+	userID := getUserIDFromContext(c)
+	if userID == uuid.Nil {
+		h.handleError(c, apperr.ErrUnauthorized)
+		return
+	}
+
+	integrations, err := h.chatService.List(c.Request.Context(), userID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, integrations)
+}
+
+// Update はチャット連携を更新します
+// PUT /integrations/chat/:id
+func (h *ChatIntegrationHandler) Update(c *gin.Context) {
+	userID, id, ok := h.parseIDs(c)
+	if !ok {
+		return
+	}
+
+	var req models.ChatIntegrationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.handleError(c, fmt.Errorf("%w: %v", apperr.ErrValidation, err))
+		return
+	}
+
+	integration, err := h.chatService.Update(c.Request.Context(), userID, id, &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, integration)
+}
+
+// Delete はチャット連携を削除します
+// DELETE /integrations/chat/:id
+func (h *ChatIntegrationHandler) Delete(c *gin.Context) {
+	userID, id, ok := h.parseIDs(c)
+	if !ok {
+		return
+	}
+
+	if err := h.chatService.Delete(c.Request.Context(), userID, id); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// SendTest はテストメッセージを投稿し、その結果を返します
+// POST /integrations/chat/:id/test
+func (h *ChatIntegrationHandler) SendTest(c *gin.Context) {
+	userID, id, ok := h.parseIDs(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.chatService.SendTest(c.Request.Context(), userID, id)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,86 @@
+// internal/app/handler/dead_letter_handler.go
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"my-portfolio-2025/internal/app/apperr"
+	"my-portfolio-2025/internal/app/models"
+	"my-portfolio-2025/internal/app/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DeadLetterHandler は DLQ の管理エンドポイント (/admin/dlq) を処理します
+type DeadLetterHandler struct {
+	deadLetterService service.DeadLetterService
+}
+
+// NewDeadLetterHandler は DeadLetterHandler の新しいインスタンスを作成します
+func NewDeadLetterHandler(s service.DeadLetterService) *DeadLetterHandler {
+	return &DeadLetterHandler{deadLetterService: s}
+}
+
+// handleError: 他のハンドラーと共通のエラーハンドリング方針
+func (h *DeadLetterHandler) handleError(c *gin.Context, err error) {
+	var status int
+	var msg string
+
+	switch {
+	case errors.Is(err, apperr.ErrValidation):
+		status = http.StatusBadRequest
+		msg = err.Error()
+	default:
+		slog.Error("Dead-letter handler error", "error", err)
+		status = http.StatusInternalServerError
+		msg = "サーバー内部エラーが発生しました"
+	}
+
+	c.JSON(status, gin.H{"error": msg})
+}
+
+// List は DLQ のメッセージを封筒をデコードして返します
+// GET /admin/dlq?limit=20
+func (h *DeadLetterHandler) List(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	list, err := h.deadLetterService.List(c.Request.Context(), limit)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// Redrive は DLQ のメッセージをメインキューへ戻します
+// POST /admin/dlq/redrive {"ids":["..."]} または {"all":true}、patch で本文を書き換えられる
+func (h *DeadLetterHandler) Redrive(c *gin.Context) {
+	var req models.DeadLetterRedriveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.handleError(c, fmt.Errorf("%w: %v", apperr.ErrValidation, err))
+		return
+	}
+
+	result, err := h.deadLetterService.Redrive(c.Request.Context(), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// Purge は DLQ のメッセージをすべて削除します
+// DELETE /admin/dlq
+func (h *DeadLetterHandler) Purge(c *gin.Context) {
+	if err := h.deadLetterService.Purge(c.Request.Context()); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
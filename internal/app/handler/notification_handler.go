@@ -6,33 +6,72 @@ import (
 	"fmt"
 	"log/slog"
 	"my-portfolio-2025/internal/app/apperr"
+	"my-portfolio-2025/internal/app/middleware"
+	"my-portfolio-2025/internal/app/models"
 	"my-portfolio-2025/internal/app/service"
 	"net/http"
-	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
 )
 
-// WebSocketのアップグレーダー設定
-var upgrader = websocket.Upgrader{
-	ReadBufferSize:  1024,
-	WriteBufferSize: 1024,
-	// すべての接続を許可する（開発用）
-	// ブラウザからws接続する為に必要
-	CheckOrigin: func(r *http.Request) bool {
-		return true
-	},
+// newUpgrader は Origin の許可リストを持つアップグレーダーを作成します
+// 許可リストが空の場合は gorilla/websocket の既定動作（同一オリジンのみ許可）になります
+func newUpgrader(allowedOrigins []string) websocket.Upgrader {
+	u := websocket.Upgrader{
+		ReadBufferSize:  1024,
+		WriteBufferSize: 1024,
+		// チケットを Sec-WebSocket-Protocol で渡すクライアントにはこのプロトコル名を返す
+		Subprotocols: []string{middleware.WSSubprotocol},
+	}
+	if len(allowedOrigins) == 0 {
+		return u
+	}
+
+	allowed := make(map[string]struct{}, len(allowedOrigins))
+	for _, origin := range allowedOrigins {
+		allowed[strings.TrimRight(strings.TrimSpace(origin), "/")] = struct{}{}
+	}
+	u.CheckOrigin = func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			// ブラウザ以外のクライアントは Origin を送らない
+			return true
+		}
+		if _, ok := allowed[origin]; ok {
+			return true
+		}
+		slog.Warn("WebSocket origin rejected", "origin", origin)
+		return false
+	}
+	return u
 }
 
+// WebSocket で受け付けるフレームの最大サイズ (バイト)
+const wsMaxMessageSize = 4096
+
 type NotificationHandler struct {
-	svc service.NotificationService
-	hub *service.NotificationHub
+	svc      service.NotificationService
+	taskSvc  service.TaskService // subscribe_task の所有者チェックに使用
+	actions  service.NotificationActionService
+	hub      *service.NotificationHub
+	tickets  service.WSTicketService
+	upgrader websocket.Upgrader
 }
 
-func NewNotificationHandler(svc service.NotificationService, hub *service.NotificationHub) *NotificationHandler {
-	return &NotificationHandler{svc: svc, hub: hub}
+// NewNotificationHandler は NotificationHandler を作成します
+// allowedOrigins は WebSocket 接続を許可する Origin の一覧です (WS_ALLOWED_ORIGINS)
+func NewNotificationHandler(svc service.NotificationService, taskSvc service.TaskService, actions service.NotificationActionService, hub *service.NotificationHub, tickets service.WSTicketService, allowedOrigins []string) *NotificationHandler {
+	return &NotificationHandler{
+		svc:      svc,
+		taskSvc:  taskSvc,
+		actions:  actions,
+		hub:      hub,
+		tickets:  tickets,
+		upgrader: newUpgrader(allowedOrigins),
+	}
 }
 
 // handleError: 他のハンドラーと共通のエラーハンドリング方針
@@ -47,9 +86,16 @@ func (h *NotificationHandler) handleError(c *gin.Context, err error) {
 	case errors.Is(err, apperr.ErrUnauthorized):
 		status = http.StatusUnauthorized
 		msg = "認証が必要です"
+	case errors.Is(err, apperr.ErrForbidden):
+		slog.Warn("Authorization violation attempt", "error", err)
+		status = http.StatusForbidden
+		msg = "この操作を行う権限がありません"
 	case errors.Is(err, apperr.ErrValidation):
 		status = http.StatusBadRequest
 		msg = "リクエストが不正です"
+	case errors.Is(err, apperr.ErrConflict):
+		status = http.StatusConflict
+		msg = "タスクが同時に更新されました。やり直してください"
 	default:
 		slog.Error("Notification handler error", "error", err)
 		status = http.StatusInternalServerError
@@ -62,7 +108,7 @@ func (h *NotificationHandler) handleError(c *gin.Context, err error) {
 // HandleWS WebSocket接続の受付
 func (h *NotificationHandler) HandleWS(c *gin.Context) {
 	// 1. 認証チェック
-	// AuthMiddlewareが正常に機能し、コンテキストにuserIDが入っていることが前提です
+	// WSAuthMiddlewareが正常に機能し、コンテキストにuserIDが入っていることが前提です
 	val, ok := c.Get("userID")
 	if !ok {
 		slog.Warn("WebSocket unauthorized: userID not found in context")
@@ -73,35 +119,77 @@ func (h *NotificationHandler) HandleWS(c *gin.Context) {
 	userID := val.(uuid.UUID)
 
 	// 2. アップグレード
-	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
 	if err != nil {
 		slog.Error("WebSocket upgrade failed", "error", err)
 		return
 	}
 
 	// 3. Hubへの登録
-	h.hub.Register <- &service.ClientRegistration{
-		UserID: userID,
-		Conn:   conn,
+	client := service.NewClient(userID, conn)
+	if !h.hub.Join(client) {
+		// サーバーの停止中は新しい接続を受け付けない
+		client.CloseGoingAway()
+		client.Close()
+		return
 	}
 	slog.Info("User registered to Hub", "userID", userID)
 
 	defer func() {
-		h.hub.Unregister <- userID
+		h.hub.Leave(client)
 		slog.Info("User connection closed", "userID", userID)
-		conn.Close()
 	}()
 
-	// 4. メッセージ待機ループ (接続維持のため)
+	// 4. コマンド受信ループ
+	// プロトコルの詳細は docs/websocket-protocol.md を参照
+	conn.SetReadLimit(wsMaxMessageSize)
+	ctx := c.Request.Context()
 	for {
-		if _, _, err := conn.ReadMessage(); err != nil {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			break
+		}
+
+		var reply *models.WSEnvelope
+		cmd, err := decodeWSCommand(data)
+		if err == nil {
+			reply, err = h.dispatchWSCommand(ctx, client, cmd)
+		}
+		if err != nil {
+			var replyTo string
+			if cmd != nil {
+				replyTo = cmd.ID
+			}
+			reply = wsErrorEnvelope(replyTo, err)
+		}
+
+		if err := client.Send(reply); err != nil {
+			slog.Error("Failed to write WebSocket reply", "userID", userID, "error", err)
 			break
 		}
 	}
 }
 
+// IssueWSTicket は WebSocket 接続用の使い捨てチケットを発行します
+// POST /ws/ticket
+func (h *NotificationHandler) IssueWSTicket(c *gin.Context) {
+	userID := getUserIDFromContext(c)
+	if userID == uuid.Nil {
+		h.handleError(c, apperr.ErrUnauthorized)
+		return
+	}
+
+	ticket, expiresAt, err := h.tickets.Issue(c.Request.Context(), userID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"ticket": ticket, "expires_at": expiresAt})
+}
+
 // GetNotifications はログインユーザーの通知一覧を取得します
-// GET /notifications?page=1
+// GET /notifications?type=task_deadline&is_read=false&task_id=<uuid>&limit=20&cursor=<next_cursor>
 func (h *NotificationHandler) GetNotifications(c *gin.Context) {
 	userID, ok := c.Get("userID")
 	if !ok {
@@ -109,15 +197,132 @@ func (h *NotificationHandler) GetNotifications(c *gin.Context) {
 		return
 	}
 
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	var query models.NotificationListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		h.handleError(c, fmt.Errorf("%w: %v", apperr.ErrValidation, err))
+		return
+	}
+
+	page, err := h.svc.GetNotifications(c.Request.Context(), userID.(uuid.UUID), &query)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// GetUnreadCount は未読件数を返します
+// GET /notifications/unread-count
+func (h *NotificationHandler) GetUnreadCount(c *gin.Context) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		h.handleError(c, apperr.ErrUnauthorized)
+		return
+	}
+
+	count, err := h.svc.UnreadCount(c.Request.Context(), userID.(uuid.UUID))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"unread_count": count})
+}
+
+// MarkAllAsRead は未読の通知をすべて既読にします
+// POST /notifications/read-all
+func (h *NotificationHandler) MarkAllAsRead(c *gin.Context) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		h.handleError(c, apperr.ErrUnauthorized)
+		return
+	}
+
+	updated, err := h.svc.MarkAllAsRead(c.Request.Context(), userID.(uuid.UUID))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"updated": updated})
+}
+
+// DeleteNotification は特定の通知を削除します
+// DELETE /notifications/:id
+func (h *NotificationHandler) DeleteNotification(c *gin.Context) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		h.handleError(c, apperr.ErrUnauthorized)
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		h.handleError(c, fmt.Errorf("%w: invalid notification id", apperr.ErrValidation))
+		return
+	}
+
+	if err := h.svc.Delete(c.Request.Context(), id, userID.(uuid.UUID)); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Snooze は通知をスヌーズし、指定時刻に再配信させます
+// POST /notifications/:id/snooze
+func (h *NotificationHandler) Snooze(c *gin.Context) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		h.handleError(c, apperr.ErrUnauthorized)
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		h.handleError(c, fmt.Errorf("%w: invalid notification id", apperr.ErrValidation))
+		return
+	}
+
+	var req models.SnoozeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.handleError(c, fmt.Errorf("%w: %v", apperr.ErrValidation, err))
+		return
+	}
+
+	until, err := h.svc.Snooze(c.Request.Context(), id, userID.(uuid.UUID), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"id": id, "snoozed_until": until})
+}
+
+// PerformAction は通知に付いた操作 (complete_task / snooze_1h / open_task) を実行し、通知を既読にします
+// POST /notifications/:id/actions/:action
+func (h *NotificationHandler) PerformAction(c *gin.Context) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		h.handleError(c, apperr.ErrUnauthorized)
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		h.handleError(c, fmt.Errorf("%w: invalid notification id", apperr.ErrValidation))
+		return
+	}
 
-	notifications, err := h.svc.GetNotifications(c.Request.Context(), userID.(uuid.UUID), page)
+	result, err := h.actions.Perform(c.Request.Context(), userID.(uuid.UUID), id, c.Param("action"))
 	if err != nil {
 		h.handleError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, notifications)
+	c.JSON(http.StatusOK, result)
 }
 
 // MarkAsRead は特定の通知を既読にします
@@ -0,0 +1,88 @@
+// internal/app/handler/notification_preference_handler.go
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"my-portfolio-2025/internal/app/apperr"
+	"my-portfolio-2025/internal/app/models"
+	"my-portfolio-2025/internal/app/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// NotificationPreferenceHandler は通知設定のエンドポイントを処理します
+type NotificationPreferenceHandler struct {
+	prefService service.NotificationPreferenceService
+}
+
+// NewNotificationPreferenceHandler は NotificationPreferenceHandler の新しいインスタンスを作成します
+func NewNotificationPreferenceHandler(s service.NotificationPreferenceService) *NotificationPreferenceHandler {
+	return &NotificationPreferenceHandler{prefService: s}
+}
+
+// handleError: 他のハンドラーと共通のエラーハンドリング方針
+func (h *NotificationPreferenceHandler) handleError(c *gin.Context, err error) {
+	var status int
+	var msg string
+
+	switch {
+	case errors.Is(err, apperr.ErrValidation):
+		status = http.StatusBadRequest
+		msg = err.Error()
+	case errors.Is(err, apperr.ErrUnauthorized):
+		status = http.StatusUnauthorized
+		msg = "認証が必要です"
+	default:
+		slog.Error("Notification preference handler error", "error", err)
+		status = http.StatusInternalServerError
+		msg = "サーバー内部エラーが発生しました"
+	}
+
+	c.JSON(status, gin.H{"error": msg})
+}
+
+// Get は通知設定を返します
+// GET /notifications/preferences
+func (h *NotificationPreferenceHandler) Get(c *gin.Context) {
+	userID := getUserIDFromContext(c)
+	if userID == uuid.Nil {
+		h.handleError(c, apperr.ErrUnauthorized)
+		return
+	}
+
+	pref, err := h.prefService.Get(c.Request.Context(), userID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, pref)
+}
+
+// Update は通知設定を更新します
+// PUT /notifications/preferences
+func (h *NotificationPreferenceHandler) Update(c *gin.Context) {
+	userID := getUserIDFromContext(c)
+	if userID == uuid.Nil {
+		h.handleError(c, apperr.ErrUnauthorized)
+		return
+	}
+
+	var req models.NotificationPreferenceUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.handleError(c, fmt.Errorf("%w: %v", apperr.ErrValidation, err))
+		return
+	}
+
+	pref, err := h.prefService.Update(c.Request.Context(), userID, &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, pref)
+}
@@ -0,0 +1,108 @@
+// internal/app/handler/push_handler.go
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"my-portfolio-2025/internal/app/apperr"
+	"my-portfolio-2025/internal/app/models"
+	"my-portfolio-2025/internal/app/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// PushHandler は Web Push の購読エンドポイントを処理します
+type PushHandler struct {
+	pushService service.PushService
+}
+
+// NewPushHandler は PushHandler の新しいインスタンスを作成します
+func NewPushHandler(s service.PushService) *PushHandler {
+	return &PushHandler{pushService: s}
+}
+
+// handleError: 他のハンドラーと共通のエラーハンドリング方針
+func (h *PushHandler) handleError(c *gin.Context, err error) {
+	var status int
+	var msg string
+
+	switch {
+	case errors.Is(err, apperr.ErrValidation):
+		status = http.StatusBadRequest
+		msg = err.Error()
+	case errors.Is(err, apperr.ErrNotFound):
+		status = http.StatusNotFound
+		msg = "Web Push の購読が見つからないか、Web Push が無効です"
+	case errors.Is(err, apperr.ErrUnauthorized):
+		status = http.StatusUnauthorized
+		msg = "認証が必要です"
+	default:
+		slog.Error("Push handler error", "error", err)
+		status = http.StatusInternalServerError
+		msg = "サーバー内部エラーが発生しました"
+	}
+
+	c.JSON(status, gin.H{"error": msg})
+}
+
+// PublicKey はブラウザの pushManager.subscribe に渡す VAPID 公開鍵を返します
+// GET /push/vapid-public-key
+func (h *PushHandler) PublicKey(c *gin.Context) {
+	key, err := h.pushService.PublicKey()
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"public_key": key})
+}
+
+// Subscribe はブラウザの購読 (PushSubscription.toJSON()) を保存します
+// POST /push/subscriptions
+func (h *PushHandler) Subscribe(c *gin.Context) {
+	userID := getUserIDFromContext(c)
+	if userID == uuid.Nil {
+		h.handleError(c, apperr.ErrUnauthorized)
+		return
+	}
+
+	var req models.PushSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.handleError(c, fmt.Errorf("%w: %v", apperr.ErrValidation, err))
+		return
+	}
+
+	sub, err := h.pushService.Subscribe(c.Request.Context(), userID, &req, c.Request.UserAgent())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, sub)
+}
+
+// Unsubscribe はブラウザの購読を削除します
+// DELETE /push/subscriptions
+func (h *PushHandler) Unsubscribe(c *gin.Context) {
+	userID := getUserIDFromContext(c)
+	if userID == uuid.Nil {
+		h.handleError(c, apperr.ErrUnauthorized)
+		return
+	}
+
+	var req models.PushUnsubscribeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.handleError(c, fmt.Errorf("%w: %v", apperr.ErrValidation, err))
+		return
+	}
+
+	if err := h.pushService.Unsubscribe(c.Request.Context(), userID, req.Endpoint); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,108 @@
+// internal/app/handler/reminder_handler.go
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"my-portfolio-2025/internal/app/apperr"
+	"my-portfolio-2025/internal/app/models"
+	"my-portfolio-2025/internal/app/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ReminderHandler はタスクのリマインダー設定エンドポイントを処理します
+type ReminderHandler struct {
+	reminderService service.ReminderService
+}
+
+// NewReminderHandler は ReminderHandler の新しいインスタンスを作成します
+func NewReminderHandler(s service.ReminderService) *ReminderHandler {
+	return &ReminderHandler{reminderService: s}
+}
+
+// handleError: TaskHandler と同じエラーハンドリング方針
+func (h *ReminderHandler) handleError(c *gin.Context, err error) {
+	var status int
+	var msg string
+
+	switch {
+	case errors.Is(err, apperr.ErrNotFound):
+		status = http.StatusNotFound
+		msg = "指定されたタスクが見つかりません"
+	case errors.Is(err, apperr.ErrForbidden):
+		slog.Warn("Authorization violation attempt", "error", err)
+		status = http.StatusForbidden
+		msg = "この操作を行う権限がありません"
+	case errors.Is(err, apperr.ErrValidation):
+		status = http.StatusBadRequest
+		msg = err.Error()
+	case errors.Is(err, apperr.ErrUnauthorized):
+		status = http.StatusUnauthorized
+		msg = "認証が必要です"
+	default:
+		slog.Error("Reminder handler error", "error", err)
+		status = http.StatusInternalServerError
+		msg = "サーバー内部でエラーが発生しました"
+	}
+
+	c.JSON(status, gin.H{"error": msg})
+}
+
+// parseIDs はログインユーザーとパスの :id (タスクID) を取り出します
+func (h *ReminderHandler) parseIDs(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	userID := getUserIDFromContext(c)
+	if userID == uuid.Nil {
+		h.handleError(c, apperr.ErrUnauthorized)
+		return uuid.Nil, uuid.Nil, false
+	}
+	taskID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		h.handleError(c, fmt.Errorf("%w: invalid task id", apperr.ErrValidation))
+		return uuid.Nil, uuid.Nil, false
+	}
+	return userID, taskID, true
+}
+
+// List はタスクのリマインダーを返します
+// GET /tasks/:id/reminders
+func (h *ReminderHandler) List(c *gin.Context) {
+	userID, taskID, ok := h.parseIDs(c)
+	if !ok {
+		return
+	}
+
+	reminders, err := h.reminderService.List(c.Request.Context(), userID, taskID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, reminders)
+}
+
+// Replace はタスクのリマインダーを置き換えます
+// PUT /tasks/:id/reminders
+func (h *ReminderHandler) Replace(c *gin.Context) {
+	userID, taskID, ok := h.parseIDs(c)
+	if !ok {
+		return
+	}
+
+	var req models.TaskReminderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.handleError(c, fmt.Errorf("%w: %v", apperr.ErrValidation, err))
+		return
+	}
+
+	reminders, err := h.reminderService.Replace(c.Request.Context(), userID, taskID, req.Offsets)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, reminders)
+}
@@ -0,0 +1,79 @@
+// internal/app/handler/scheduled_job_handler.go
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"my-portfolio-2025/internal/app/apperr"
+	"my-portfolio-2025/internal/app/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ScheduledJobHandler は定期ジョブの管理エンドポイント (/admin/jobs) を処理します
+type ScheduledJobHandler struct {
+	schedulerService service.SchedulerService
+}
+
+// NewScheduledJobHandler は ScheduledJobHandler の新しいインスタンスを作成します
+func NewScheduledJobHandler(s service.SchedulerService) *ScheduledJobHandler {
+	return &ScheduledJobHandler{schedulerService: s}
+}
+
+// handleError: 他のハンドラーと共通のエラーハンドリング方針
+func (h *ScheduledJobHandler) handleError(c *gin.Context, err error) {
+	var status int
+	var msg string
+
+	switch {
+	case errors.Is(err, apperr.ErrNotFound):
+		status = http.StatusNotFound
+		msg = "指定された定期ジョブが見つかりません"
+	default:
+		slog.Error("Scheduled job handler error", "error", err)
+		status = http.StatusInternalServerError
+		msg = "サーバー内部エラーが発生しました"
+	}
+
+	c.JSON(status, gin.H{"error": msg})
+}
+
+// List は登録されている定期ジョブと次の実行時刻・最後の実行を返します
+// GET /admin/jobs
+func (h *ScheduledJobHandler) List(c *gin.Context) {
+	jobs, err := h.schedulerService.Jobs(c.Request.Context())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"jobs": jobs})
+}
+
+// Runs は定期ジョブの実行履歴を新しい順に返します
+// GET /admin/jobs/:name/runs?limit=20
+func (h *ScheduledJobHandler) Runs(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	runs, err := h.schedulerService.Runs(c.Request.Context(), c.Param("name"), limit)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"runs": runs})
+}
+
+// Trigger は定期ジョブの手動実行を依頼します。実行はワーカーが行うため 202 を返す
+// POST /admin/jobs/:name/run
+func (h *ScheduledJobHandler) Trigger(c *gin.Context) {
+	run, err := h.schedulerService.Trigger(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, run)
+}
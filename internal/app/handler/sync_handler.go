@@ -0,0 +1,88 @@
+// internal/app/handler/sync_handler.go
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"my-portfolio-2025/internal/app/apperr"
+	"my-portfolio-2025/internal/app/models"
+	"my-portfolio-2025/internal/app/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// SyncHandler はオフラインクライアント向けの差分同期エンドポイントを処理します
+type SyncHandler struct {
+	syncService service.SyncService
+}
+
+// NewSyncHandler は SyncHandler の新しいインスタンスを作成します
+func NewSyncHandler(s service.SyncService) *SyncHandler {
+	return &SyncHandler{syncService: s}
+}
+
+// handleError: 他のハンドラーと共通のエラーハンドリング方針
+func (h *SyncHandler) handleError(c *gin.Context, err error) {
+	var status int
+	var msg string
+
+	switch {
+	case errors.Is(err, apperr.ErrValidation):
+		status = http.StatusBadRequest
+		msg = err.Error()
+	case errors.Is(err, apperr.ErrUnauthorized):
+		status = http.StatusUnauthorized
+		msg = "認証が必要です"
+	default:
+		slog.Error("Sync handler error", "error", err)
+		status = http.StatusInternalServerError
+		msg = "サーバー内部エラーが発生しました"
+	}
+
+	c.JSON(status, gin.H{"error": msg})
+}
+
+// Pull は since トークン以降の差分を返します
+// GET /sync?since=<token>
+func (h *SyncHandler) Pull(c *gin.Context) {
+	userID := getUserIDFromContext(c)
+	if userID == uuid.Nil {
+		h.handleError(c, apperr.ErrUnauthorized)
+		return
+	}
+
+	resp, err := h.syncService.Pull(c.Request.Context(), userID, c.Query("since"))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// Push はオフライン中の変更をまとめて適用します
+// POST /sync
+func (h *SyncHandler) Push(c *gin.Context) {
+	userID := getUserIDFromContext(c)
+	if userID == uuid.Nil {
+		h.handleError(c, apperr.ErrUnauthorized)
+		return
+	}
+
+	var req models.SyncPushRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.handleError(c, fmt.Errorf("%w: %v", apperr.ErrValidation, err))
+		return
+	}
+
+	resp, err := h.syncService.Push(c.Request.Context(), userID, &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
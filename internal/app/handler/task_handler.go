@@ -43,6 +43,9 @@ func (h *TaskHandler) handleError(c *gin.Context, err error) {
 	case errors.Is(err, apperr.ErrValidation):
 		status = http.StatusBadRequest
 		msg = err.Error() // バリデーション内容はユーザーに伝える
+	case errors.Is(err, apperr.ErrConflict):
+		status = http.StatusConflict
+		msg = "タスクが同時に更新されました。再読み込みしてからやり直してください"
 	case errors.Is(err, apperr.ErrUnauthorized):
 		status = http.StatusUnauthorized
 		msg = "認証が必要です"
@@ -157,6 +160,34 @@ func (h *TaskHandler) UpdateTask(c *gin.Context) {
 	c.JSON(http.StatusOK, updatedTask)
 }
 
+// SnoozeTask: POST /tasks/:id/snooze (スヌーズ)
+func (h *TaskHandler) SnoozeTask(c *gin.Context) {
+	userID := getUserIDFromContext(c)
+	if userID == uuid.Nil {
+		h.handleError(c, apperr.ErrUnauthorized)
+		return
+	}
+	taskID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		h.handleError(c, fmt.Errorf("%w: invalid UUID", apperr.ErrValidation))
+		return
+	}
+
+	var req models.SnoozeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.handleError(c, fmt.Errorf("%w: %v", apperr.ErrValidation, err))
+		return
+	}
+
+	task, err := h.taskService.SnoozeTask(userID, taskID, &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, task)
+}
+
 // DeleteTask: DELETE /tasks/:id (削除)
 func (h *TaskHandler) DeleteTask(c *gin.Context) {
 
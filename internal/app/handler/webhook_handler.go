@@ -0,0 +1,198 @@
+// internal/app/handler/webhook_handler.go
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"my-portfolio-2025/internal/app/apperr"
+	"my-portfolio-2025/internal/app/models"
+	"my-portfolio-2025/internal/app/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// WebhookHandler は Webhook の購読管理エンドポイントを処理します
+type WebhookHandler struct {
+	webhookService service.WebhookService
+}
+
+// NewWebhookHandler は WebhookHandler の新しいインスタンスを作成します
+func NewWebhookHandler(s service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{webhookService: s}
+}
+
+// handleError: 他のハンドラーと共通のエラーハンドリング方針
+func (h *WebhookHandler) handleError(c *gin.Context, err error) {
+	var status int
+	var msg string
+
+	switch {
+	case errors.Is(err, apperr.ErrValidation):
+		status = http.StatusBadRequest
+		msg = err.Error()
+	case errors.Is(err, apperr.ErrNotFound):
+		status = http.StatusNotFound
+		msg = "指定された Webhook が見つかりません"
+	case errors.Is(err, apperr.ErrUnauthorized):
+		status = http.StatusUnauthorized
+		msg = "認証が必要です"
+	default:
+		slog.Error("Webhook handler error", "error", err)
+		status = http.StatusInternalServerError
+		msg = "サーバー内部エラーが発生しました"
+	}
+
+	c.JSON(status, gin.H{"error": msg})
+}
+
+// parseIDs はログインユーザーとパスの :id を取り出します
+func (h *WebhookHandler) parseIDs(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	userID := getUserIDFromContext(c)
+	if userID == uuid.Nil {
+		h.handleError(c, apperr.ErrUnauthorized)
+		return uuid.Nil, uuid.Nil, false
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		h.handleError(c, fmt.Errorf("%w: invalid webhook id", apperr.ErrValidation))
+		return uuid.Nil, uuid.Nil, false
+	}
+	return userID, id, true
+}
+
+// Create は Webhook を登録します。署名用シークレットはこのレスポンスでのみ返します
+// POST /webhooks
+func (h *WebhookHandler) Create(c *gin.Context) {
+	userID := getUserIDFromContext(c)
+	if userID == uuid.Nil {
+		h.handleError(c, apperr.ErrUnauthorized)
+		return
+	}
+
+	var req models.WebhookSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.handleError(c, fmt.Errorf("%w: %v", apperr.ErrValidation, err))
+		return
+	}
+
+	created, err := h.webhookService.Create(c.Request.Context(), userID, &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, created)
+}
+
+// List は登録済みの Webhook 一覧を返します
+// GET /webhooks
+func (h *WebhookHandler) List(c *gin.Context) {
+	userID := getUserIDFromContext(c)
+	if userID == uuid.Nil {
+		h.handleError(c, apperr.ErrUnauthorized)
+		return
+	}
+
+	subs, err := h.webhookService.List(c.Request.Context(), userID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, subs)
+}
+
+// Get は Webhook を1件返します
+// GET /webhooks/:id
+func (h *WebhookHandler) Get(c *gin.Context) {
+	userID, id, ok := h.parseIDs(c)
+	if !ok {
+		return
+	}
+
+	sub, err := h.webhookService.Get(c.Request.Context(), userID, id)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, sub)
+}
+
+// Update は Webhook を更新します
+// PUT /webhooks/:id
+func (h *WebhookHandler) Update(c *gin.Context) {
+	userID, id, ok := h.parseIDs(c)
+	if !ok {
+		return
+	}
+
+	var req models.WebhookSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.handleError(c, fmt.Errorf("%w: %v", apperr.ErrValidation, err))
+		return
+	}
+
+	sub, err := h.webhookService.Update(c.Request.Context(), userID, id, &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, sub)
+}
+
+// Delete は Webhook を削除します
+// DELETE /webhooks/:id
+func (h *WebhookHandler) Delete(c *gin.Context) {
+	userID, id, ok := h.parseIDs(c)
+	if !ok {
+		return
+	}
+
+	if err := h.webhookService.Delete(c.Request.Context(), userID, id); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListDeliveries は配信ログを新しい順に返します
+// GET /webhooks/:id/deliveries?limit=50
+func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
+	userID, id, ok := h.parseIDs(c)
+	if !ok {
+		return
+	}
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	deliveries, err := h.webhookService.ListDeliveries(c.Request.Context(), userID, id, limit)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, deliveries)
+}
+
+// SendTest はテストイベントを送信し、その結果を返します
+// POST /webhooks/:id/test
+func (h *WebhookHandler) SendTest(c *gin.Context) {
+	userID, id, ok := h.parseIDs(c)
+	if !ok {
+		return
+	}
+
+	delivery, err := h.webhookService.SendTest(c.Request.Context(), userID, id)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, delivery)
+}
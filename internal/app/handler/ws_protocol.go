@@ -0,0 +1,136 @@
+// internal/app/handler/ws_protocol.go
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"my-portfolio-2025/internal/app/apperr"
+	"my-portfolio-2025/internal/app/models"
+	"my-portfolio-2025/internal/app/service"
+
+	"github.com/google/uuid"
+)
+
+// wsProtocolError はクライアントへ error イベントとして返すエラーです
+type wsProtocolError struct {
+	Code    string
+	Message string
+}
+
+func (e *wsProtocolError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+// decodeWSCommand はクライアントから受信したフレームを検証し、エンベロープに変換します
+// ペイロードの中身はコマンドごとに dispatchWSCommand で検証します
+func decodeWSCommand(data []byte) (*models.WSEnvelope, error) {
+	var env models.WSEnvelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, &wsProtocolError{Code: models.WSErrorInvalidJSON, Message: "JSONの形式が不正です"}
+	}
+
+	if env.Version != models.WSProtocolVersion {
+		return &env, &wsProtocolError{
+			Code:    models.WSErrorUnsupportedVersion,
+			Message: fmt.Sprintf("サポートしていないプロトコルバージョンです (v=%d)", env.Version),
+		}
+	}
+
+	switch env.Type {
+	case models.WSCommandAck, models.WSCommandMarkRead, models.WSCommandSubscribeTask, models.WSCommandPing:
+		return &env, nil
+	default:
+		return &env, &wsProtocolError{
+			Code:    models.WSErrorUnknownCommand,
+			Message: fmt.Sprintf("不明なコマンドです (type=%q)", env.Type),
+		}
+	}
+}
+
+// decodeWSNotificationRef は ack / mark_read のペイロードを取り出します
+func decodeWSNotificationRef(env *models.WSEnvelope) (uuid.UUID, error) {
+	var p models.WSNotificationRefPayload
+	if err := json.Unmarshal(env.Payload, &p); err != nil || p.NotificationID == uuid.Nil {
+		return uuid.Nil, &wsProtocolError{Code: models.WSErrorInvalidPayload, Message: "notification_id が必要です"}
+	}
+	return p.NotificationID, nil
+}
+
+// decodeWSSubscribeTask は subscribe_task のペイロードを取り出します
+func decodeWSSubscribeTask(env *models.WSEnvelope) (uuid.UUID, error) {
+	var p models.WSSubscribeTaskPayload
+	if err := json.Unmarshal(env.Payload, &p); err != nil || p.TaskID == uuid.Nil {
+		return uuid.Nil, &wsProtocolError{Code: models.WSErrorInvalidPayload, Message: "task_id が必要です"}
+	}
+	return p.TaskID, nil
+}
+
+// dispatchWSCommand は検証済みのコマンドを各サービスへ振り分け、応答イベントを返します
+func (h *NotificationHandler) dispatchWSCommand(ctx context.Context, client *service.Client, env *models.WSEnvelope) (*models.WSEnvelope, error) {
+	switch env.Type {
+	case models.WSCommandPing:
+		return models.NewWSEnvelope(models.WSEventPong, env.ID, nil)
+
+	case models.WSCommandAck:
+		id, err := decodeWSNotificationRef(env)
+		if err != nil {
+			return nil, err
+		}
+		if err := h.svc.Acknowledge(ctx, id, client.UserID); err != nil {
+			return nil, err
+		}
+		return models.NewWSEnvelope(models.WSEventResult, env.ID, nil)
+
+	case models.WSCommandMarkRead:
+		id, err := decodeWSNotificationRef(env)
+		if err != nil {
+			return nil, err
+		}
+		if err := h.svc.MarkAsRead(ctx, id, client.UserID); err != nil {
+			return nil, err
+		}
+		return models.NewWSEnvelope(models.WSEventResult, env.ID, nil)
+
+	case models.WSCommandSubscribeTask:
+		taskID, err := decodeWSSubscribeTask(env)
+		if err != nil {
+			return nil, err
+		}
+		// GetTaskByID で存在確認と所有者チェックを行う
+		task, err := h.taskSvc.GetTaskByID(client.UserID, taskID)
+		if err != nil {
+			return nil, err
+		}
+		client.SubscribeTask(taskID)
+		// 購読開始時点のスナップショットを返し、クライアントの状態を揃える
+		return models.NewWSEnvelope(models.WSEventTaskUpdated, env.ID, models.WSTaskEventPayload{TaskID: task.ID, Version: task.Version, Task: task})
+	}
+
+	return nil, &wsProtocolError{Code: models.WSErrorUnknownCommand, Message: "不明なコマンドです"}
+}
+
+// wsErrorEnvelope はエラーを error イベントに変換します
+// HTTPの handleError と同じく、内部エラーの詳細はクライアントに返しません
+func wsErrorEnvelope(replyTo string, err error) *models.WSEnvelope {
+	payload := models.WSErrorPayload{Code: models.WSErrorInternal, Message: "サーバー内部エラーが発生しました"}
+
+	var protoErr *wsProtocolError
+	switch {
+	case errors.As(err, &protoErr):
+		payload = models.WSErrorPayload{Code: protoErr.Code, Message: protoErr.Message}
+	case errors.Is(err, apperr.ErrNotFound):
+		payload = models.WSErrorPayload{Code: models.WSErrorNotFound, Message: "指定されたリソースが見つかりません"}
+	case errors.Is(err, apperr.ErrForbidden):
+		payload = models.WSErrorPayload{Code: models.WSErrorForbidden, Message: "この操作を行う権限がありません"}
+	case errors.Is(err, apperr.ErrValidation):
+		payload = models.WSErrorPayload{Code: models.WSErrorInvalidPayload, Message: "リクエストが不正です"}
+	default:
+		slog.Error("WebSocket command error", "error", err)
+	}
+
+	env, _ := models.NewWSEnvelope(models.WSEventError, replyTo, payload)
+	return env
+}
@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"my-portfolio-2025/internal/app/apperr"
+	"my-portfolio-2025/internal/app/models"
+	"my-portfolio-2025/internal/app/service"
+	"my-portfolio-2025/internal/testutils/mock"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeWSCommand(t *testing.T) {
+	tests := []struct {
+		name         string
+		frame        string
+		expectedCode string // 空なら正常系
+	}{
+		{name: "正常系：ping", frame: `{"v":1,"type":"ping","id":"1"}`},
+		{name: "正常系：mark_read", frame: `{"v":1,"type":"mark_read","payload":{"notification_id":"` + uuid.NewString() + `"}}`},
+		{name: "異常系：JSONが壊れている", frame: `{"v":1,`, expectedCode: models.WSErrorInvalidJSON},
+		{name: "異常系：バージョン違い", frame: `{"v":2,"type":"ping"}`, expectedCode: models.WSErrorUnsupportedVersion},
+		{name: "異常系：不明なコマンド", frame: `{"v":1,"type":"shutdown"}`, expectedCode: models.WSErrorUnknownCommand},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env, err := decodeWSCommand([]byte(tt.frame))
+			if tt.expectedCode == "" {
+				require.NoError(t, err)
+				assert.NotNil(t, env)
+				return
+			}
+
+			var protoErr *wsProtocolError
+			require.ErrorAs(t, err, &protoErr)
+			assert.Equal(t, tt.expectedCode, protoErr.Code)
+		})
+	}
+}
+
+func TestDispatchWSCommand_SubscribeTask(t *testing.T) {
+	userID := uuid.New()
+	taskID := uuid.New()
+
+	tests := []struct {
+		name          string
+		payload       string
+		setupMock     func(m *mock.TaskServiceMock)
+		expectedType  string
+		expectedError string
+	}{
+		{
+			name:    "正常系：自分のタスクを購読するとスナップショットが返る",
+			payload: fmt.Sprintf(`{"task_id":"%s"}`, taskID),
+			setupMock: func(m *mock.TaskServiceMock) {
+				m.On("GetTaskByID", userID, taskID).Return(&models.Task{ID: taskID, UserID: userID}, nil)
+			},
+			expectedType: models.WSEventTaskUpdated,
+		},
+		{
+			name:    "異常系：他人のタスクは購読できない",
+			payload: fmt.Sprintf(`{"task_id":"%s"}`, taskID),
+			setupMock: func(m *mock.TaskServiceMock) {
+				m.On("GetTaskByID", userID, taskID).Return(nil, apperr.ErrForbidden)
+			},
+			expectedError: models.WSErrorForbidden,
+		},
+		{
+			name:          "異常系：task_id が無い",
+			payload:       `{}`,
+			setupMock:     func(m *mock.TaskServiceMock) {},
+			expectedError: models.WSErrorInvalidPayload,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			taskSvc := new(mock.TaskServiceMock)
+			tt.setupMock(taskSvc)
+			h := NewNotificationHandler(nil, taskSvc, nil, nil, nil, nil)
+			client := service.NewClient(userID, nil)
+
+			cmd := &models.WSEnvelope{
+				Version: models.WSProtocolVersion,
+				Type:    models.WSCommandSubscribeTask,
+				ID:      "req-1",
+				Payload: json.RawMessage(tt.payload),
+			}
+			reply, err := h.dispatchWSCommand(context.Background(), client, cmd)
+
+			if tt.expectedError != "" {
+				require.Error(t, err)
+				errEnv := wsErrorEnvelope(cmd.ID, err)
+				var payload models.WSErrorPayload
+				require.NoError(t, json.Unmarshal(errEnv.Payload, &payload))
+				assert.Equal(t, tt.expectedError, payload.Code)
+				assert.Equal(t, "req-1", errEnv.ReplyTo)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedType, reply.Type)
+			assert.Equal(t, "req-1", reply.ReplyTo)
+			taskSvc.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,36 @@
+// internal/app/middleware/admin_auth.go
+package middleware
+
+import (
+	"crypto/subtle"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminAuthMiddleware は管理用エンドポイント (/admin) の認証ミドルウェアです
+// Authorization: Bearer <ADMIN_API_TOKEN> を要求します。ユーザーの JWT では認証しない
+func AdminAuthMiddleware(token string) gin.HandlerFunc {
+	const BEARER_SCHEMA = "Bearer "
+
+	return func(c *gin.Context) {
+		authHeader := c.GetHeader("Authorization")
+		given := strings.TrimPrefix(authHeader, BEARER_SCHEMA)
+
+		// タイミング攻撃でトークンを推測されないよう、定数時間で比較する
+		if token == "" || !strings.HasPrefix(authHeader, BEARER_SCHEMA) ||
+			subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			slog.Warn("Admin authentication failed",
+				"path", c.Request.URL.Path,
+				"method", c.Request.Method,
+				"client_ip", c.ClientIP(),
+			)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "無効な管理トークンです"})
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminAuthMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		token          string
+		header         string
+		expectedStatus int
+	}{
+		{name: "正常系：管理トークンで認証できる", token: "secret", header: "Bearer secret", expectedStatus: http.StatusOK},
+		{name: "異常系：トークンが違う", token: "secret", header: "Bearer other", expectedStatus: http.StatusUnauthorized},
+		{name: "異常系：ヘッダーがない", token: "secret", header: "", expectedStatus: http.StatusUnauthorized},
+		{name: "異常系：Bearer でない", token: "secret", header: "secret", expectedStatus: http.StatusUnauthorized},
+		{name: "異常系：管理トークンが未設定なら空のトークンでも通さない", token: "", header: "Bearer ", expectedStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.GET("/admin", AdminAuthMiddleware(tt.token), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
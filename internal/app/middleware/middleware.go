@@ -19,12 +19,11 @@ func AuthMiddleware() gin.HandlerFunc {
 		var tokenString string
 
 		// 1. Authorization ヘッダーの確認
+		// JWT はアクセスログやプロキシに残らないよう、クエリパラメータでは受け付けない
+		// (WebSocket は WSAuthMiddleware のチケット方式を使う)
 		authHeader := c.GetHeader("Authorization")
 		if authHeader != "" && strings.HasPrefix(authHeader, BEARER_SCHEMA) {
 			tokenString = strings.TrimPrefix(authHeader, BEARER_SCHEMA)
-		} else {
-			// 2. クエリパラメータの確認 (WebSocket接続用)
-			tokenString = c.Query("token")
 		}
 
 		// トークンが存在しない場合
@@ -34,7 +33,7 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		// 3. トークンの検証
+		// 2. トークンの検証
 		userID, err := auth.ValidateToken(tokenString)
 		if err != nil {
 			// 認証失敗はセキュリティ監査のために Warn レベルで記録する
@@ -0,0 +1,87 @@
+// internal/app/middleware/ws_auth.go
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"my-portfolio-2025/pkg/auth"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// WSSubprotocol は WebSocket のアプリケーションプロトコル名です
+// Sec-WebSocket-Protocol でチケットを渡す場合、クライアントはこの値も一緒に提示します
+const WSSubprotocol = "kota.v1"
+
+// Sec-WebSocket-Protocol でチケットを渡すときの接頭辞 (例: "ticket.xxxx")
+const wsTicketProtocolPrefix = "ticket."
+
+// TicketRedeemer は WebSocket チケットを消費してユーザーIDを返します
+type TicketRedeemer interface {
+	Redeem(ctx context.Context, ticket string) (uuid.UUID, error)
+}
+
+// WSAuthMiddleware は /ws 用の認証ミドルウェアです
+// 次のいずれかで認証します (JWT をクエリ文字列に載せる方式は廃止)
+//  1. Authorization: Bearer <JWT>   (ネイティブクライアント向け)
+//  2. ?ticket=<ticket>              (POST /ws/ticket で発行した使い捨てチケット)
+//  3. Sec-WebSocket-Protocol: kota.v1, ticket.<ticket>
+func WSAuthMiddleware(tickets TicketRedeemer) gin.HandlerFunc {
+	const BEARER_SCHEMA = "Bearer "
+
+	return func(c *gin.Context) {
+		var (
+			userID uuid.UUID
+			err    error
+			method string
+		)
+
+		authHeader := c.GetHeader("Authorization")
+		ticket := c.Query("ticket")
+		if ticket == "" {
+			ticket = ticketFromSubprotocols(c.Request)
+		}
+
+		switch {
+		case strings.HasPrefix(authHeader, BEARER_SCHEMA):
+			method = "bearer"
+			userID, err = auth.ValidateToken(strings.TrimPrefix(authHeader, BEARER_SCHEMA))
+		case ticket != "":
+			method = "ticket"
+			userID, err = tickets.Redeem(c.Request.Context(), ticket)
+		default:
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "認証トークンが必要です"})
+			return
+		}
+
+		if err != nil {
+			slog.Warn("WebSocket authentication failed",
+				"error", err,
+				"method", method,
+				"client_ip", c.ClientIP(),
+			)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "無効なトークンです"})
+			return
+		}
+
+		c.Set("userID", userID)
+		c.Next()
+	}
+}
+
+// ticketFromSubprotocols は Sec-WebSocket-Protocol ヘッダーからチケットを取り出します
+func ticketFromSubprotocols(r *http.Request) string {
+	for _, header := range r.Header.Values("Sec-WebSocket-Protocol") {
+		for _, proto := range strings.Split(header, ",") {
+			proto = strings.TrimSpace(proto)
+			if strings.HasPrefix(proto, wsTicketProtocolPrefix) {
+				return strings.TrimPrefix(proto, wsTicketProtocolPrefix)
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeRedeemer は使い捨てチケットの挙動を再現するテスト用実装です
+type fakeRedeemer struct {
+	tickets map[string]uuid.UUID
+}
+
+func (f *fakeRedeemer) Redeem(ctx context.Context, ticket string) (uuid.UUID, error) {
+	userID, ok := f.tickets[ticket]
+	if !ok {
+		return uuid.Nil, errors.New("invalid ticket")
+	}
+	delete(f.tickets, ticket)
+	return userID, nil
+}
+
+func TestWSAuthMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	userID := uuid.New()
+
+	tests := []struct {
+		name           string
+		setupRequest   func(r *http.Request)
+		expectedStatus int
+	}{
+		{
+			name:           "正常系：クエリのチケットで認証できる",
+			setupRequest:   func(r *http.Request) { r.URL.RawQuery = "ticket=abc" },
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "正常系：Sec-WebSocket-Protocol のチケットで認証できる",
+			setupRequest: func(r *http.Request) {
+				r.Header.Set("Sec-WebSocket-Protocol", WSSubprotocol+", ticket.abc")
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "異常系：未知のチケットは401",
+			setupRequest:   func(r *http.Request) { r.URL.RawQuery = "ticket=unknown" },
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "異常系：JWTをクエリに載せても受け付けない",
+			setupRequest:   func(r *http.Request) { r.URL.RawQuery = "token=some.jwt.value" },
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			redeemer := &fakeRedeemer{tickets: map[string]uuid.UUID{"abc": userID}}
+
+			r := gin.New()
+			r.GET("/ws", WSAuthMiddleware(redeemer), func(c *gin.Context) {
+				assert.Equal(t, userID, c.MustGet("userID"))
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/ws", nil)
+			tt.setupRequest(req)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestWSAuthMiddleware_TicketIsSingleUse(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	redeemer := &fakeRedeemer{tickets: map[string]uuid.UUID{"abc": uuid.New()}}
+
+	r := gin.New()
+	r.GET("/ws", WSAuthMiddleware(redeemer), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	for i, expected := range []int{http.StatusOK, http.StatusUnauthorized} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ws?ticket=abc", nil))
+		assert.Equal(t, expected, w.Code, "attempt %d", i+1)
+	}
+}
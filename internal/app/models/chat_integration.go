@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ChatIntegrationDefaultTypes はチャット連携で既定で投稿する通知種別です
+var ChatIntegrationDefaultTypes = []string{NotificationTypeDeadline, NotificationTypeMention}
+
+// ChatIntegration はユーザーが登録したチャットツール (Slack / Teams) の Incoming Webhook です
+type ChatIntegration struct {
+	ID                uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	UserID            uuid.UUID `gorm:"type:uuid;not null;index" json:"user_id"`
+	Provider          string    `gorm:"size:20;not null" json:"provider"` // chat.ProviderSlack / chat.ProviderTeams
+	Name              string    `gorm:"size:100" json:"name"`             // 表示用の名前 (例: "#team-dev")
+	WebhookURL        string    `gorm:"type:text;not null" json:"-"`      // URL 自体が認証情報のため、レスポンスには含めない
+	NotificationTypes []string  `gorm:"type:jsonb;serializer:json;not null" json:"notification_types"`
+	Active            bool      `gorm:"not null;default:true" json:"active"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// ChatIntegrationRequest はチャット連携の作成・更新リクエストです
+// 更新時に WebhookURL を省略すると、登録済みの URL をそのまま使います
+type ChatIntegrationRequest struct {
+	Provider          string   `json:"provider" binding:"required,oneof=slack teams"`
+	Name              string   `json:"name" binding:"max=100"`
+	WebhookURL        string   `json:"webhook_url" binding:"omitempty,url"`
+	NotificationTypes []string `json:"notification_types"` // 省略時は ChatIntegrationDefaultTypes
+	Active            *bool    `json:"active"`
+}
+
+// ChatTestResult はテスト投稿の結果です
+type ChatTestResult struct {
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
+
+// BeforeCreate GORMフックで作成時にUUIDを自動生成
+func (i *ChatIntegration) BeforeCreate(tx *gorm.DB) (err error) {
+	if i.ID == uuid.Nil {
+		i.ID = uuid.New()
+	}
+	return
+}
@@ -0,0 +1,43 @@
+package models
+
+import "encoding/json"
+
+// DeadLetterMessage は DLQ に送られたメッセージです (管理用)
+type DeadLetterMessage struct {
+	ID           string `json:"id"`
+	ReceiveCount int    `json:"receive_count"`
+	// Envelope は本文をデコードしたジョブの封筒 (封筒の導入前のメッセージは本文を Payload とする封筒)
+	Envelope *JobEnvelope `json:"envelope,omitempty"`
+	// DecodeError は本文を封筒としてデコードできなかった理由
+	DecodeError string `json:"decode_error,omitempty"`
+	Body        string `json:"body"`
+}
+
+// DeadLetterList は DLQ のメッセージの一覧です
+type DeadLetterList struct {
+	// ApproximateCount は DLQ にあるメッセージのおおよその数 (Messages は最大 limit 件)
+	ApproximateCount int                 `json:"approximate_count"`
+	Messages         []DeadLetterMessage `json:"messages"`
+}
+
+// DeadLetterRedriveRequest は DLQ のメッセージをメインキューへ戻すリクエストです
+// IDs と All のどちらか一方を指定します
+type DeadLetterRedriveRequest struct {
+	IDs []string `json:"ids"`
+	All bool     `json:"all"`
+	// Patch は戻す前に本文へ適用する JSON Merge Patch (RFC 7386)。例: {"payload":{"message":"..."}}
+	Patch json.RawMessage `json:"patch,omitempty"`
+}
+
+// DeadLetterRedriveResult は DLQ のメッセージをメインキューへ戻した結果です
+type DeadLetterRedriveResult struct {
+	Redriven []string            `json:"redriven"`
+	NotFound []string            `json:"not_found,omitempty"`
+	Failed   []DeadLetterFailure `json:"failed,omitempty"`
+}
+
+// DeadLetterFailure はメインキューへ戻せなかったメッセージです (DLQ に残る)
+type DeadLetterFailure struct {
+	ID    string `json:"id"`
+	Error string `json:"error"`
+}
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ジョブ種別 (JobEnvelope.Type)
+const (
+	JobTypeNotification    = "notification"     // 通知の作成と配信 (NotificationMessage)
+	JobTypeWebhookDelivery = "webhook_delivery" // Webhook の配信 (WebhookJob)
+	JobTypeTaskEvent       = "task_event"       // タスクの変更イベントの Webhook への展開 (TaskEvent)
+)
+
+// JobEnvelopeVersion は現在の封筒の形式のバージョンです
+// Payload の形式を互換性なく変える場合に上げ、ワーカーは処理できるバージョンまでを受け付ける
+const JobEnvelopeVersion = 1
+
+// JobEnvelope はキューに流すすべてのジョブに共通の封筒です
+// ワーカーは Type でハンドラーを選び、Payload をジョブ種別ごとの型にデコードします
+type JobEnvelope struct {
+	Type    string    `json:"type"`
+	Version int       `json:"version"`
+	ID      uuid.UUID `json:"id"` // ジョブID。キューの再配信でも変わらない
+	// CorrelationID は関連するジョブを追跡するためのID (タスクID、Webhook のイベントIDなど)
+	CorrelationID string          `json:"correlation_id,omitempty"`
+	CreatedAt     time.Time       `json:"created_at"`
+	Payload       json.RawMessage `json:"payload"`
+}
+
+// NewJobEnvelope は payload を JSON にしてジョブの封筒に入れます
+func NewJobEnvelope(jobType string, correlationID string, payload interface{}, now time.Time) (*JobEnvelope, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("marshal %s job payload: %w", jobType, err)
+	}
+	return &JobEnvelope{
+		Type:          jobType,
+		Version:       JobEnvelopeVersion,
+		ID:            uuid.New(),
+		CorrelationID: correlationID,
+		CreatedAt:     now,
+		Payload:       body,
+	}, nil
+}
+
+// ProcessedJob は処理を終えたジョブの台帳です
+// SQS の標準キューは同じメッセージを複数回配信することがあるため、ジョブの結果 (通知の作成など) と同じトランザクションで記録し、
+// 2回目以降の配信では処理をスキップします。ExpiresAt を過ぎた記録は削除する
+type ProcessedJob struct {
+	// ジョブの冪等キー ("job:" + 封筒のジョブID。封筒の導入前のメッセージは "sqs:" + SQS のメッセージID)
+	Key     string `gorm:"primaryKey;size:200" json:"key"`
+	JobType string `gorm:"size:50;not null" json:"job_type"`
+	// ResultID はジョブが作成したリソースのID (通知IDなど)。再配信時に前回の結果を参照する
+	ResultID    string    `gorm:"size:100" json:"result_id,omitempty"`
+	ProcessedAt time.Time `gorm:"not null" json:"processed_at"`
+	ExpiresAt   time.Time `gorm:"not null;index" json:"expires_at"`
+}
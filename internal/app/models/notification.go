@@ -1,27 +1,137 @@
 package models
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 // Notification は通知情報を表すモデルです
 // DB保存用モデル
 type Notification struct {
-	ID        uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
-	UserID    uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
-	TaskID    *uuid.UUID `gorm:"type:uuid" json:"task_id"`     // システム通知の場合はnullを許容
-	Type      string     `gorm:"size:20;not null" json:"type"` // overdue, system など
-	Message   string     `gorm:"type:text;not null" json:"message"`
-	IsRead    bool       `gorm:"not null;default:false" json:"is_read"`
-	CreatedAt time.Time  `gorm:"not null" json:"created_at"`
+	ID      uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	UserID  uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	TaskID  *uuid.UUID `gorm:"type:uuid" json:"task_id"`     // システム通知の場合はnullを許容
+	Type    string     `gorm:"size:20;not null" json:"type"` // overdue, system など
+	Message string     `gorm:"type:text;not null" json:"message"`
+	IsRead  bool       `gorm:"not null;default:false" json:"is_read"`
+	AckedAt *time.Time `json:"acked_at"` // クライアントが受信確認(ack)した時刻
+	// 通知から直接実行できる操作 (タスクの完了・スヌーズなど)。POST /notifications/:id/actions/:action で実行する
+	Actions []NotificationAction `gorm:"type:jsonb;serializer:json" json:"actions"`
+	// おやすみ時間帯などで配信を保留している場合の配信予定時刻。配信後は NULL に戻す
+	DeferredUntil *time.Time `gorm:"index" json:"deferred_until,omitempty"`
+	// 重複排除キー (種別 + タスク + 時間枠)。同じキーの通知は1件しか作成しない。タスクに紐づかない通知は SQS のメッセージID
+	DedupKey *string `gorm:"size:191;uniqueIndex" json:"-"`
+	// リアルタイム配信 (WebSocket) を終えた時刻。SQS の再配信で未配信の通知だけを配信し直すために使用
+	DeliveredAt *time.Time `json:"-"`
+	// 一覧をタスクごとにまとめて取得した場合の、まとめられた通知の件数 (DBには保存しない)
+	GroupCount int       `gorm:"->;-:migration" json:"group_count,omitempty"`
+	CreatedAt  time.Time `gorm:"not null" json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"` // 差分同期で既読化などの変更を検知するために使用
+	// 論理削除。差分同期で tombstone として返すため物理削除はしない
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// NotificationDedupWindow は重複排除の時間枠です
+// リマインダーの offset は分単位のため、別のリマインダーが同じ枠に入ることはない
+const NotificationDedupWindow = time.Minute
+
+// NotificationDedupKey はタスクの通知の重複排除キーを返します
+// at には通知の予定時刻 (リマインダーの送信時刻など) を渡します
+func NotificationDedupKey(notificationType string, taskID uuid.UUID, at time.Time) string {
+	return fmt.Sprintf("%s:%s:%d", notificationType, taskID, at.Truncate(NotificationDedupWindow).Unix())
+}
+
+// 通知アクション
+const (
+	NotificationActionCompleteTask = "complete_task" // タスクを完了にする
+	NotificationActionSnooze1h     = "snooze_1h"     // タスクを1時間スヌーズする
+	NotificationActionOpenTask     = "open_task"     // タスクを開く (サーバーはタスクを返すだけ)
+)
+
+// NotificationAction は通知に表示する操作ボタンです
+type NotificationAction struct {
+	ID    string `json:"id"`
+	Label string `json:"label"`
+}
+
+// TaskNotificationActions はタスクに関する通知 (期限・期限切れ) の操作です
+func TaskNotificationActions() []NotificationAction {
+	return []NotificationAction{
+		{ID: NotificationActionCompleteTask, Label: "完了にする"},
+		{ID: NotificationActionSnooze1h, Label: "1時間後に再通知"},
+		{ID: NotificationActionOpenTask, Label: "タスクを開く"},
+	}
+}
+
+// HasAction は通知が指定の操作を持つかを返します
+func (n *Notification) HasAction(id string) bool {
+	for _, a := range n.Actions {
+		if a.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// NotificationActionResult は POST /notifications/:id/actions/:action のレスポンスです
+type NotificationActionResult struct {
+	NotificationID uuid.UUID `json:"notification_id"`
+	Action         string    `json:"action"`
+	Task           *Task     `json:"task"` // 操作後のタスク
 }
 
 // 配信（WebSocket/Redis）用
 type NotificationMessage struct {
-	ID      uuid.UUID `json:"id"`
-	UserID  uuid.UUID `json:"user_id"`
-	Type    string    `json:"type"`
-	Message string    `json:"message"`
+	ID      uuid.UUID            `json:"id"`
+	UserID  uuid.UUID            `json:"user_id"`
+	TaskID  *uuid.UUID           `json:"task_id,omitempty"`
+	Type    string               `json:"type"`
+	Message string               `json:"message"`
+	Actions []NotificationAction `json:"actions,omitempty"`
+	// ScheduledAt は SQS 経由の通知でのみ使用し、重複排除キーの時間枠に使う (未指定の場合は受信時刻)
+	ScheduledAt *time.Time `json:"scheduled_at,omitempty"`
+	// WithheldChannels は SQS 経由の通知でのみ使用し、指定チャネルへは配信しない (督促のエスカレーション前など)
+	WithheldChannels []string `json:"withheld_channels,omitempty"`
+}
+
+// NotificationListQuery は GET /notifications のクエリパラメータです
+type NotificationListQuery struct {
+	Type   string `form:"type"`
+	IsRead *bool  `form:"is_read"`
+	TaskID string `form:"task_id" binding:"omitempty,uuid"`
+	Cursor string `form:"cursor"` // 前回レスポンスの next_cursor
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Group  string `form:"group" binding:"omitempty,oneof=task"` // task: 同じタスクの通知を最新の1件にまとめる
+}
+
+// NotificationGroupByTask は一覧を同じタスクの通知ごとにまとめる指定です
+const NotificationGroupByTask = "task"
+
+// NotificationFilter はリポジトリで通知を絞り込む条件です
+type NotificationFilter struct {
+	Type   string
+	IsRead *bool
+	TaskID *uuid.UUID
+	Before *NotificationCursor // この位置より古い通知だけを返す
+	// この時刻より後に作成された通知だけを返す (ダイジェストの集計期間)
+	CreatedAfter *time.Time
+	// 同じタスクの通知を最新の1件にまとめ、GroupCount に件数を入れる
+	GroupByTask bool
+	Limit       int
+}
+
+// NotificationCursor は一覧の取得位置です (created_at DESC, id DESC の順で並べたときの最後の1件)
+type NotificationCursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+// NotificationPage は通知一覧の1ページ分です
+// NextCursor が空の場合、それ以上の通知はありません
+type NotificationPage struct {
+	Items      []Notification `json:"items"`
+	NextCursor string         `json:"next_cursor,omitempty"`
 }
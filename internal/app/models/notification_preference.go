@@ -0,0 +1,136 @@
+package models
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// 通知種別
+const (
+	NotificationTypeDeadline = "task_deadline" // タスク期限の接近
+	NotificationTypeOverdue  = "overdue"       // 期限切れタスクの督促
+	NotificationTypeMention  = "mention"       // メンション
+	NotificationTypeSystem   = "system"        // システムからのお知らせ
+)
+
+// 配信チャネル
+const (
+	NotificationChannelWebSocket = "websocket"
+	NotificationChannelEmail     = "email"
+	NotificationChannelWebhook   = "webhook"
+	NotificationChannelChat      = "chat" // Slack / Teams の Incoming Webhook
+	NotificationChannelPush      = "push" // Web Push (アプリを開いていないブラウザ向け)
+)
+
+// メールのダイジェスト配信モード
+const (
+	EmailDigestOff    = "off"    // 通知ごとに即時送信
+	EmailDigestDaily  = "daily"  // 1日1回、未読通知をまとめて送信
+	EmailDigestWeekly = "weekly" // 週1回 (月曜)、未読通知をまとめて送信
+)
+
+// NotificationTypes は設定可能な通知種別の一覧です
+var NotificationTypes = []string{NotificationTypeDeadline, NotificationTypeOverdue, NotificationTypeMention, NotificationTypeSystem}
+
+// NotificationChannels は設定可能な配信チャネルの一覧です
+var NotificationChannels = []string{NotificationChannelWebSocket, NotificationChannelEmail, NotificationChannelWebhook, NotificationChannelChat, NotificationChannelPush}
+
+// NotificationPreference はユーザーごとの通知設定です
+// ルールが存在しない (種別, チャネル) の組み合わせは「有効」として扱います
+type NotificationPreference struct {
+	UserID            uuid.UUID  `gorm:"type:uuid;primaryKey" json:"user_id"`
+	Timezone          string     `gorm:"size:64;not null;default:Asia/Tokyo" json:"timezone"`
+	QuietHoursEnabled bool       `gorm:"not null;default:false" json:"quiet_hours_enabled"`
+	QuietHoursStart   string     `gorm:"size:5" json:"quiet_hours_start"` // "22:00" 形式 (ユーザーのタイムゾーン)
+	QuietHoursEnd     string     `gorm:"size:5" json:"quiet_hours_end"`   // "07:00" 形式。開始より前なら日付をまたぐ
+	EmailDigest       string     `gorm:"size:10;not null;default:off" json:"email_digest"`
+	LastDigestAt      *time.Time `json:"last_digest_at"` // 最後にダイジェストを送信した時刻
+	// 新しいタスクに設定するリマインダー (期限の何分前か)。null の場合は DefaultReminderOffsets
+	ReminderOffsets []int              `gorm:"type:jsonb;serializer:json" json:"reminder_offsets"`
+	Rules           []NotificationRule `gorm:"foreignKey:UserID;references:UserID" json:"rules"`
+	UpdatedAt       time.Time          `json:"updated_at"`
+}
+
+// EffectiveReminderOffsets は新しいタスクに設定するリマインダーを返します
+func (p *NotificationPreference) EffectiveReminderOffsets() []int {
+	if p.ReminderOffsets == nil {
+		return DefaultReminderOffsets
+	}
+	return p.ReminderOffsets
+}
+
+// NotificationRule は (通知種別, チャネル) ごとの有効/無効です
+type NotificationRule struct {
+	ID      uuid.UUID `gorm:"type:uuid;primaryKey" json:"-"`
+	UserID  uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_notification_rule" json:"-"`
+	Type    string    `gorm:"size:30;not null;uniqueIndex:idx_notification_rule" json:"type"`
+	Channel string    `gorm:"size:20;not null;uniqueIndex:idx_notification_rule" json:"channel"`
+	Enabled bool      `gorm:"not null" json:"enabled"`
+}
+
+// NotificationPreferenceUpdateRequest は通知設定の更新リクエストです
+// 指定されたフィールドだけを更新し、Rules は指定された組み合わせだけを上書きします
+type NotificationPreferenceUpdateRequest struct {
+	Timezone    *string            `json:"timezone"`
+	QuietHours  *QuietHoursRequest `json:"quiet_hours"`
+	EmailDigest *string            `json:"email_digest" binding:"omitempty,oneof=off daily weekly"`
+	// 既定のリマインダー (期限の何分前か)。省略時は変更せず、[] でリマインダーなしにする
+	ReminderOffsets []int                     `json:"reminder_offsets"`
+	Rules           []NotificationRuleRequest `json:"rules" binding:"dive"`
+}
+
+// QuietHoursRequest はおやすみ時間帯の設定です
+type QuietHoursRequest struct {
+	Enabled bool   `json:"enabled"`
+	Start   string `json:"start"`
+	End     string `json:"end"`
+}
+
+// NotificationRuleRequest は1件のルール設定です
+type NotificationRuleRequest struct {
+	Type    string `json:"type" binding:"required"`
+	Channel string `json:"channel" binding:"required"`
+	Enabled bool   `json:"enabled"`
+}
+
+// DeliveryDecision は通知設定を評価した結果です
+// Channels が空の場合、その通知は作成しません
+type DeliveryDecision struct {
+	Channels   []string
+	DeferUntil *time.Time // おやすみ時間帯のため配信を遅らせる場合の配信予定時刻
+}
+
+// Allows は指定チャネルへの配信が許可されているかを返します
+func (d *DeliveryDecision) Allows(channel string) bool {
+	for _, c := range d.Channels {
+		if c == channel {
+			return true
+		}
+	}
+	return false
+}
+
+// Without は指定チャネルを除いた配信判定を返します
+func (d *DeliveryDecision) Without(channels ...string) *DeliveryDecision {
+	if len(channels) == 0 {
+		return d
+	}
+	filtered := &DeliveryDecision{DeferUntil: d.DeferUntil}
+	for _, c := range d.Channels {
+		if !slices.Contains(channels, c) {
+			filtered.Channels = append(filtered.Channels, c)
+		}
+	}
+	return filtered
+}
+
+// BeforeCreate GORMフックで作成時にUUIDを自動生成
+func (r *NotificationRule) BeforeCreate(tx *gorm.DB) (err error) {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return
+}
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// OutboxMessage はキューへ送る前のジョブです (transactional outbox)
+// ドメインの変更と同じトランザクションで書き込み、ワーカーのリレーが SQS へ送ります
+type OutboxMessage struct {
+	// 書き込み順の連番。同じ集約のジョブはこの順に送る
+	ID int64 `gorm:"primaryKey;autoIncrement" json:"id"`
+	// 集約 (ジョブ種別 + 相関ID)。集約ごとに順序を保って送る
+	AggregateType string `gorm:"size:50;not null;index:idx_outbox_aggregate" json:"aggregate_type"`
+	AggregateID   string `gorm:"size:100;not null;index:idx_outbox_aggregate" json:"aggregate_id"`
+	// キューへ送る本文 (JobEnvelope の JSON)
+	Body         string `gorm:"type:text;not null" json:"body"`
+	DelaySeconds int    `gorm:"not null;default:0" json:"delay_seconds"`
+	// 送信に失敗した回数と最後のエラー。NextAttemptAt まで再送しない
+	Attempts      int        `gorm:"not null;default:0" json:"attempts"`
+	LastError     string     `gorm:"type:text" json:"last_error,omitempty"`
+	NextAttemptAt time.Time  `gorm:"not null;index" json:"next_attempt_at"`
+	CreatedAt     time.Time  `gorm:"not null" json:"created_at"`
+	SentAt        *time.Time `gorm:"index" json:"sent_at"` // 送信済みの時刻。NULL は未送信
+}
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PushSubscription はブラウザの Web Push 購読 (PushSubscription) です
+// エンドポイントはブラウザ・端末ごとに一意なので、1ユーザーが複数持てます
+type PushSubscription struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;index" json:"user_id"`
+	Endpoint  string    `gorm:"type:text;not null;uniqueIndex" json:"endpoint"`
+	P256dh    string    `gorm:"size:100;not null" json:"-"` // ブラウザの公開鍵 (base64url)
+	Auth      string    `gorm:"size:50;not null" json:"-"`  // 認証シークレット (base64url)
+	UserAgent string    `gorm:"size:255" json:"user_agent"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// PushSubscriptionRequest は POST /push/subscriptions のリクエストです
+// ブラウザの PushSubscription.toJSON() の形をそのまま受け取ります
+type PushSubscriptionRequest struct {
+	Endpoint string `json:"endpoint" binding:"required,url"`
+	Keys     struct {
+		P256dh string `json:"p256dh" binding:"required"`
+		Auth   string `json:"auth" binding:"required"`
+	} `json:"keys"`
+}
+
+// PushUnsubscribeRequest は DELETE /push/subscriptions のリクエストです
+type PushUnsubscribeRequest struct {
+	Endpoint string `json:"endpoint" binding:"required"`
+}
+
+// PushPayload はブラウザの Service Worker に届けるペイロードです
+type PushPayload struct {
+	NotificationID uuid.UUID            `json:"notification_id"`
+	Type           string               `json:"type"`
+	Title          string               `json:"title"`
+	Body           string               `json:"body"`
+	TaskID         *uuid.UUID           `json:"task_id,omitempty"`
+	Actions        []NotificationAction `json:"actions,omitempty"` // Service Worker で showNotification の actions に使う
+	CreatedAt      time.Time            `json:"created_at"`
+}
+
+// BeforeCreate GORMフックで作成時にUUIDを自動生成
+func (p *PushSubscription) BeforeCreate(tx *gorm.DB) (err error) {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	return
+}
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// 定期ジョブの実行のきっかけ (ScheduledJobRun.Trigger)
+const (
+	ScheduledJobTriggerSchedule = "schedule" // cron 式の実行時刻 (取り逃した時刻の実行を含む)
+	ScheduledJobTriggerManual   = "manual"   // 管理 API からの手動実行
+)
+
+// 定期ジョブの実行の状態 (ScheduledJobRun.Status)
+const (
+	ScheduledJobPending   = "pending"   // 手動実行の依頼を受け付け、ワーカーの実行を待っている
+	ScheduledJobRunning   = "running"   // 実行中
+	ScheduledJobSucceeded = "succeeded" // 成功
+	ScheduledJobFailed    = "failed"    // 失敗 (ワーカーの停止・クラッシュで中断した場合を含む)
+	ScheduledJobSkipped   = "skipped"   // 取り逃した実行時刻を、取り逃し時の方針により実行しなかった
+)
+
+// MissedRunPolicy はワーカーの停止中などで実行時刻を取り逃した場合の方針です
+type MissedRunPolicy string
+
+const (
+	// MissedRunSkip は取り逃した実行を行わず、次の実行時刻を待ちます (直前の実行時刻から猶予内なら実行する)
+	MissedRunSkip MissedRunPolicy = "skip"
+	// MissedRunOnce は何回取り逃しても、最後の実行時刻の分を1回だけ実行します
+	MissedRunOnce MissedRunPolicy = "once"
+	// MissedRunAll は取り逃した実行時刻の分を古い順にすべて実行します (件数には上限がある)
+	MissedRunAll MissedRunPolicy = "all"
+)
+
+// ScheduledJobRun は定期ジョブの1回の実行の履歴です
+// cron 式による実行は (Job, ScheduledAt) が一意のため、複数のワーカーが同じ実行時刻を同時に処理しても記録・実行できるのは1つだけ
+type ScheduledJobRun struct {
+	ID  uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	Job string    `gorm:"size:100;not null;uniqueIndex:idx_scheduled_job_slot;index:idx_scheduled_job_created" json:"job"`
+	// ScheduledAt は cron 式の実行時刻。手動実行は NULL
+	ScheduledAt *time.Time `gorm:"uniqueIndex:idx_scheduled_job_slot" json:"scheduled_at,omitempty"`
+	Trigger     string     `gorm:"size:20;not null" json:"trigger"`
+	Status      string     `gorm:"size:20;not null;index" json:"status"`
+	// Holder は実行したワーカー (リースの保持者名)
+	Holder     string     `gorm:"size:100" json:"holder,omitempty"`
+	Error      string     `gorm:"type:text" json:"error,omitempty"`
+	CreatedAt  time.Time  `gorm:"not null;index:idx_scheduled_job_created" json:"created_at"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// ScheduledJobInfo は登録されている定期ジョブの情報です (管理 API 用)
+type ScheduledJobInfo struct {
+	Name        string          `json:"name"`
+	Schedule    string          `json:"schedule"`
+	Description string          `json:"description,omitempty"`
+	MissedRuns  MissedRunPolicy `json:"missed_runs"`
+	NextRunAt   *time.Time      `json:"next_run_at"`
+	// LastRun は最後に作成された実行 (手動実行を含む)。一度も実行していない場合は null
+	LastRun *ScheduledJobRun `json:"last_run"`
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// MaxSnoozeMinutes はスヌーズできる最長の時間 (30日)
+const MaxSnoozeMinutes = 30 * 24 * 60
+
+// SnoozeRequest は POST /tasks/:id/snooze と POST /notifications/:id/snooze のリクエストです
+// DurationMinutes (今から何分後まで) と Until (再開する時刻) のどちらか一方を指定します
+type SnoozeRequest struct {
+	DurationMinutes int        `json:"duration_minutes"`
+	Until           *time.Time `json:"until"`
+}
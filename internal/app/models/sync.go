@@ -0,0 +1,84 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// 同期対象のエンティティ種別
+const (
+	SyncEntityTask         = "task"
+	SyncEntityNotification = "notification"
+)
+
+// オフライン変更の操作種別
+const (
+	SyncOpCreate   = "create"
+	SyncOpUpdate   = "update"
+	SyncOpDelete   = "delete"
+	SyncOpMarkRead = "mark_read" // notification のみ
+)
+
+// オフライン変更の適用結果
+const (
+	SyncResultApplied  = "applied"
+	SyncResultConflict = "conflict"
+	SyncResultRejected = "rejected"
+)
+
+// SyncTombstone は削除されたレコードを表します
+type SyncTombstone struct {
+	Entity    string    `json:"entity"`
+	ID        uuid.UUID `json:"id"`
+	Version   int       `json:"version,omitempty"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+// SyncPullResponse は GET /sync のレスポンスです
+// NextToken を次回の since に指定すると、それ以降の差分だけを取得できます
+type SyncPullResponse struct {
+	Tasks         []Task          `json:"tasks"`
+	Notifications []Notification  `json:"notifications"`
+	Tombstones    []SyncTombstone `json:"tombstones"`
+	NextToken     string          `json:"next_token"`
+}
+
+// SyncTaskData はオフラインで編集されたタスクの内容です
+type SyncTaskData struct {
+	Title       *string    `json:"title"`
+	Description *string    `json:"description"`
+	DueDate     *time.Time `json:"due_date"`
+	Status      *string    `json:"status"`
+}
+
+// SyncChange はクライアントがオフライン中に行った1件の変更です
+// BaseVersion はクライアントが編集を始めた時点のサーバー側バージョンです
+type SyncChange struct {
+	Entity      string        `json:"entity" binding:"required"`
+	Op          string        `json:"op" binding:"required"`
+	ID          uuid.UUID     `json:"id" binding:"required"`
+	BaseVersion int           `json:"base_version"`
+	Task        *SyncTaskData `json:"task"`
+}
+
+// SyncPushRequest は POST /sync のリクエストです
+type SyncPushRequest struct {
+	Changes []SyncChange `json:"changes" binding:"required,max=500,dive"`
+}
+
+// SyncChangeResult は1件の変更に対する適用結果です
+// 競合時は Server にサーバー側の最新状態（削除済みなら nil）が入ります
+type SyncChangeResult struct {
+	Entity  string    `json:"entity"`
+	ID      uuid.UUID `json:"id"`
+	Result  string    `json:"result"`
+	Version int       `json:"version,omitempty"`
+	Server  *Task     `json:"server,omitempty"`
+	Reason  string    `json:"reason,omitempty"`
+}
+
+// SyncPushResponse は POST /sync のレスポンスです
+type SyncPushResponse struct {
+	Results []SyncChangeResult `json:"results"`
+}
@@ -24,6 +24,8 @@ type Task struct {
 	DueDate        time.Time      `gorm:"type:timestamp" json:"due_date"`
 	LastNotifiedAt *time.Time     `json:"last_notified_at" gorm:"type:timestamp"` // ポインタにしてNULLを許容
 	Status         string         `gorm:"type:varchar(20);not null;default:pending" json:"status"`
+	Version        int            `gorm:"not null;default:1" json:"version"`   // 変更のたびにインクリメント（端末間同期用）
+	SnoozedUntil   *time.Time     `gorm:"type:timestamp" json:"snoozed_until"` // この時刻まで期限の通知を止める
 	CreatedAt      time.Time      `gorm:"type:timestamp" json:"created_at"`
 	UpdatedAt      time.Time      `gorm:"type:timestamp" json:"updated_at"`
 	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"`
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TaskEscalation は期限切れタスクへの督促 (overdue 通知) の進み具合です
+// 期限切れのタスクを監視ループが見つけた時点で作成し、期限が変わると削除して最初からやり直します
+type TaskEscalation struct {
+	TaskID    uuid.UUID  `gorm:"type:uuid;primaryKey" json:"task_id"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null;index" json:"-"`
+	Level     int        `gorm:"not null;default:0" json:"level"` // 送信済みの overdue 通知の件数
+	NextAt    time.Time  `gorm:"not null;index" json:"next_at"`   // 次の overdue 通知の送信時刻
+	LastAt    *time.Time `json:"last_at"`                         // 最後に overdue 通知を送った時刻
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// DueEscalation は送信時刻を迎えた督促とその対象タスクです
+// Escalation.Level は今回の通知を送った後の値 (1 始まり) です
+type DueEscalation struct {
+	Escalation TaskEscalation
+	Task       Task
+}
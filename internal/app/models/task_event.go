@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TaskEvent はタスクの変更を表すドメインイベントです
+// Type には WSEventTaskCreated / WSEventTaskUpdated / WSEventTaskDeleted のいずれかが入ります
+type TaskEvent struct {
+	Type          string    `json:"type"`
+	TaskID        uuid.UUID `json:"task_id"`
+	UserID        uuid.UUID `json:"user_id"`
+	Version       int       `json:"version"`
+	ChangedFields []string  `json:"changed_fields,omitempty"`
+	Task          *Task     `json:"task,omitempty"` // 削除イベントでは nil
+	OccurredAt    time.Time `json:"occurred_at"`
+}
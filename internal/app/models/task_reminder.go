@@ -0,0 +1,49 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	// MaxReminderOffset は設定できるリマインダーの最大値 (期限の30日前)
+	MaxReminderOffset = 30 * 24 * 60
+	// MaxRemindersPerTask は1タスクに設定できるリマインダーの最大件数
+	MaxRemindersPerTask = 10
+)
+
+// DefaultReminderOffsets は通知設定でリマインダーを指定していないユーザーの既定値です (期限の1時間前)
+var DefaultReminderOffsets = []int{60}
+
+// TaskReminder はタスクの期限に対する1つのリマインダーです
+// FiredAt が NULL の間は未送信で、送信時にワーカーが原子的に FiredAt を埋めるため1回だけ送られます
+type TaskReminder struct {
+	ID            uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	TaskID        uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_task_reminder" json:"task_id"`
+	UserID        uuid.UUID  `gorm:"type:uuid;not null;index" json:"-"`
+	OffsetMinutes int        `gorm:"not null;uniqueIndex:idx_task_reminder" json:"offset_minutes"` // 期限の何分前か。0 は期限ちょうど
+	RemindAt      time.Time  `gorm:"not null;index" json:"remind_at"`
+	FiredAt       *time.Time `json:"fired_at"` // 送信済み (または設定時点で既に過ぎていた) 時刻
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// TaskReminderRequest は PUT /tasks/:id/reminders のリクエストです
+type TaskReminderRequest struct {
+	Offsets []int `json:"offsets" binding:"required"` // 期限の何分前か。[] でリマインダーなし
+}
+
+// DueReminder は送信時刻を迎えたリマインダーとその対象タスクです
+type DueReminder struct {
+	Reminder TaskReminder
+	Task     Task
+}
+
+// BeforeCreate GORMフックで作成時にUUIDを自動生成
+func (r *TaskReminder) BeforeCreate(tx *gorm.DB) (err error) {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return
+}
@@ -14,6 +14,7 @@ type User struct {
 	ID        uuid.UUID      `gorm:"type:uuid;primaryKey" json:"id"`
 	Username  string         `gorm:"unique;not null" json:"username"` // 一意性とNOT NULL制約
 	Password  string         `gorm:"not null" json:"-"`               // ハッシュ化されたパスワード。レスポンスには含めないため `json:"-"`
+	Email     string         `gorm:"size:255" json:"email,omitempty"` // メール通知の送信先。未登録の場合はメール通知を送らない
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
@@ -23,6 +24,7 @@ type User struct {
 type SignupRequest struct {
 	Username string `json:"username" binding:"required"`       // Ginのバリデーションタグ
 	Password string `json:"password" binding:"required,min=8"` // パスワードの最小長を8文字に設定
+	Email    string `json:"email" binding:"omitempty,email"`   // 任意。メール通知を受け取る場合に指定
 }
 
 // SigninRequest はログイン時にクライアントから受け取るデータ
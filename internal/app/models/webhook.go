@@ -0,0 +1,107 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Webhook で購読できるイベント種別
+const (
+	WebhookEventTaskCreated         = "task.created"
+	WebhookEventTaskUpdated         = "task.updated"
+	WebhookEventTaskCompleted       = "task.completed" // status が completed に変わったとき (task.updated と併せて送信)
+	WebhookEventTaskDeleted         = "task.deleted"
+	WebhookEventNotificationCreated = "notification.created"
+	WebhookEventTest                = "webhook.test" // 「テスト送信」専用。購読は不要
+)
+
+// WebhookEventTypes は購読可能なイベント種別の一覧です
+var WebhookEventTypes = []string{
+	WebhookEventTaskCreated,
+	WebhookEventTaskUpdated,
+	WebhookEventTaskCompleted,
+	WebhookEventTaskDeleted,
+	WebhookEventNotificationCreated,
+}
+
+// JobKindWebhookDelivery は封筒 (JobEnvelope) の導入前の Webhook 配信ジョブの kind です
+// 旧形式のメッセージを判定するためだけに使用する
+const JobKindWebhookDelivery = JobTypeWebhookDelivery
+
+// WebhookSubscription はユーザーが登録した Webhook の送信先です
+type WebhookSubscription struct {
+	ID           uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	UserID       uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	URL          string     `gorm:"type:text;not null" json:"url"`
+	Secret       string     `gorm:"size:100;not null" json:"-"` // 署名用の共有シークレット。作成時のレスポンスでのみ返す
+	EventTypes   []string   `gorm:"type:jsonb;serializer:json;not null" json:"event_types"`
+	Active       bool       `gorm:"not null;default:true" json:"active"`
+	FailureCount int        `gorm:"not null;default:0" json:"failure_count"` // 連続失敗回数。成功すると0に戻る
+	DisabledAt   *time.Time `json:"disabled_at"`                             // 連続失敗で自動停止した時刻
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// WebhookDelivery は1回の送信試行の記録です (配信ログ)
+type WebhookDelivery struct {
+	ID             uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	SubscriptionID uuid.UUID `gorm:"type:uuid;not null;index" json:"subscription_id"`
+	EventID        uuid.UUID `gorm:"type:uuid;not null;index" json:"event_id"`
+	EventType      string    `gorm:"size:50;not null" json:"event_type"`
+	Attempt        int       `gorm:"not null" json:"attempt"`
+	StatusCode     int       `json:"status_code"` // 接続エラーなどで応答が無い場合は 0
+	Success        bool      `gorm:"not null" json:"success"`
+	Error          string    `gorm:"type:text" json:"error,omitempty"`
+	DurationMs     int64     `json:"duration_ms"`
+	CreatedAt      time.Time `gorm:"index" json:"created_at"`
+}
+
+// WebhookJob は SQS 経由でワーカーに渡す Webhook 配信ジョブです
+type WebhookJob struct {
+	Kind           string          `json:"kind,omitempty"` // 旧形式のメッセージでのみ JobKindWebhookDelivery
+	SubscriptionID uuid.UUID       `json:"subscription_id"`
+	EventID        uuid.UUID       `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Body           json.RawMessage `json:"body"`    // 送信先へ POST する本文 (WebhookPayload)
+	Attempt        int             `json:"attempt"` // 1始まり
+}
+
+// WebhookPayload は送信先へ POST する本文です
+type WebhookPayload struct {
+	ID        uuid.UUID   `json:"id"` // イベントID。再送時も同じ値になるため、受信側で重複排除に使える
+	Type      string      `json:"type"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+// WebhookSubscriptionRequest は Webhook の作成・更新リクエストです
+type WebhookSubscriptionRequest struct {
+	URL        string   `json:"url" binding:"required,url"`
+	EventTypes []string `json:"event_types" binding:"required,min=1"`
+	Active     *bool    `json:"active"` // 更新時のみ有効。true にすると自動停止を解除する
+}
+
+// WebhookSubscriptionCreated は作成時のレスポンスです。シークレットはこの時だけ返します
+type WebhookSubscriptionCreated struct {
+	WebhookSubscription
+	Secret string `json:"secret"`
+}
+
+// BeforeCreate GORMフックで作成時にUUIDを自動生成
+func (w *WebhookSubscription) BeforeCreate(tx *gorm.DB) (err error) {
+	if w.ID == uuid.Nil {
+		w.ID = uuid.New()
+	}
+	return
+}
+
+// BeforeCreate GORMフックで作成時にUUIDを自動生成
+func (d *WebhookDelivery) BeforeCreate(tx *gorm.DB) (err error) {
+	if d.ID == uuid.Nil {
+		d.ID = uuid.New()
+	}
+	return
+}
@@ -0,0 +1,115 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WSProtocolVersion は WebSocket プロトコルのバージョンです
+// 互換性のない変更を行う場合にインクリメントします
+const WSProtocolVersion = 1
+
+// クライアント → サーバーのコマンド種別
+const (
+	WSCommandAck           = "ack"            // 通知の受信確認
+	WSCommandMarkRead      = "mark_read"      // 通知を既読にする
+	WSCommandSubscribeTask = "subscribe_task" // タスクの変更イベントを購読する
+	WSCommandPing          = "ping"           // 死活確認
+)
+
+// サーバー → クライアントのイベント種別
+const (
+	WSEventNotification        = "notification"         // 新しい通知
+	WSEventTaskCreated         = "task.created"         // タスクの作成
+	WSEventTaskUpdated         = "task.updated"         // タスクの更新
+	WSEventTaskDeleted         = "task.deleted"         // タスクの削除
+	WSEventUnreadCount         = "unread_count"         // 未読件数の変化
+	WSEventNotificationSnoozed = "notification.snoozed" // 通知のスヌーズ (再配信まで一覧から隠す)
+	WSEventPong                = "pong"                 // ping への応答
+	WSEventResult              = "result"               // コマンドの正常完了
+	WSEventError               = "error"                // コマンドの失敗
+)
+
+// エラーイベントで返すエラーコード
+const (
+	WSErrorInvalidJSON        = "invalid_json"
+	WSErrorUnsupportedVersion = "unsupported_version"
+	WSErrorUnknownCommand     = "unknown_command"
+	WSErrorInvalidPayload     = "invalid_payload"
+	WSErrorNotFound           = "not_found"
+	WSErrorForbidden          = "forbidden"
+	WSErrorInternal           = "internal_error"
+)
+
+// WSEnvelope は WebSocket 上でやり取りする全フレーム共通の形式です
+// クライアントのコマンドもサーバーのイベントもこの形で送受信します
+type WSEnvelope struct {
+	Version int             `json:"v"`
+	Type    string          `json:"type"`
+	ID      string          `json:"id,omitempty"`       // クライアントが採番する相関ID（任意）
+	ReplyTo string          `json:"reply_to,omitempty"` // どのコマンドへの応答かを示す（サーバー発のみ）
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// WSNotificationRefPayload は ack / mark_read コマンドのペイロードです
+type WSNotificationRefPayload struct {
+	NotificationID uuid.UUID `json:"notification_id"`
+}
+
+// WSSubscribeTaskPayload は subscribe_task コマンドのペイロードです
+type WSSubscribeTaskPayload struct {
+	TaskID uuid.UUID `json:"task_id"`
+}
+
+// WSTaskEventPayload は task.* イベントのペイロードです
+// ChangedFields は task.updated の場合のみ、変更されたフィールド名(JSON名)が入ります
+type WSTaskEventPayload struct {
+	TaskID        uuid.UUID `json:"task_id"`
+	Version       int       `json:"version"`
+	ChangedFields []string  `json:"changed_fields,omitempty"`
+	Task          *Task     `json:"task,omitempty"`
+}
+
+// WSUnreadCountPayload は unread_count イベントのペイロードです
+type WSUnreadCountPayload struct {
+	UnreadCount int64 `json:"unread_count"`
+}
+
+// WSNotificationSnoozedPayload は notification.snoozed イベントのペイロードです
+type WSNotificationSnoozedPayload struct {
+	NotificationID uuid.UUID `json:"notification_id"`
+	SnoozedUntil   time.Time `json:"snoozed_until"`
+}
+
+// WSErrorPayload は error イベントのペイロードです
+type WSErrorPayload struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// HubMessage は Redis Pub/Sub を経由して各 API インスタンスの Hub に配られるメッセージです
+// TaskID はタスク購読によるフィルタリングに使います
+type HubMessage struct {
+	UserID uuid.UUID  `json:"user_id"`
+	TaskID *uuid.UUID `json:"task_id,omitempty"`
+	Event  WSEnvelope `json:"event"`
+}
+
+// NewWSEnvelope はペイロードをエンコードしてサーバーイベントを組み立てます
+func NewWSEnvelope(eventType string, replyTo string, payload interface{}) (*WSEnvelope, error) {
+	env := &WSEnvelope{
+		Version: WSProtocolVersion,
+		Type:    eventType,
+		ReplyTo: replyTo,
+	}
+	if payload != nil {
+		raw, err := json.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+		env.Payload = raw
+	}
+	return env, nil
+}
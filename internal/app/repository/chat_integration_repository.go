@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+	"my-portfolio-2025/internal/app/models"
+
+	"github.com/google/uuid"
+)
+
+// ChatIntegrationRepository はチャット連携の永続化を抽象化します
+type ChatIntegrationRepository interface {
+	// Create (連携を作成)
+	Create(ctx context.Context, integration *models.ChatIntegration) error
+
+	// FindByID (連携を取得。存在しない場合は gorm.ErrRecordNotFound)
+	FindByID(ctx context.Context, id uuid.UUID) (*models.ChatIntegration, error)
+
+	// FindByUserID (ユーザーの連携を作成順に取得)
+	FindByUserID(ctx context.Context, userID uuid.UUID) ([]models.ChatIntegration, error)
+
+	// FindActiveByType (指定した通知種別を投稿する有効な連携を取得)
+	FindActiveByType(ctx context.Context, userID uuid.UUID, notificationType string) ([]models.ChatIntegration, error)
+
+	// Update (名前・URL・通知種別・有効状態を更新)
+	Update(ctx context.Context, integration *models.ChatIntegration) error
+
+	// Delete (連携を削除)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
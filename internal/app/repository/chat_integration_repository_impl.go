@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"my-portfolio-2025/internal/app/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type chatIntegrationRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewChatIntegrationRepository は ChatIntegrationRepository の新しいインスタンスを作成します
+func NewChatIntegrationRepository(db *gorm.DB) ChatIntegrationRepository {
+	return &chatIntegrationRepositoryImpl{db: db}
+}
+
+// Create は連携を作成します
+func (r *chatIntegrationRepositoryImpl) Create(ctx context.Context, integration *models.ChatIntegration) error {
+	if err := r.db.WithContext(ctx).Create(integration).Error; err != nil {
+		return fmt.Errorf("chatIntegrationRepository.Create: %w", err)
+	}
+	return nil
+}
+
+// FindByID は連携を取得します
+func (r *chatIntegrationRepositoryImpl) FindByID(ctx context.Context, id uuid.UUID) (*models.ChatIntegration, error) {
+	var integration models.ChatIntegration
+	if err := r.db.WithContext(ctx).First(&integration, "id = ?", id).Error; err != nil {
+		return nil, fmt.Errorf("chatIntegrationRepository.FindByID (id=%s): %w", id, err)
+	}
+	return &integration, nil
+}
+
+// FindByUserID はユーザーの連携一覧を取得します
+func (r *chatIntegrationRepositoryImpl) FindByUserID(ctx context.Context, userID uuid.UUID) ([]models.ChatIntegration, error) {
+	var integrations []models.ChatIntegration
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at").Find(&integrations).Error; err != nil {
+		return nil, fmt.Errorf("chatIntegrationRepository.FindByUserID (userID=%s): %w", userID, err)
+	}
+	return integrations, nil
+}
+
+// FindActiveByType は指定した通知種別を投稿する有効な連携を取得します
+// notification_types は jsonb 配列のため、包含演算子 (@>) で絞り込む
+func (r *chatIntegrationRepositoryImpl) FindActiveByType(ctx context.Context, userID uuid.UUID, notificationType string) ([]models.ChatIntegration, error) {
+	contains, err := json.Marshal([]string{notificationType})
+	if err != nil {
+		return nil, fmt.Errorf("chatIntegrationRepository.FindActiveByType (marshal): %w", err)
+	}
+
+	var integrations []models.ChatIntegration
+	err = r.db.WithContext(ctx).
+		Where("user_id = ? AND active = ? AND notification_types @> ?::jsonb", userID, true, string(contains)).
+		Find(&integrations).Error
+	if err != nil {
+		return nil, fmt.Errorf("chatIntegrationRepository.FindActiveByType (userID=%s): %w", userID, err)
+	}
+	return integrations, nil
+}
+
+// Update は連携を更新します
+func (r *chatIntegrationRepositoryImpl) Update(ctx context.Context, integration *models.ChatIntegration) error {
+	err := r.db.WithContext(ctx).
+		Model(integration).
+		Select("provider", "name", "webhook_url", "notification_types", "active").
+		Updates(integration).Error
+	if err != nil {
+		return fmt.Errorf("chatIntegrationRepository.Update (id=%s): %w", integration.ID, err)
+	}
+	return nil
+}
+
+// Delete は連携を削除します
+func (r *chatIntegrationRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Delete(&models.ChatIntegration{}, "id = ?", id)
+	if result.Error != nil {
+		return fmt.Errorf("chatIntegrationRepository.Delete (id=%s): %w", id, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("chatIntegrationRepository.Delete (id=%s): %w", id, gorm.ErrRecordNotFound)
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+	"my-portfolio-2025/internal/app/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NotificationPreferenceRepository は通知設定の永続化を抽象化します
+type NotificationPreferenceRepository interface {
+	// FindByUserID (ルールを含めて取得。未設定の場合は gorm.ErrRecordNotFound)
+	FindByUserID(ctx context.Context, userID uuid.UUID) (*models.NotificationPreference, error)
+
+	// Save (設定本体を upsert し、ルールを置き換える)
+	Save(ctx context.Context, pref *models.NotificationPreference) error
+
+	// FindDigestSubscribers (メールをダイジェストで受け取る設定のユーザーをルールを含めて取得)
+	FindDigestSubscribers(ctx context.Context) ([]models.NotificationPreference, error)
+
+	// MarkDigestSent (last_digest_at が prev のままの場合のみ sentAt に更新。他のワーカーが先に更新していた場合は false)
+	MarkDigestSent(ctx context.Context, userID uuid.UUID, prev *time.Time, sentAt time.Time) (bool, error)
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"my-portfolio-2025/internal/app/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type notificationPreferenceRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewNotificationPreferenceRepository は NotificationPreferenceRepository の新しいインスタンスを作成します
+func NewNotificationPreferenceRepository(db *gorm.DB) NotificationPreferenceRepository {
+	return &notificationPreferenceRepositoryImpl{db: db}
+}
+
+// FindByUserID はユーザーの通知設定をルールと一緒に取得します
+func (r *notificationPreferenceRepositoryImpl) FindByUserID(ctx context.Context, userID uuid.UUID) (*models.NotificationPreference, error) {
+	var pref models.NotificationPreference
+	if err := r.db.WithContext(ctx).Preload("Rules").First(&pref, "user_id = ?", userID).Error; err != nil {
+		return nil, fmt.Errorf("notificationPreferenceRepository.FindByUserID (userID=%s): %w", userID, err)
+	}
+	return &pref, nil
+}
+
+// Save は通知設定を保存します
+// 本体とルールの整合性を保つため、1つのトランザクションで置き換えます
+func (r *notificationPreferenceRepositoryImpl) Save(ctx context.Context, pref *models.NotificationPreference) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// last_digest_at は DigestService だけが更新するため上書きしない
+		upsert := clause.OnConflict{
+			Columns: []clause.Column{{Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"timezone", "quiet_hours_enabled", "quiet_hours_start", "quiet_hours_end", "email_digest", "reminder_offsets", "updated_at",
+			}),
+		}
+		if err := tx.Omit("Rules").Clauses(upsert).Create(pref).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", pref.UserID).Delete(&models.NotificationRule{}).Error; err != nil {
+			return err
+		}
+		if len(pref.Rules) == 0 {
+			return nil
+		}
+		for i := range pref.Rules {
+			pref.Rules[i].UserID = pref.UserID
+		}
+		return tx.Create(&pref.Rules).Error
+	})
+	if err != nil {
+		return fmt.Errorf("notificationPreferenceRepository.Save (userID=%s): %w", pref.UserID, err)
+	}
+	return nil
+}
+
+// FindDigestSubscribers はダイジェスト配信を有効にしているユーザーの設定を取得します
+func (r *notificationPreferenceRepositoryImpl) FindDigestSubscribers(ctx context.Context) ([]models.NotificationPreference, error) {
+	var prefs []models.NotificationPreference
+	err := r.db.WithContext(ctx).
+		Preload("Rules").
+		Where("email_digest IN ?", []string{models.EmailDigestDaily, models.EmailDigestWeekly}).
+		Find(&prefs).Error
+
+	if err != nil {
+		return nil, fmt.Errorf("notificationPreferenceRepository.FindDigestSubscribers: %w", err)
+	}
+	return prefs, nil
+}
+
+// MarkDigestSent はダイジェストの送信時刻を記録します
+// 読み取った時点の値を条件に含めることで、複数のワーカーが同じダイジェストを二重に送らないようにする
+func (r *notificationPreferenceRepositoryImpl) MarkDigestSent(ctx context.Context, userID uuid.UUID, prev *time.Time, sentAt time.Time) (bool, error) {
+	query := r.db.WithContext(ctx).Model(&models.NotificationPreference{}).Where("user_id = ?", userID)
+	if prev == nil {
+		query = query.Where("last_digest_at IS NULL")
+	} else {
+		query = query.Where("last_digest_at = ?", *prev)
+	}
+
+	result := query.UpdateColumn("last_digest_at", sentAt)
+	if result.Error != nil {
+		return false, fmt.Errorf("notificationPreferenceRepository.MarkDigestSent (userID=%s): %w", userID, result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
@@ -3,18 +3,49 @@ package repository
 import (
 	"context"
 	"my-portfolio-2025/internal/app/models"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type NotificationRepository interface {
 
-	// Create (作成)
-	Create(ctx context.Context, notification *models.Notification) error
+	// Create (作成。同じ DedupKey の通知が既にある場合は作成せず false を返し、notification に既存の通知を読み込む)
+	Create(ctx context.Context, notification *models.Notification) (bool, error)
 
-	// FindByUserID (ユーザーIDに紐づく通知を最新順に取得)
-	FindByUserID(ctx context.Context, userID uuid.UUID, limit int, offset int) ([]models.Notification, error)
+	// FindByUserID (ユーザーIDに紐づく通知を条件で絞り込み、最新順に取得。filter.GroupByTask の場合はタスクごとに最新の1件)
+	FindByUserID(ctx context.Context, userID uuid.UUID, filter models.NotificationFilter) ([]models.Notification, error)
+
+	// FindByID (ユーザーの通知を1件取得。他人の通知は gorm.ErrRecordNotFound)
+	FindByID(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*models.Notification, error)
+
+	// CountUnread (未読件数を取得)
+	CountUnread(ctx context.Context, userID uuid.UUID) (int64, error)
 
 	// MarkAsRead (既読に更新)
 	MarkAsRead(ctx context.Context, id uuid.UUID, userID uuid.UUID) error
+
+	// MarkAllAsRead (ユーザーの未読通知をすべて既読に更新し、更新件数を返す)
+	MarkAllAsRead(ctx context.Context, userID uuid.UUID) (int64, error)
+
+	// Delete (論理削除)
+	Delete(ctx context.Context, id uuid.UUID, userID uuid.UUID) error
+
+	// MarkAsAcked (クライアントの受信確認時刻を記録)
+	MarkAsAcked(ctx context.Context, id uuid.UUID, userID uuid.UUID, ackedAt time.Time) error
+
+	// FindChangedSince (since 以降に作成・更新された通知を取得。差分同期用)
+	FindChangedSince(ctx context.Context, userID uuid.UUID, since time.Time) ([]models.Notification, error)
+
+	// FindDueDeferred (配信予定時刻を過ぎた保留中の通知を古い順に取得)
+	FindDueDeferred(ctx context.Context, now time.Time, limit int) ([]models.Notification, error)
+
+	// Snooze (until まで保留して未読に戻す。保留解除時に再配信される)
+	Snooze(ctx context.Context, id uuid.UUID, userID uuid.UUID, until time.Time) error
+
+	// MarkDelivered (リアルタイム配信を終えた時刻を記録)
+	MarkDelivered(ctx context.Context, id uuid.UUID, deliveredAt time.Time) error
+
+	// ClearDeferred (保留を解除。既に他のワーカーが解除していた場合は false)
+	ClearDeferred(ctx context.Context, id uuid.UUID) (bool, error)
 }
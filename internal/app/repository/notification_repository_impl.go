@@ -4,9 +4,11 @@ import (
 	"context"
 	"fmt"
 	"my-portfolio-2025/internal/app/models"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type notificationRepositoryImpl struct {
@@ -18,21 +20,78 @@ func NewNotificationRepository(db *gorm.DB) NotificationRepository {
 }
 
 // Create は新しい通知をDBに保存します
-func (r *notificationRepositoryImpl) Create(ctx context.Context, n *models.Notification) error {
-	if err := r.db.WithContext(ctx).Create(n).Error; err != nil {
-		return fmt.Errorf("notificationRepository.Create: %w", err)
+// dedup_key の一意制約に当たった場合は何もせず false を返すため、同じ通知を何度作成しても1件になる
+// その場合 n には作成済みの通知 (論理削除済みを含む) を読み込む
+func (r *notificationRepositoryImpl) Create(ctx context.Context, n *models.Notification) (bool, error) {
+	result := conn(ctx, r.db).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "dedup_key"}}, DoNothing: true}).
+		Create(n)
+	if result.Error != nil {
+		return false, fmt.Errorf("notificationRepository.Create: %w", result.Error)
+	}
+	if result.RowsAffected > 0 {
+		return true, nil
+	}
+
+	if n.DedupKey != nil {
+		key := *n.DedupKey
+		*n = models.Notification{}
+		if err := conn(ctx, r.db).Unscoped().Where("dedup_key = ?", key).First(n).Error; err != nil {
+			return false, fmt.Errorf("notificationRepository.Create (existing): %w", err)
+		}
+	}
+	return false, nil
+}
+
+// MarkDelivered はリアルタイム配信を終えた時刻を記録します
+func (r *notificationRepositoryImpl) MarkDelivered(ctx context.Context, id uuid.UUID, deliveredAt time.Time) error {
+	err := r.db.WithContext(ctx).
+		Model(&models.Notification{}).
+		Where("id = ? AND delivered_at IS NULL", id).
+		Update("delivered_at", deliveredAt).Error
+
+	if err != nil {
+		return fmt.Errorf("notificationRepository.MarkDelivered (id=%s): %w", id, err)
 	}
 	return nil
 }
 
 // FindByUserID は特定のユーザーの通知を最新順に取得します
-func (r *notificationRepositoryImpl) FindByUserID(ctx context.Context, userID uuid.UUID, limit int, offset int) ([]models.Notification, error) {
+// 配信を保留している通知は含めません
+// GroupByTask の場合は同じタスクの通知を最新の1件にまとめ、件数を GroupCount に入れます (タスクに紐づかない通知はまとめない)
+func (r *notificationRepositoryImpl) FindByUserID(ctx context.Context, userID uuid.UUID, filter models.NotificationFilter) ([]models.Notification, error) {
 	var notifications []models.Notification
-	err := r.db.WithContext(ctx).
-		Where("user_id = ?", userID).
+	query := r.db.WithContext(ctx).Model(&models.Notification{}).Where("user_id = ? AND deferred_until IS NULL", userID)
+
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+	if filter.IsRead != nil {
+		query = query.Where("is_read = ?", *filter.IsRead)
+	}
+	if filter.TaskID != nil {
+		query = query.Where("task_id = ?", *filter.TaskID)
+	}
+	if filter.CreatedAfter != nil {
+		query = query.Where("created_at > ?", *filter.CreatedAfter)
+	}
+	if filter.GroupByTask {
+		// 絞り込んだ通知をタスクごとにまとめ、各グループの最新の1件だけを残す
+		grouped := query.Select(`notifications.*,
+			COUNT(*) OVER (PARTITION BY COALESCE(task_id, id)) AS group_count,
+			ROW_NUMBER() OVER (PARTITION BY COALESCE(task_id, id) ORDER BY created_at DESC, id DESC) AS group_rank`)
+		query = r.db.WithContext(ctx).Table("(?) AS notifications", grouped).Where("group_rank = 1")
+	}
+	if filter.Before != nil {
+		// キーセットページネーション。同時刻の通知は id で順序を決める
+		query = query.Where("(created_at < ? OR (created_at = ? AND id < ?))",
+			filter.Before.CreatedAt, filter.Before.CreatedAt, filter.Before.ID)
+	}
+
+	err := query.
 		Order("created_at DESC").
-		Limit(limit).
-		Offset(offset).
+		Order("id DESC").
+		Limit(filter.Limit).
 		Find(&notifications).Error
 
 	if err != nil {
@@ -41,6 +100,30 @@ func (r *notificationRepositoryImpl) FindByUserID(ctx context.Context, userID uu
 	return notifications, nil
 }
 
+// FindByID はユーザーの通知を1件取得します
+// セキュリティのため、userIDも条件に含めて他人の通知は見つからない扱いにする
+func (r *notificationRepositoryImpl) FindByID(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*models.Notification, error) {
+	var n models.Notification
+	if err := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).First(&n).Error; err != nil {
+		return nil, fmt.Errorf("notificationRepository.FindByID (id=%s): %w", id, err)
+	}
+	return &n, nil
+}
+
+// CountUnread は未読の通知件数を返します (保留中の通知は数えません)
+func (r *notificationRepositoryImpl) CountUnread(ctx context.Context, userID uuid.UUID) (int64, error) {
+	var count int64
+	err := conn(ctx, r.db).
+		Model(&models.Notification{}).
+		Where("user_id = ? AND is_read = ? AND deferred_until IS NULL", userID, false).
+		Count(&count).Error
+
+	if err != nil {
+		return 0, fmt.Errorf("notificationRepository.CountUnread (userID=%s): %w", userID, err)
+	}
+	return count, nil
+}
+
 // MarkAsRead は通知を既読(is_read = true)に更新します
 // セキュリティのため、userIDも条件に含めて他人の通知を操作できないようにする
 func (r *notificationRepositoryImpl) MarkAsRead(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
@@ -59,3 +142,125 @@ func (r *notificationRepositoryImpl) MarkAsRead(ctx context.Context, id uuid.UUI
 	}
 	return nil
 }
+
+// MarkAllAsRead はユーザーの未読通知をすべて既読に更新します
+func (r *notificationRepositoryImpl) MarkAllAsRead(ctx context.Context, userID uuid.UUID) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.Notification{}).
+		Where("user_id = ? AND is_read = ? AND deferred_until IS NULL", userID, false).
+		Update("is_read", true)
+
+	if result.Error != nil {
+		return 0, fmt.Errorf("notificationRepository.MarkAllAsRead (userID=%s): %w", userID, result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// Delete は通知を論理削除します
+// MarkAsRead と同様に userID を条件に含め、他人の通知は削除できないようにする
+func (r *notificationRepositoryImpl) Delete(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
+	result := r.db.WithContext(ctx).
+		Where("id = ? AND user_id = ?", id, userID).
+		Delete(&models.Notification{})
+
+	if result.Error != nil {
+		return fmt.Errorf("notificationRepository.Delete (id=%s, userID=%s): %w", id, userID, result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("notificationRepository.Delete (id=%s): %w", id, gorm.ErrRecordNotFound)
+	}
+	return nil
+}
+
+// MarkAsAcked はクライアントが通知を受信したことを記録します
+// 既に記録済みの場合は最初の受信時刻を維持します
+func (r *notificationRepositoryImpl) MarkAsAcked(ctx context.Context, id uuid.UUID, userID uuid.UUID, ackedAt time.Time) error {
+	result := r.db.WithContext(ctx).
+		Model(&models.Notification{}).
+		Where("id = ? AND user_id = ?", id, userID).
+		Update("acked_at", gorm.Expr("COALESCE(acked_at, ?)", ackedAt))
+
+	if result.Error != nil {
+		return fmt.Errorf("notificationRepository.MarkAsAcked (id=%s, userID=%s): %w", id, userID, result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("notificationRepository.MarkAsAcked (id=%s): %w", id, gorm.ErrRecordNotFound)
+	}
+	return nil
+}
+
+// FindChangedSince は since 以降に作成・更新・削除された通知を取得します
+// since がゼロ値の場合は削除済みを除く全件を返します。保留中の通知は保留解除 (updated_at の更新) 後に返ります
+func (r *notificationRepositoryImpl) FindChangedSince(ctx context.Context, userID uuid.UUID, since time.Time) ([]models.Notification, error) {
+	var notifications []models.Notification
+	var err error
+
+	if since.IsZero() {
+		err = r.db.WithContext(ctx).
+			Where("user_id = ? AND deferred_until IS NULL", userID).
+			Order("created_at").
+			Find(&notifications).Error
+	} else {
+		// 削除された通知も tombstone として返すため Unscoped で取得する
+		err = r.db.WithContext(ctx).Unscoped().
+			Where("user_id = ? AND deferred_until IS NULL AND (updated_at > ? OR created_at > ? OR deleted_at > ?)", userID, since, since, since).
+			Order("created_at").
+			Find(&notifications).Error
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("notificationRepository.FindChangedSince (userID=%s): %w", userID, err)
+	}
+	return notifications, nil
+}
+
+// Snooze は通知を until まで保留し、未読に戻します
+// 保留中の通知は一覧・未読件数から除かれ、監視ループが until を過ぎた時点で再配信します
+func (r *notificationRepositoryImpl) Snooze(ctx context.Context, id uuid.UUID, userID uuid.UUID, until time.Time) error {
+	result := r.db.WithContext(ctx).
+		Model(&models.Notification{}).
+		Where("id = ? AND user_id = ?", id, userID).
+		Updates(map[string]interface{}{
+			"deferred_until": until,
+			"is_read":        false,
+		})
+
+	if result.Error != nil {
+		return fmt.Errorf("notificationRepository.Snooze (id=%s, userID=%s): %w", id, userID, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("notificationRepository.Snooze (id=%s): %w", id, gorm.ErrRecordNotFound)
+	}
+	return nil
+}
+
+// FindDueDeferred は配信予定時刻 (deferred_until) が now 以前の通知を取得します
+func (r *notificationRepositoryImpl) FindDueDeferred(ctx context.Context, now time.Time, limit int) ([]models.Notification, error) {
+	var notifications []models.Notification
+	err := r.db.WithContext(ctx).
+		Where("deferred_until IS NOT NULL AND deferred_until <= ?", now).
+		Order("deferred_until").
+		Limit(limit).
+		Find(&notifications).Error
+
+	if err != nil {
+		return nil, fmt.Errorf("notificationRepository.FindDueDeferred: %w", err)
+	}
+	return notifications, nil
+}
+
+// ClearDeferred は通知の保留を解除します
+// deferred_until IS NOT NULL を条件に含めることで、複数のワーカーが同じ通知を二重に配信しないようにする
+func (r *notificationRepositoryImpl) ClearDeferred(ctx context.Context, id uuid.UUID) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.Notification{}).
+		Where("id = ? AND deferred_until IS NOT NULL", id).
+		Update("deferred_until", nil)
+
+	if result.Error != nil {
+		return false, fmt.Errorf("notificationRepository.ClearDeferred (id=%s): %w", id, result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
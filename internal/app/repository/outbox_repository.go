@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+	"my-portfolio-2025/internal/app/models"
+	"time"
+)
+
+type OutboxRepository interface {
+
+	// Add (ジョブを書き込む。Transactor のトランザクション内で呼ぶとドメインの変更と一緒にコミットされる)
+	Add(ctx context.Context, msg *models.OutboxMessage) error
+
+	// LockPending (送信できる未送信のジョブを、集約ごとに最も古い1件ずつロックして取得。トランザクション内で呼ぶ)
+	LockPending(ctx context.Context, now time.Time, limit int) ([]models.OutboxMessage, error)
+
+	// MarkSent (送信済みにする)
+	MarkSent(ctx context.Context, id int64, sentAt time.Time) error
+
+	// MarkFailed (送信の失敗を記録し、nextAttemptAt まで再送を待たせる)
+	MarkFailed(ctx context.Context, id int64, reason string, nextAttemptAt time.Time) error
+
+	// DeleteSentBefore (before より前に送信済みになったジョブを削除し、削除件数を返す)
+	DeleteSentBefore(ctx context.Context, before time.Time) (int64, error)
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"my-portfolio-2025/internal/app/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type outboxRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) OutboxRepository {
+	return &outboxRepositoryImpl{db: db}
+}
+
+// Add はジョブを書き込みます
+func (r *outboxRepositoryImpl) Add(ctx context.Context, msg *models.OutboxMessage) error {
+	if err := conn(ctx, r.db).Create(msg).Error; err != nil {
+		return fmt.Errorf("outboxRepository.Add: %w", err)
+	}
+	return nil
+}
+
+// LockPending は送信できる未送信のジョブをロックして取得します
+// 同じ集約に自分より古い未送信のジョブがあるものは対象外のため、集約ごとに先頭の1件だけを返す
+// (先頭のジョブが再送待ちの間は後続も送らない)。FOR UPDATE SKIP LOCKED により、複数のリレーが
+// 同時に実行しても同じ集約のジョブを並行して送ることはありません
+func (r *outboxRepositoryImpl) LockPending(ctx context.Context, now time.Time, limit int) ([]models.OutboxMessage, error) {
+	var msgs []models.OutboxMessage
+	err := conn(ctx, r.db).Raw(`
+		SELECT * FROM outbox_messages o
+		WHERE o.sent_at IS NULL AND o.next_attempt_at <= ?
+		  AND NOT EXISTS (
+			SELECT 1 FROM outbox_messages p
+			WHERE p.aggregate_type = o.aggregate_type AND p.aggregate_id = o.aggregate_id
+			  AND p.sent_at IS NULL AND p.id < o.id
+		  )
+		ORDER BY o.id
+		LIMIT ?
+		FOR UPDATE SKIP LOCKED`,
+		now, limit,
+	).Scan(&msgs).Error
+	if err != nil {
+		return nil, fmt.Errorf("outboxRepository.LockPending: %w", err)
+	}
+	return msgs, nil
+}
+
+// MarkSent はジョブを送信済みにします
+func (r *outboxRepositoryImpl) MarkSent(ctx context.Context, id int64, sentAt time.Time) error {
+	err := conn(ctx, r.db).
+		Model(&models.OutboxMessage{}).
+		Where("id = ?", id).
+		Update("sent_at", sentAt).Error
+	if err != nil {
+		return fmt.Errorf("outboxRepository.MarkSent (id=%d): %w", id, err)
+	}
+	return nil
+}
+
+// MarkFailed は送信の失敗を記録します
+func (r *outboxRepositoryImpl) MarkFailed(ctx context.Context, id int64, reason string, nextAttemptAt time.Time) error {
+	err := conn(ctx, r.db).
+		Model(&models.OutboxMessage{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"attempts":        gorm.Expr("attempts + 1"),
+			"last_error":      reason,
+			"next_attempt_at": nextAttemptAt,
+		}).Error
+	if err != nil {
+		return fmt.Errorf("outboxRepository.MarkFailed (id=%d): %w", id, err)
+	}
+	return nil
+}
+
+// DeleteSentBefore は古い送信済みのジョブを削除します
+func (r *outboxRepositoryImpl) DeleteSentBefore(ctx context.Context, before time.Time) (int64, error) {
+	result := conn(ctx, r.db).Where("sent_at IS NOT NULL AND sent_at < ?", before).Delete(&models.OutboxMessage{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("outboxRepository.DeleteSentBefore: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+	"my-portfolio-2025/internal/app/models"
+	"time"
+)
+
+type ProcessedJobRepository interface {
+
+	// Record (処理済みとして記録。既に記録がある場合は記録せず false を返し、job に既存の記録を読み込む)
+	Record(ctx context.Context, job *models.ProcessedJob) (bool, error)
+
+	// DeleteExpired (保持期限を過ぎた記録を削除し、削除件数を返す)
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"my-portfolio-2025/internal/app/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type processedJobRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewProcessedJobRepository(db *gorm.DB) ProcessedJobRepository {
+	return &processedJobRepositoryImpl{db: db}
+}
+
+// Record はジョブを処理済みとして記録します
+// 主キーの一意制約で判定するため、複数のワーカーが同時に同じジョブを処理しても記録できるのは1つだけ
+// (トランザクション内で呼んだ場合、先に記録したトランザクションの終了を待ってから判定される)
+func (r *processedJobRepositoryImpl) Record(ctx context.Context, job *models.ProcessedJob) (bool, error) {
+	result := conn(ctx, r.db).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "key"}}, DoNothing: true}).
+		Create(job)
+	if result.Error != nil {
+		return false, fmt.Errorf("processedJobRepository.Record: %w", result.Error)
+	}
+	if result.RowsAffected > 0 {
+		return true, nil
+	}
+
+	key := job.Key
+	*job = models.ProcessedJob{}
+	if err := conn(ctx, r.db).Where("key = ?", key).First(job).Error; err != nil {
+		return false, fmt.Errorf("processedJobRepository.Record (existing): %w", err)
+	}
+	return false, nil
+}
+
+// DeleteExpired は保持期限を過ぎた記録を削除します
+func (r *processedJobRepositoryImpl) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	result := conn(ctx, r.db).Where("expires_at < ?", now).Delete(&models.ProcessedJob{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("processedJobRepository.DeleteExpired: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+	"my-portfolio-2025/internal/app/models"
+
+	"github.com/google/uuid"
+)
+
+// PushSubscriptionRepository は Web Push 購読の永続化を抽象化します
+type PushSubscriptionRepository interface {
+	// Upsert (エンドポイントが同じ購読があれば所有者と鍵を置き換える)
+	Upsert(ctx context.Context, sub *models.PushSubscription) error
+
+	// FindByUserID (ユーザーの購読を全て取得)
+	FindByUserID(ctx context.Context, userID uuid.UUID) ([]models.PushSubscription, error)
+
+	// DeleteByEndpoint (ユーザーの購読をエンドポイントで削除。存在しない場合は gorm.ErrRecordNotFound)
+	DeleteByEndpoint(ctx context.Context, userID uuid.UUID, endpoint string) error
+
+	// Delete (失効した購読を削除)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
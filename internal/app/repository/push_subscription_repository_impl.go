@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"my-portfolio-2025/internal/app/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type pushSubscriptionRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewPushSubscriptionRepository は PushSubscriptionRepository の新しいインスタンスを作成します
+func NewPushSubscriptionRepository(db *gorm.DB) PushSubscriptionRepository {
+	return &pushSubscriptionRepositoryImpl{db: db}
+}
+
+// Upsert は購読を保存します
+// 同じブラウザで別のユーザーがログインし直した場合は、新しいユーザーの購読として置き換える
+func (r *pushSubscriptionRepositoryImpl) Upsert(ctx context.Context, sub *models.PushSubscription) error {
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "endpoint"}},
+			DoUpdates: clause.AssignmentColumns([]string{"user_id", "p256dh", "auth", "user_agent", "updated_at"}),
+		}).
+		Create(sub).Error
+	if err != nil {
+		return fmt.Errorf("pushSubscriptionRepository.Upsert: %w", err)
+	}
+	return nil
+}
+
+// FindByUserID はユーザーの購読を全て取得します
+func (r *pushSubscriptionRepositoryImpl) FindByUserID(ctx context.Context, userID uuid.UUID) ([]models.PushSubscription, error) {
+	var subs []models.PushSubscription
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Find(&subs).Error; err != nil {
+		return nil, fmt.Errorf("pushSubscriptionRepository.FindByUserID (userID=%s): %w", userID, err)
+	}
+	return subs, nil
+}
+
+// DeleteByEndpoint はユーザーの購読をエンドポイントで削除します
+func (r *pushSubscriptionRepositoryImpl) DeleteByEndpoint(ctx context.Context, userID uuid.UUID, endpoint string) error {
+	result := r.db.WithContext(ctx).Delete(&models.PushSubscription{}, "user_id = ? AND endpoint = ?", userID, endpoint)
+	if result.Error != nil {
+		return fmt.Errorf("pushSubscriptionRepository.DeleteByEndpoint (userID=%s): %w", userID, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("pushSubscriptionRepository.DeleteByEndpoint (userID=%s): %w", userID, gorm.ErrRecordNotFound)
+	}
+	return nil
+}
+
+// Delete は購読を削除します
+func (r *pushSubscriptionRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := r.db.WithContext(ctx).Delete(&models.PushSubscription{}, "id = ?", id).Error; err != nil {
+		return fmt.Errorf("pushSubscriptionRepository.Delete (id=%s): %w", id, err)
+	}
+	return nil
+}
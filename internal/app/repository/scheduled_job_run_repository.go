@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"my-portfolio-2025/internal/app/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ScheduledJobRunRepository interface {
+
+	// Claim (cron 式の実行時刻の実行を記録。同じジョブ・実行時刻の記録が既にある場合は記録せず false を返す)
+	Claim(ctx context.Context, run *models.ScheduledJobRun) (bool, error)
+
+	// Create (手動実行の依頼を記録)
+	Create(ctx context.Context, run *models.ScheduledJobRun) error
+
+	// LastScheduledAt (cron 式で記録した最後の実行時刻。記録が無い場合は nil)
+	LastScheduledAt(ctx context.Context, job string) (*time.Time, error)
+
+	// HasPending (実行待ちの手動実行があるか)
+	HasPending(ctx context.Context, job string) (bool, error)
+
+	// StartPending (最も古い実行待ちの手動実行を holder の実行中にして返す。無い場合は nil)
+	StartPending(ctx context.Context, job string, holder string, now time.Time) (*models.ScheduledJobRun, error)
+
+	// Finish (実行の結果を記録)
+	Finish(ctx context.Context, id uuid.UUID, status string, reason string, finishedAt time.Time) error
+
+	// FailRunning (実行中のまま残っている記録を失敗にし、件数を返す。ジョブのリースを取得してから呼ぶ)
+	FailRunning(ctx context.Context, job string, reason string, now time.Time) (int64, error)
+
+	// LatestByJob (ジョブごとに最後に作成された記録)
+	LatestByJob(ctx context.Context) (map[string]models.ScheduledJobRun, error)
+
+	// List (ジョブの記録を新しい順に最大 limit 件)
+	List(ctx context.Context, job string, limit int) ([]models.ScheduledJobRun, error)
+
+	// DeleteFinishedBefore (before より前に終了した記録を削除し、削除件数を返す)
+	DeleteFinishedBefore(ctx context.Context, before time.Time) (int64, error)
+}
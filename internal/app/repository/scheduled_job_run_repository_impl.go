@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"my-portfolio-2025/internal/app/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type scheduledJobRunRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewScheduledJobRunRepository(db *gorm.DB) ScheduledJobRunRepository {
+	return &scheduledJobRunRepositoryImpl{db: db}
+}
+
+// Claim は cron 式の実行時刻の実行を記録します
+// (job, scheduled_at) の一意制約で判定するため、複数のワーカーが同じ実行時刻を記録しても成功するのは1つだけ
+func (r *scheduledJobRunRepositoryImpl) Claim(ctx context.Context, run *models.ScheduledJobRun) (bool, error) {
+	result := conn(ctx, r.db).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "job"}, {Name: "scheduled_at"}}, DoNothing: true}).
+		Create(run)
+	if result.Error != nil {
+		return false, fmt.Errorf("scheduledJobRunRepository.Claim: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Create は手動実行の依頼を記録します
+func (r *scheduledJobRunRepositoryImpl) Create(ctx context.Context, run *models.ScheduledJobRun) error {
+	if err := conn(ctx, r.db).Create(run).Error; err != nil {
+		return fmt.Errorf("scheduledJobRunRepository.Create: %w", err)
+	}
+	return nil
+}
+
+// LastScheduledAt は cron 式で記録した最後の実行時刻を返します (スキップした実行時刻を含む)
+func (r *scheduledJobRunRepositoryImpl) LastScheduledAt(ctx context.Context, job string) (*time.Time, error) {
+	var last sql.NullTime
+	err := conn(ctx, r.db).
+		Model(&models.ScheduledJobRun{}).
+		Where("job = ? AND scheduled_at IS NOT NULL", job).
+		Select("MAX(scheduled_at)").
+		Scan(&last).Error
+	if err != nil {
+		return nil, fmt.Errorf("scheduledJobRunRepository.LastScheduledAt: %w", err)
+	}
+	if !last.Valid {
+		return nil, nil
+	}
+	return &last.Time, nil
+}
+
+// HasPending は実行待ちの手動実行があるかを返します
+func (r *scheduledJobRunRepositoryImpl) HasPending(ctx context.Context, job string) (bool, error) {
+	var count int64
+	err := conn(ctx, r.db).
+		Model(&models.ScheduledJobRun{}).
+		Where("job = ? AND status = ?", job, models.ScheduledJobPending).
+		Limit(1).
+		Count(&count).Error
+	if err != nil {
+		return false, fmt.Errorf("scheduledJobRunRepository.HasPending: %w", err)
+	}
+	return count > 0, nil
+}
+
+// StartPending は最も古い実行待ちの手動実行を実行中にして返します
+func (r *scheduledJobRunRepositoryImpl) StartPending(ctx context.Context, job string, holder string, now time.Time) (*models.ScheduledJobRun, error) {
+	var runs []models.ScheduledJobRun
+	err := conn(ctx, r.db).Raw(`
+		UPDATE scheduled_job_runs
+		SET status = ?, holder = ?, started_at = ?
+		WHERE id = (
+			SELECT id FROM scheduled_job_runs
+			WHERE job = ? AND status = ?
+			ORDER BY created_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`,
+		models.ScheduledJobRunning, holder, now, job, models.ScheduledJobPending,
+	).Scan(&runs).Error
+	if err != nil {
+		return nil, fmt.Errorf("scheduledJobRunRepository.StartPending: %w", err)
+	}
+	if len(runs) == 0 {
+		return nil, nil
+	}
+	return &runs[0], nil
+}
+
+// Finish は実行の結果を記録します
+func (r *scheduledJobRunRepositoryImpl) Finish(ctx context.Context, id uuid.UUID, status string, reason string, finishedAt time.Time) error {
+	result := conn(ctx, r.db).
+		Model(&models.ScheduledJobRun{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":      status,
+			"error":       reason,
+			"finished_at": finishedAt,
+		})
+	if result.Error != nil {
+		return fmt.Errorf("scheduledJobRunRepository.Finish (id=%s): %w", id, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("scheduledJobRunRepository.Finish (id=%s): %w", id, gorm.ErrRecordNotFound)
+	}
+	return nil
+}
+
+// FailRunning は実行中のまま残っている記録を失敗にします
+// リースを取得できた時点で他に実行中のワーカーはいないため、残っている記録は停止・クラッシュで中断したもの
+func (r *scheduledJobRunRepositoryImpl) FailRunning(ctx context.Context, job string, reason string, now time.Time) (int64, error) {
+	result := conn(ctx, r.db).
+		Model(&models.ScheduledJobRun{}).
+		Where("job = ? AND status = ?", job, models.ScheduledJobRunning).
+		Updates(map[string]interface{}{
+			"status":      models.ScheduledJobFailed,
+			"error":       reason,
+			"finished_at": now,
+		})
+	if result.Error != nil {
+		return 0, fmt.Errorf("scheduledJobRunRepository.FailRunning: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// LatestByJob はジョブごとに最後に作成された記録を返します
+func (r *scheduledJobRunRepositoryImpl) LatestByJob(ctx context.Context) (map[string]models.ScheduledJobRun, error) {
+	var runs []models.ScheduledJobRun
+	err := conn(ctx, r.db).Raw(`
+		SELECT DISTINCT ON (job) * FROM scheduled_job_runs
+		ORDER BY job, created_at DESC`,
+	).Scan(&runs).Error
+	if err != nil {
+		return nil, fmt.Errorf("scheduledJobRunRepository.LatestByJob: %w", err)
+	}
+
+	latest := make(map[string]models.ScheduledJobRun, len(runs))
+	for _, run := range runs {
+		latest[run.Job] = run
+	}
+	return latest, nil
+}
+
+// List はジョブの記録を新しい順に返します
+func (r *scheduledJobRunRepositoryImpl) List(ctx context.Context, job string, limit int) ([]models.ScheduledJobRun, error) {
+	var runs []models.ScheduledJobRun
+	err := conn(ctx, r.db).
+		Where("job = ?", job).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&runs).Error
+	if err != nil {
+		return nil, fmt.Errorf("scheduledJobRunRepository.List: %w", err)
+	}
+	return runs, nil
+}
+
+// DeleteFinishedBefore は before より前に終了した記録を削除します
+// 各ジョブの cron 式の最後の実行時刻は取り逃しの判定に使うため残す
+func (r *scheduledJobRunRepositoryImpl) DeleteFinishedBefore(ctx context.Context, before time.Time) (int64, error) {
+	result := conn(ctx, r.db).Exec(`
+		DELETE FROM scheduled_job_runs r
+		WHERE r.finished_at < ?
+		  AND NOT (r.scheduled_at IS NOT NULL AND r.scheduled_at = (
+			SELECT MAX(l.scheduled_at) FROM scheduled_job_runs l WHERE l.job = r.job
+		  ))`,
+		before,
+	)
+	if result.Error != nil {
+		return 0, fmt.Errorf("scheduledJobRunRepository.DeleteFinishedBefore: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
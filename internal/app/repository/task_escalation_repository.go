@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+	"my-portfolio-2025/internal/app/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TaskEscalationRepository は期限切れタスクの督促状態の永続化を抽象化します
+type TaskEscalationRepository interface {
+	// StartOverdue (期限切れで督促状態が無いタスクの督促を開始する。初回の送信時刻は期限 + firstDelay)
+	StartOverdue(ctx context.Context, now time.Time, firstDelay time.Duration) error
+
+	// ClaimDue (送信時刻を迎えた督促の Level を1つ進めて返す。next には送信後の Level を渡し、次回の送信時刻を受け取る)
+	// Level が maxLevel に達した督促は対象外。並行実行しても同じ行は1回しか返らない
+	ClaimDue(ctx context.Context, now time.Time, maxLevel int, limit int, next func(level int) time.Time) ([]models.TaskEscalation, error)
+
+	// Unclaim (送信に失敗した督促の Level を1つ戻し、次の監視ループで再送させる)
+	Unclaim(ctx context.Context, taskID uuid.UUID, level int, now time.Time) error
+
+	// DeleteByTaskID (タスクの督促状態を削除する。期限が変わった場合は最初からやり直す)
+	DeleteByTaskID(ctx context.Context, taskID uuid.UUID) error
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"my-portfolio-2025/internal/app/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type taskEscalationRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewTaskEscalationRepository は TaskEscalationRepository の新しいインスタンスを作成します
+func NewTaskEscalationRepository(db *gorm.DB) TaskEscalationRepository {
+	return &taskEscalationRepositoryImpl{db: db}
+}
+
+// StartOverdue は期限を過ぎた未完了のタスクのうち、督促状態が無いものに督促状態を作成します
+// 期限が未設定 (ゼロ値) のタスクとスヌーズ中のタスクは対象外です
+func (r *taskEscalationRepositoryImpl) StartOverdue(ctx context.Context, now time.Time, firstDelay time.Duration) error {
+	err := conn(ctx, r.db).Exec(`
+		INSERT INTO task_escalations (task_id, user_id, level, next_at, created_at, updated_at)
+		SELECT t.id, t.user_id, 0, t.due_date + ? * interval '1 second', ?, ?
+		FROM tasks t
+		LEFT JOIN task_escalations e ON e.task_id = t.id
+		WHERE e.task_id IS NULL
+		  AND t.due_date > ? AND t.due_date <= ?
+		  AND t.status <> ? AND t.deleted_at IS NULL
+		  AND (t.snoozed_until IS NULL OR t.snoozed_until <= ?)
+		ON CONFLICT (task_id) DO NOTHING`,
+		firstDelay.Seconds(), now, now, time.Time{}, now, models.TaskStatusCompleted, now,
+	).Error
+	if err != nil {
+		return fmt.Errorf("taskEscalationRepository.StartOverdue: %w", err)
+	}
+	return nil
+}
+
+// ClaimDue は送信時刻を迎えた督促を1つのトランザクションで取得し、Level と次回の送信時刻を進めます
+// 完了・削除済みのタスクと、スヌーズ中のタスクは対象外です。FOR UPDATE SKIP LOCKED により、
+// 複数のワーカーが同時に実行しても同じ督促を二重に取得しません
+func (r *taskEscalationRepositoryImpl) ClaimDue(ctx context.Context, now time.Time, maxLevel int, limit int, next func(level int) time.Time) ([]models.TaskEscalation, error) {
+	var claimed []models.TaskEscalation
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.TaskEscalation{}).
+			Joins("JOIN tasks t ON t.id = task_escalations.task_id").
+			Where("task_escalations.next_at <= ? AND task_escalations.level < ?", now, maxLevel).
+			Where("t.status <> ? AND t.deleted_at IS NULL", models.TaskStatusCompleted).
+			Where("t.snoozed_until IS NULL OR t.snoozed_until <= ?", now).
+			Order("task_escalations.next_at").
+			Limit(limit).
+			Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: "task_escalations"}, Options: "SKIP LOCKED"}).
+			Find(&claimed).Error; err != nil {
+			return err
+		}
+
+		for i := range claimed {
+			e := &claimed[i]
+			e.Level++
+			e.NextAt = next(e.Level)
+			e.LastAt = &now
+			if err := tx.Model(&models.TaskEscalation{}).Where("task_id = ?", e.TaskID).
+				Updates(map[string]interface{}{
+					"level":      e.Level,
+					"next_at":    e.NextAt,
+					"last_at":    now,
+					"updated_at": now,
+				}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("taskEscalationRepository.ClaimDue: %w", err)
+	}
+	return claimed, nil
+}
+
+// Unclaim は ClaimDue で進めた Level を1つ戻します
+// 取得後に期限が変わって督促状態が作り直された場合などは、Level が一致しないため何もしません
+func (r *taskEscalationRepositoryImpl) Unclaim(ctx context.Context, taskID uuid.UUID, level int, now time.Time) error {
+	err := r.db.WithContext(ctx).
+		Model(&models.TaskEscalation{}).
+		Where("task_id = ? AND level = ?", taskID, level).
+		Updates(map[string]interface{}{
+			"level":      level - 1,
+			"next_at":    now,
+			"updated_at": now,
+		}).Error
+	if err != nil {
+		return fmt.Errorf("taskEscalationRepository.Unclaim (taskID=%s): %w", taskID, err)
+	}
+	return nil
+}
+
+// DeleteByTaskID はタスクの督促状態を削除します
+func (r *taskEscalationRepositoryImpl) DeleteByTaskID(ctx context.Context, taskID uuid.UUID) error {
+	if err := r.db.WithContext(ctx).Where("task_id = ?", taskID).Delete(&models.TaskEscalation{}).Error; err != nil {
+		return fmt.Errorf("taskEscalationRepository.DeleteByTaskID (taskID=%s): %w", taskID, err)
+	}
+	return nil
+}
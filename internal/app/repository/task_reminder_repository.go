@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+	"my-portfolio-2025/internal/app/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TaskReminderRepository はタスクのリマインダーの永続化を抽象化します
+type TaskReminderRepository interface {
+	// FindByTaskID (タスクのリマインダーを送信時刻の順に取得)
+	FindByTaskID(ctx context.Context, taskID uuid.UUID) ([]models.TaskReminder, error)
+
+	// ReplaceForTask (タスクのリマインダーを全て置き換える)
+	ReplaceForTask(ctx context.Context, taskID uuid.UUID, reminders []models.TaskReminder) error
+
+	// DeleteByTaskID (タスクのリマインダーを全て削除)
+	DeleteByTaskID(ctx context.Context, taskID uuid.UUID) error
+
+	// ClaimDue (送信時刻を過ぎた未送信のリマインダーを送信済みにして返す。並行実行しても同じ行は1回しか返らない)
+	ClaimDue(ctx context.Context, now time.Time, limit int) ([]models.TaskReminder, error)
+
+	// Unclaim (送信に失敗したリマインダーを未送信に戻す)
+	Unclaim(ctx context.Context, id uuid.UUID) error
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"my-portfolio-2025/internal/app/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type taskReminderRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewTaskReminderRepository は TaskReminderRepository の新しいインスタンスを作成します
+func NewTaskReminderRepository(db *gorm.DB) TaskReminderRepository {
+	return &taskReminderRepositoryImpl{db: db}
+}
+
+// FindByTaskID はタスクのリマインダーを取得します
+func (r *taskReminderRepositoryImpl) FindByTaskID(ctx context.Context, taskID uuid.UUID) ([]models.TaskReminder, error) {
+	var reminders []models.TaskReminder
+	if err := r.db.WithContext(ctx).Where("task_id = ?", taskID).Order("remind_at").Find(&reminders).Error; err != nil {
+		return nil, fmt.Errorf("taskReminderRepository.FindByTaskID (taskID=%s): %w", taskID, err)
+	}
+	return reminders, nil
+}
+
+// ReplaceForTask はタスクのリマインダーを1つのトランザクションで置き換えます
+func (r *taskReminderRepositoryImpl) ReplaceForTask(ctx context.Context, taskID uuid.UUID, reminders []models.TaskReminder) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("task_id = ?", taskID).Delete(&models.TaskReminder{}).Error; err != nil {
+			return err
+		}
+		if len(reminders) == 0 {
+			return nil
+		}
+		return tx.Create(&reminders).Error
+	})
+	if err != nil {
+		return fmt.Errorf("taskReminderRepository.ReplaceForTask (taskID=%s): %w", taskID, err)
+	}
+	return nil
+}
+
+// DeleteByTaskID はタスクのリマインダーを削除します
+func (r *taskReminderRepositoryImpl) DeleteByTaskID(ctx context.Context, taskID uuid.UUID) error {
+	if err := r.db.WithContext(ctx).Where("task_id = ?", taskID).Delete(&models.TaskReminder{}).Error; err != nil {
+		return fmt.Errorf("taskReminderRepository.DeleteByTaskID (taskID=%s): %w", taskID, err)
+	}
+	return nil
+}
+
+// ClaimDue は送信時刻を過ぎた未送信のリマインダーを送信済みにして返します
+// 完了・削除済みのタスクと、スヌーズ中のタスクは対象外です。FOR UPDATE SKIP LOCKED により、
+// 複数のワーカーが同時に実行しても同じリマインダーを二重に取得しません
+func (r *taskReminderRepositoryImpl) ClaimDue(ctx context.Context, now time.Time, limit int) ([]models.TaskReminder, error) {
+	var reminders []models.TaskReminder
+	err := conn(ctx, r.db).Raw(`
+		UPDATE task_reminders SET fired_at = ?
+		WHERE id IN (
+			SELECT r.id FROM task_reminders r
+			JOIN tasks t ON t.id = r.task_id
+			WHERE r.fired_at IS NULL AND r.remind_at <= ?
+			  AND t.status <> ? AND t.deleted_at IS NULL
+			  AND (t.snoozed_until IS NULL OR t.snoozed_until <= ?)
+			ORDER BY r.remind_at
+			LIMIT ?
+			FOR UPDATE OF r SKIP LOCKED
+		)
+		RETURNING *`,
+		now, now, models.TaskStatusCompleted, now, limit,
+	).Scan(&reminders).Error
+	if err != nil {
+		return nil, fmt.Errorf("taskReminderRepository.ClaimDue: %w", err)
+	}
+	return reminders, nil
+}
+
+// Unclaim はリマインダーを未送信に戻します
+func (r *taskReminderRepositoryImpl) Unclaim(ctx context.Context, id uuid.UUID) error {
+	err := r.db.WithContext(ctx).
+		Model(&models.TaskReminder{}).
+		Where("id = ?", id).
+		Update("fired_at", nil).Error
+	if err != nil {
+		return fmt.Errorf("taskReminderRepository.Unclaim (id=%s): %w", id, err)
+	}
+	return nil
+}
@@ -31,4 +31,19 @@ type TaskRepository interface {
 
 	// UpdateLastNotifiedAt: 通知完了時刻を更新する
 	UpdateLastNotifiedAt(ctx context.Context, taskID uuid.UUID, notifiedAt time.Time) error
+
+	// FindChangedSince: since 以降に作成・更新・削除されたタスクを削除済みも含めて取得する (差分同期用)
+	// since がゼロ値の場合は削除済みを除く全件を返す
+	FindChangedSince(ctx context.Context, userID uuid.UUID, since time.Time) ([]models.Task, error)
+
+	// UpdateIfVersion: バージョンが expectedVersion と一致する場合のみ更新する (楽観的ロック)
+	// 更新できた場合は true を返す
+	UpdateIfVersion(ctx context.Context, task *models.Task, expectedVersion int) (bool, error)
+
+	// CreateIfAbsent: 同じIDのタスク (論理削除済みを含む) が無い場合のみ作成する
+	// 作成できた場合は true を返す
+	CreateIfAbsent(ctx context.Context, task *models.Task) (bool, error)
+
+	// DeleteIfVersion: バージョンが expectedVersion と一致する場合のみ論理削除する
+	DeleteIfVersion(ctx context.Context, taskID uuid.UUID, expectedVersion int) (bool, error)
 }
@@ -9,6 +9,7 @@ import (
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // taskRepositoryImpl は TaskRepository インターフェースの具体的な実装です。
@@ -21,6 +22,11 @@ func NewTaskRepository(db *gorm.DB) TaskRepository {
 	return &taskRepositoryImpl{db: db}
 }
 
+// withDB はトランザクションの接続で動くリポジトリを返します (Bind から使用)
+func (r *taskRepositoryImpl) withDB(db *gorm.DB) TaskRepository {
+	return &taskRepositoryImpl{db: db}
+}
+
 // Create: 新しいタスクをDBに保存します。
 // エラー発生時にコンテキストを付与して返す
 func (r *taskRepositoryImpl) Create(task *models.Task) error {
@@ -60,14 +66,23 @@ func (r *taskRepositoryImpl) Update(task *models.Task) error {
 }
 
 // Delete: IDを指定してタスクを削除します。
+// 差分同期で削除を検知できるよう、論理削除と同時にバージョンを進めます。
 func (r *taskRepositoryImpl) Delete(taskID uuid.UUID) error {
-	if err := r.db.Delete(&models.Task{}, taskID).Error; err != nil {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Task{}).Where("id = ?", taskID).
+			UpdateColumn("version", gorm.Expr("version + 1")).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Task{}, taskID).Error
+	})
+	if err != nil {
 		return fmt.Errorf("taskRepository.Delete (taskID=%s): %w", taskID, err)
 	}
 	return nil
 }
 
 // FindUpcomingTasks: 指定した日付より前の期限のタスクを取得 (期限切れチェック用)
+// スヌーズ中のタスクは解除されるまで対象外です
 func (r *taskRepositoryImpl) FindUpcomingTasks(ctx context.Context, threshold time.Time) ([]models.Task, error) {
 	var tasks []models.Task
 	now := utils.NowJST()
@@ -75,6 +90,7 @@ func (r *taskRepositoryImpl) FindUpcomingTasks(ctx context.Context, threshold ti
 	err := r.db.WithContext(ctx).
 		Where("due_date <= ? AND status != ? AND (last_notified_at IS NULL OR last_notified_at < ?)",
 			threshold, models.TaskStatusCompleted, now.Add(-1*time.Hour)).
+		Where("snoozed_until IS NULL OR snoozed_until <= ?", now).
 		Find(&tasks).Error
 
 	if err != nil {
@@ -85,7 +101,7 @@ func (r *taskRepositoryImpl) FindUpcomingTasks(ctx context.Context, threshold ti
 
 // UpdateLastNotifiedAt: 通知完了時刻を更新する
 func (r *taskRepositoryImpl) UpdateLastNotifiedAt(ctx context.Context, taskID uuid.UUID, notifiedAt time.Time) error {
-	err := r.db.WithContext(ctx).Model(&models.Task{}).
+	err := conn(ctx, r.db).Model(&models.Task{}).
 		Where("id = ?", taskID).
 		Update("last_notified_at", notifiedAt).Error
 
@@ -94,3 +110,78 @@ func (r *taskRepositoryImpl) UpdateLastNotifiedAt(ctx context.Context, taskID uu
 	}
 	return nil
 }
+
+// FindChangedSince: since 以降に変更されたタスクを削除済み(墓標)も含めて取得します
+func (r *taskRepositoryImpl) FindChangedSince(ctx context.Context, userID uuid.UUID, since time.Time) ([]models.Task, error) {
+	var tasks []models.Task
+	var err error
+
+	if since.IsZero() {
+		// 初回同期: 削除済みは不要なので通常のスコープで全件取得
+		err = r.db.WithContext(ctx).
+			Where("user_id = ?", userID).
+			Order("updated_at").
+			Find(&tasks).Error
+	} else {
+		// updated_at はタイムゾーンなしの timestamp 列で、JST の壁時計時刻が保存されている
+		// ドライバーは引数のタイムゾーンを捨てて壁時計時刻で比較するため、JST に揃えてから渡す
+		since = since.In(utils.JST)
+		err = r.db.WithContext(ctx).Unscoped().
+			Where("user_id = ? AND (updated_at > ? OR deleted_at > ?)", userID, since, since).
+			Order("updated_at").
+			Find(&tasks).Error
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("taskRepository.FindChangedSince (userID=%s): %w", userID, err)
+	}
+	return tasks, nil
+}
+
+// CreateIfAbsent: 同じIDのタスクが無い場合のみ作成します
+// 主キーの一意制約で判定するため、論理削除済みの同一IDが残っている場合も作成しない
+// (一意制約違反のエラーにしないので、呼び出し元のトランザクションを中断させない)
+func (r *taskRepositoryImpl) CreateIfAbsent(ctx context.Context, task *models.Task) (bool, error) {
+	result := conn(ctx, r.db).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "id"}}, DoNothing: true}).
+		Create(task)
+	if result.Error != nil {
+		return false, fmt.Errorf("taskRepository.CreateIfAbsent (taskID=%s): %w", task.ID, result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// UpdateIfVersion: バージョンが一致する場合のみタスクを更新します
+// task.Version には更新後のバージョンを設定してから呼び出してください
+func (r *taskRepositoryImpl) UpdateIfVersion(ctx context.Context, task *models.Task, expectedVersion int) (bool, error) {
+	result := conn(ctx, r.db).Model(&models.Task{}).
+		Where("id = ? AND version = ?", task.ID, expectedVersion).
+		Updates(map[string]interface{}{
+			"title":         task.Title,
+			"description":   task.Description,
+			"due_date":      task.DueDate,
+			"status":        task.Status,
+			"snoozed_until": task.SnoozedUntil,
+			"version":       task.Version,
+		})
+
+	if result.Error != nil {
+		return false, fmt.Errorf("taskRepository.UpdateIfVersion (taskID=%s): %w", task.ID, result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// DeleteIfVersion: バージョンが一致する場合のみタスクを論理削除します
+func (r *taskRepositoryImpl) DeleteIfVersion(ctx context.Context, taskID uuid.UUID, expectedVersion int) (bool, error) {
+	result := conn(ctx, r.db).Model(&models.Task{}).
+		Where("id = ? AND version = ?", taskID, expectedVersion).
+		Updates(map[string]interface{}{
+			"version":    gorm.Expr("version + 1"),
+			"deleted_at": utils.NowJST(),
+		})
+
+	if result.Error != nil {
+		return false, fmt.Errorf("taskRepository.DeleteIfVersion (taskID=%s): %w", taskID, result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
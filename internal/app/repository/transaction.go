@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// Transactor は複数のリポジトリへの書き込みを1つの DB トランザクションにまとめます
+// トランザクションは Context で受け渡すため、fn の中では渡された ctx を使ってリポジトリを呼び出してください
+type Transactor interface {
+	// Transaction は fn をトランザクション内で実行します。fn がエラーを返すとロールバックする
+	// 既にトランザクション内の場合は、そのトランザクションのまま fn を実行する
+	Transaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type txKey struct{}
+
+type transactorImpl struct {
+	db *gorm.DB
+}
+
+// NewTransactor は Transactor を作成します
+func NewTransactor(db *gorm.DB) Transactor {
+	return &transactorImpl{db: db}
+}
+
+func (t *transactorImpl) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return fn(ctx)
+	}
+
+	err := t.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+	if err != nil {
+		return fmt.Errorf("transactor.Transaction: %w", err)
+	}
+	return nil
+}
+
+// conn は ctx にトランザクションがあればそれを、無ければ db を返します
+// Context を受け取るリポジトリのメソッドは、これを使うことで Transactor のトランザクションに参加できる
+func conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
+
+// txBinder はトランザクションの接続に差し替えたリポジトリを返せるリポジトリです
+type txBinder[T any] interface {
+	withDB(db *gorm.DB) T
+}
+
+// Bind は ctx にトランザクションがあれば、そのトランザクションで動く repo を返します
+// Context を受け取らないメソッド (TaskRepository.Create など) をトランザクションに参加させるために使う
+// トランザクションが無い場合や、差し替えられない実装 (テストのモックなど) の場合は repo をそのまま返す
+func Bind[T any](ctx context.Context, repo T) T {
+	tx, ok := ctx.Value(txKey{}).(*gorm.DB)
+	if !ok {
+		return repo
+	}
+	if b, ok := any(repo).(txBinder[T]); ok {
+		return b.withDB(tx)
+	}
+	return repo
+}
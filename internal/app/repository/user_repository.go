@@ -2,6 +2,8 @@ package repository
 
 import (
 	"my-portfolio-2025/internal/app/models" // Taskモデルをインポート
+
+	"github.com/google/uuid"
 )
 
 // UserRepository はTaskモデルのデータ永続化（CRUD）操作を抽象化します。
@@ -11,4 +13,7 @@ type UserRepository interface {
 
 	// FindByUsername (ユーザー名からユーザーを取得)
 	FindByUsername(username string) (*models.User, error)
+
+	// FindByID (IDからユーザーを取得)
+	FindByID(userID uuid.UUID) (*models.User, error)
 }
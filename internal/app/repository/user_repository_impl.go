@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"my-portfolio-2025/internal/app/models"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
@@ -34,3 +35,12 @@ func (r *userRepositoryImpl) FindByUsername(username string) (*models.User, erro
 	}
 	return &user, nil
 }
+
+// FindByID はIDからユーザーを取得します (通知の送信先の解決などに使用)
+func (r *userRepositoryImpl) FindByID(userID uuid.UUID) (*models.User, error) {
+	var user models.User
+	if err := r.DB.First(&user, "id = ?", userID).Error; err != nil {
+		return nil, fmt.Errorf("userRepository.FindByID (userID=%s): %w", userID, err)
+	}
+	return &user, nil
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"my-portfolio-2025/internal/app/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WebhookRepository は Webhook の購読と配信ログの永続化を抽象化します
+type WebhookRepository interface {
+	// Create (購読を作成)
+	Create(ctx context.Context, sub *models.WebhookSubscription) error
+
+	// FindByID (購読を取得。存在しない場合は gorm.ErrRecordNotFound)
+	FindByID(ctx context.Context, id uuid.UUID) (*models.WebhookSubscription, error)
+
+	// FindByUserID (ユーザーの購読を作成順に取得)
+	FindByUserID(ctx context.Context, userID uuid.UUID) ([]models.WebhookSubscription, error)
+
+	// FindActiveByEvent (指定イベントを購読している有効な購読を取得)
+	FindActiveByEvent(ctx context.Context, userID uuid.UUID, eventType string) ([]models.WebhookSubscription, error)
+
+	// Update (URL・イベント種別・有効状態・失敗回数を更新)
+	Update(ctx context.Context, sub *models.WebhookSubscription) error
+
+	// Delete (購読と配信ログを削除)
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	// RecordFailure (連続失敗回数を1増やし、更新後の値を返す)
+	RecordFailure(ctx context.Context, id uuid.UUID) (int, error)
+
+	// ResetFailures (連続失敗回数を0に戻す)
+	ResetFailures(ctx context.Context, id uuid.UUID) error
+
+	// Disable (自動停止)
+	Disable(ctx context.Context, id uuid.UUID, at time.Time) error
+
+	// CreateDelivery (配信ログを記録)
+	CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error
+
+	// FindDeliveries (配信ログを新しい順に取得)
+	FindDeliveries(ctx context.Context, subscriptionID uuid.UUID, limit int) ([]models.WebhookDelivery, error)
+}
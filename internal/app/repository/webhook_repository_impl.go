@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"my-portfolio-2025/internal/app/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type webhookRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewWebhookRepository は WebhookRepository の新しいインスタンスを作成します
+func NewWebhookRepository(db *gorm.DB) WebhookRepository {
+	return &webhookRepositoryImpl{db: db}
+}
+
+// Create は購読を作成します
+func (r *webhookRepositoryImpl) Create(ctx context.Context, sub *models.WebhookSubscription) error {
+	if err := r.db.WithContext(ctx).Create(sub).Error; err != nil {
+		return fmt.Errorf("webhookRepository.Create: %w", err)
+	}
+	return nil
+}
+
+// FindByID は購読を取得します
+func (r *webhookRepositoryImpl) FindByID(ctx context.Context, id uuid.UUID) (*models.WebhookSubscription, error) {
+	var sub models.WebhookSubscription
+	if err := r.db.WithContext(ctx).First(&sub, "id = ?", id).Error; err != nil {
+		return nil, fmt.Errorf("webhookRepository.FindByID (id=%s): %w", id, err)
+	}
+	return &sub, nil
+}
+
+// FindByUserID はユーザーの購読一覧を取得します
+func (r *webhookRepositoryImpl) FindByUserID(ctx context.Context, userID uuid.UUID) ([]models.WebhookSubscription, error) {
+	var subs []models.WebhookSubscription
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at").Find(&subs).Error; err != nil {
+		return nil, fmt.Errorf("webhookRepository.FindByUserID (userID=%s): %w", userID, err)
+	}
+	return subs, nil
+}
+
+// FindActiveByEvent は指定したイベントを購読している有効な購読を取得します
+// event_types は jsonb 配列のため、包含演算子 (@>) で絞り込む
+func (r *webhookRepositoryImpl) FindActiveByEvent(ctx context.Context, userID uuid.UUID, eventType string) ([]models.WebhookSubscription, error) {
+	contains, err := json.Marshal([]string{eventType})
+	if err != nil {
+		return nil, fmt.Errorf("webhookRepository.FindActiveByEvent (marshal): %w", err)
+	}
+
+	var subs []models.WebhookSubscription
+	err = r.db.WithContext(ctx).
+		Where("user_id = ? AND active = ? AND event_types @> ?::jsonb", userID, true, string(contains)).
+		Find(&subs).Error
+	if err != nil {
+		return nil, fmt.Errorf("webhookRepository.FindActiveByEvent (userID=%s): %w", userID, err)
+	}
+	return subs, nil
+}
+
+// Update は購読を更新します
+func (r *webhookRepositoryImpl) Update(ctx context.Context, sub *models.WebhookSubscription) error {
+	err := r.db.WithContext(ctx).
+		Model(sub).
+		Select("url", "event_types", "active", "failure_count", "disabled_at").
+		Updates(sub).Error
+	if err != nil {
+		return fmt.Errorf("webhookRepository.Update (id=%s): %w", sub.ID, err)
+	}
+	return nil
+}
+
+// Delete は購読を削除します。配信ログも一緒に削除します
+func (r *webhookRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("subscription_id = ?", id).Delete(&models.WebhookDelivery{}).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&models.WebhookSubscription{}, "id = ?", id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("webhookRepository.Delete (id=%s): %w", id, err)
+	}
+	return nil
+}
+
+// RecordFailure は連続失敗回数を原子的に1増やします
+func (r *webhookRepositoryImpl) RecordFailure(ctx context.Context, id uuid.UUID) (int, error) {
+	var sub models.WebhookSubscription
+	result := r.db.WithContext(ctx).
+		Model(&sub).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "failure_count"}}}).
+		Where("id = ?", id).
+		UpdateColumn("failure_count", gorm.Expr("failure_count + 1"))
+
+	if result.Error != nil {
+		return 0, fmt.Errorf("webhookRepository.RecordFailure (id=%s): %w", id, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return 0, fmt.Errorf("webhookRepository.RecordFailure (id=%s): %w", id, gorm.ErrRecordNotFound)
+	}
+	return sub.FailureCount, nil
+}
+
+// ResetFailures は連続失敗回数を0に戻します
+func (r *webhookRepositoryImpl) ResetFailures(ctx context.Context, id uuid.UUID) error {
+	err := r.db.WithContext(ctx).
+		Model(&models.WebhookSubscription{}).
+		Where("id = ? AND failure_count <> 0", id).
+		UpdateColumn("failure_count", 0).Error
+	if err != nil {
+		return fmt.Errorf("webhookRepository.ResetFailures (id=%s): %w", id, err)
+	}
+	return nil
+}
+
+// Disable は購読を停止します
+func (r *webhookRepositoryImpl) Disable(ctx context.Context, id uuid.UUID, at time.Time) error {
+	err := r.db.WithContext(ctx).
+		Model(&models.WebhookSubscription{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"active": false, "disabled_at": at}).Error
+	if err != nil {
+		return fmt.Errorf("webhookRepository.Disable (id=%s): %w", id, err)
+	}
+	return nil
+}
+
+// CreateDelivery は配信ログを記録します
+func (r *webhookRepositoryImpl) CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	if err := r.db.WithContext(ctx).Create(delivery).Error; err != nil {
+		return fmt.Errorf("webhookRepository.CreateDelivery: %w", err)
+	}
+	return nil
+}
+
+// FindDeliveries は配信ログを新しい順に取得します
+func (r *webhookRepositoryImpl) FindDeliveries(ctx context.Context, subscriptionID uuid.UUID, limit int) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+	err := r.db.WithContext(ctx).
+		Where("subscription_id = ?", subscriptionID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&deliveries).Error
+	if err != nil {
+		return nil, fmt.Errorf("webhookRepository.FindDeliveries (subscriptionID=%s): %w", subscriptionID, err)
+	}
+	return deliveries, nil
+}
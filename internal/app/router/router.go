@@ -15,8 +15,18 @@ import (
 func SetupRouter(
 	authHandler *handler.AuthController,
 	taskHandler *handler.TaskHandler,
+	reminderHandler *handler.ReminderHandler,
 	notificationHandler *handler.NotificationHandler,
+	syncHandler *handler.SyncHandler,
+	preferenceHandler *handler.NotificationPreferenceHandler,
+	webhookHandler *handler.WebhookHandler,
+	chatHandler *handler.ChatIntegrationHandler,
+	pushHandler *handler.PushHandler,
+	deadLetterHandler *handler.DeadLetterHandler,
+	scheduledJobHandler *handler.ScheduledJobHandler,
+	wsTickets middleware.TicketRedeemer,
 	redisClient *redis.Client,
+	adminToken string,
 ) *gin.Engine {
 
 	// gin.Default() ではなく gin.New() を使用
@@ -50,19 +60,99 @@ func SetupRouter(
 			tasks.GET("/:id", taskHandler.GetTaskByID)
 			tasks.PUT("/:id", taskHandler.UpdateTask)
 			tasks.DELETE("/:id", taskHandler.DeleteTask)
+			tasks.POST("/:id/snooze", taskHandler.SnoozeTask)
+
+			// 期限リマインダー (期限の何分前に通知するか)
+			tasks.GET("/:id/reminders", reminderHandler.List)
+			tasks.PUT("/:id/reminders", reminderHandler.Replace)
 		}
 
-		// WebSocket エンドポイント
-		authGroup.GET("/ws", notificationHandler.HandleWS)
+		// WebSocket 接続用の使い捨てチケット発行
+		authGroup.POST("/ws/ticket", notificationHandler.IssueWSTicket)
 
 		// 通知関連
 		notifications := authGroup.Group("/notifications")
 		{
 			notifications.GET("", notificationHandler.GetNotifications)
+			notifications.GET("/unread-count", notificationHandler.GetUnreadCount)
+			notifications.POST("/read-all", notificationHandler.MarkAllAsRead)
 			notifications.PATCH("/:id/read", notificationHandler.MarkAsRead)
+			notifications.DELETE("/:id", notificationHandler.DeleteNotification)
+			notifications.POST("/:id/snooze", notificationHandler.Snooze)
+			notifications.POST("/:id/actions/:action", notificationHandler.PerformAction)
+
+			// 通知設定 (種別・チャネルごとの有効/無効、おやすみ時間帯)
+			notifications.GET("/preferences", preferenceHandler.Get)
+			notifications.PUT("/preferences", preferenceHandler.Update)
+		}
+
+		// Webhook (外部システムへのイベント送信)
+		webhooks := authGroup.Group("/webhooks")
+		{
+			webhooks.POST("", webhookHandler.Create)
+			webhooks.GET("", webhookHandler.List)
+			webhooks.GET("/:id", webhookHandler.Get)
+			webhooks.PUT("/:id", webhookHandler.Update)
+			webhooks.DELETE("/:id", webhookHandler.Delete)
+			webhooks.GET("/:id/deliveries", webhookHandler.ListDeliveries)
+			webhooks.POST("/:id/test", webhookHandler.SendTest)
+		}
+
+		// チャット連携 (Slack / Teams の Incoming Webhook への投稿)
+		chat := authGroup.Group("/integrations/chat")
+		{
+			chat.POST("", chatHandler.Create)
+			chat.GET("", chatHandler.List)
+			chat.PUT("/:id", chatHandler.Update)
+			chat.DELETE("/:id", chatHandler.Delete)
+			chat.POST("/:id/test", chatHandler.SendTest)
+		}
+
+		// Web Push (アプリを開いていないブラウザへの通知)
+		push := authGroup.Group("/push")
+		{
+			push.GET("/vapid-public-key", pushHandler.PublicKey)
+			push.POST("/subscriptions", pushHandler.Subscribe)
+			push.DELETE("/subscriptions", pushHandler.Unsubscribe)
+		}
+
+		// オフラインクライアント向けの差分同期
+		sync := authGroup.Group("/sync")
+		{
+			sync.GET("", syncHandler.Pull)
+			sync.POST("", syncHandler.Push)
+		}
+	}
+
+	// --- 運用者向けの管理ルート /admin (ADMIN_API_TOKEN が設定されている場合のみ) ---
+	if adminToken != "" {
+		admin := r.Group("/admin")
+		admin.Use(middleware.AdminAuthMiddleware(adminToken))
+		{
+			// DLQ (ワーカーが処理できなかったメッセージ) の確認・再投入・削除
+			if deadLetterHandler != nil {
+				admin.GET("/dlq", deadLetterHandler.List)
+				admin.POST("/dlq/redrive", deadLetterHandler.Redrive)
+				admin.DELETE("/dlq", deadLetterHandler.Purge)
+			}
+
+			// 定期ジョブの一覧・実行履歴・手動実行
+			if scheduledJobHandler != nil {
+				admin.GET("/jobs", scheduledJobHandler.List)
+				admin.GET("/jobs/:name/runs", scheduledJobHandler.Runs)
+				admin.POST("/jobs/:name/run", scheduledJobHandler.Trigger)
+			}
 		}
 	}
 
+	// --- WebSocket エンドポイント ---
+	// ブラウザは Authorization ヘッダーを付けられないため、チケットで認証する
+	r.GET("/ws",
+		middleware.WSAuthMiddleware(wsTickets),
+		middleware.RateLimiter(redisClient, 5, time.Minute),
+		notificationHandler.HandleWS,
+	)
+
 	slog.Info("Router setup completed") // 正常にルートが組まれた記録を残す
 	return r
 }
@@ -47,6 +47,7 @@ func (s *AuthServiceImpl) Signup(req *models.SignupRequest) (*models.User, error
 	user := &models.User{
 		Username: req.Username,
 		Password: hashedPassword,
+		Email:    req.Email,
 	}
 
 	// 4. DBに保存
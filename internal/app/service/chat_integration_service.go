@@ -0,0 +1,35 @@
+package service
+
+import (
+	"context"
+	"my-portfolio-2025/internal/app/models"
+	"my-portfolio-2025/internal/infrastructure/chat"
+
+	"github.com/google/uuid"
+)
+
+// ChatPoster はチャットツールへの投稿を抽象化します (chat.Client が実装)
+type ChatPoster interface {
+	Post(ctx context.Context, provider, webhookURL string, msg *chat.Message) error
+}
+
+// ChatIntegrationService はチャット連携 (Slack / Teams) の管理と投稿を扱います
+type ChatIntegrationService interface {
+	// Create は連携を作成します
+	Create(ctx context.Context, userID uuid.UUID, req *models.ChatIntegrationRequest) (*models.ChatIntegration, error)
+
+	// List はユーザーの連携一覧を返します
+	List(ctx context.Context, userID uuid.UUID) ([]models.ChatIntegration, error)
+
+	// Update は連携を更新します
+	Update(ctx context.Context, userID uuid.UUID, id uuid.UUID, req *models.ChatIntegrationRequest) (*models.ChatIntegration, error)
+
+	// Delete は連携を削除します
+	Delete(ctx context.Context, userID uuid.UUID, id uuid.UUID) error
+
+	// SendTest はテストメッセージを同期的に投稿し、その結果を返します
+	SendTest(ctx context.Context, userID uuid.UUID, id uuid.UUID) (*models.ChatTestResult, error)
+
+	// Notify は通知をその種別を投稿する全ての連携へ投稿します (chatNotifier から呼ばれます)
+	Notify(ctx context.Context, n *models.Notification) error
+}
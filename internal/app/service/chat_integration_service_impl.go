@@ -0,0 +1,205 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"my-portfolio-2025/internal/app/apperr"
+	"my-portfolio-2025/internal/app/models"
+	"my-portfolio-2025/internal/app/repository"
+	"my-portfolio-2025/internal/infrastructure/chat"
+	"my-portfolio-2025/internal/infrastructure/egress"
+	"my-portfolio-2025/pkg/utils"
+	"slices"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// chatColors は通知種別ごとのアクセントカラーです (Teams の themeColor)
+var chatColors = map[string]string{
+	models.NotificationTypeDeadline: "#E01E5A",
+	models.NotificationTypeOverdue:  "#B00020",
+	models.NotificationTypeMention:  "#1264A3",
+}
+
+type chatIntegrationServiceImpl struct {
+	repo   repository.ChatIntegrationRepository
+	poster ChatPoster
+	guard  *egress.Guard // webhook_url の検査 (Webhook と同じルール)
+}
+
+// NewChatIntegrationService は ChatIntegrationService の新しいインスタンスを作成します
+func NewChatIntegrationService(repo repository.ChatIntegrationRepository, poster ChatPoster) ChatIntegrationService {
+	return &chatIntegrationServiceImpl{repo: repo, poster: poster, guard: egress.Default}
+}
+
+// Create は連携を作成します
+func (s *chatIntegrationServiceImpl) Create(ctx context.Context, userID uuid.UUID, req *models.ChatIntegrationRequest) (*models.ChatIntegration, error) {
+	if req.WebhookURL == "" {
+		return nil, fmt.Errorf("%w: webhook_url is required", apperr.ErrValidation)
+	}
+	if err := s.validateRequest(ctx, req); err != nil {
+		return nil, err
+	}
+
+	integration := &models.ChatIntegration{
+		UserID:            userID,
+		Provider:          req.Provider,
+		Name:              req.Name,
+		WebhookURL:        req.WebhookURL,
+		NotificationTypes: chatNotificationTypes(req.NotificationTypes),
+		Active:            req.Active == nil || *req.Active,
+	}
+	if err := s.repo.Create(ctx, integration); err != nil {
+		return nil, fmt.Errorf("chatIntegrationService.Create: %w", err)
+	}
+	return integration, nil
+}
+
+// List は連携一覧を返します
+func (s *chatIntegrationServiceImpl) List(ctx context.Context, userID uuid.UUID) ([]models.ChatIntegration, error) {
+	integrations, err := s.repo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("chatIntegrationService.List: %w", err)
+	}
+	if integrations == nil {
+		integrations = []models.ChatIntegration{}
+	}
+	return integrations, nil
+}
+
+// Update は連携を更新します
+func (s *chatIntegrationServiceImpl) Update(ctx context.Context, userID uuid.UUID, id uuid.UUID, req *models.ChatIntegrationRequest) (*models.ChatIntegration, error) {
+	if err := s.validateRequest(ctx, req); err != nil {
+		return nil, err
+	}
+	integration, err := s.findOwned(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	integration.Provider = req.Provider
+	integration.Name = req.Name
+	if req.WebhookURL != "" {
+		integration.WebhookURL = req.WebhookURL
+	}
+	integration.NotificationTypes = chatNotificationTypes(req.NotificationTypes)
+	if req.Active != nil {
+		integration.Active = *req.Active
+	}
+
+	if err := s.repo.Update(ctx, integration); err != nil {
+		return nil, fmt.Errorf("chatIntegrationService.Update: %w", err)
+	}
+	return integration, nil
+}
+
+// Delete は連携を削除します
+func (s *chatIntegrationServiceImpl) Delete(ctx context.Context, userID uuid.UUID, id uuid.UUID) error {
+	if _, err := s.findOwned(ctx, userID, id); err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("chatIntegrationService.Delete: %w", err)
+	}
+	return nil
+}
+
+// SendTest はテストメッセージを投稿します
+// 投稿の失敗はエラーではなく結果として返します (URL の確認に使うため)
+func (s *chatIntegrationServiceImpl) SendTest(ctx context.Context, userID uuid.UUID, id uuid.UUID) (*models.ChatTestResult, error) {
+	integration, err := s.findOwned(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	msg := &chat.Message{
+		Title: "Kota Todo との連携テスト",
+		Text:  "このチャンネルに通知が届くように設定されました",
+	}
+	if err := s.poster.Post(ctx, integration.Provider, integration.WebhookURL, msg); err != nil {
+		return &models.ChatTestResult{Success: false, Error: err.Error()}, nil
+	}
+	return &models.ChatTestResult{Success: true}, nil
+}
+
+// Notify は通知を対象の連携へ投稿します
+// 1件の失敗で他の連携への投稿を止めないよう、エラーはまとめて返します
+func (s *chatIntegrationServiceImpl) Notify(ctx context.Context, n *models.Notification) error {
+	integrations, err := s.repo.FindActiveByType(ctx, n.UserID, n.Type)
+	if err != nil {
+		return fmt.Errorf("chatIntegrationService.Notify: %w", err)
+	}
+
+	msg := chatMessageFor(n)
+	var errs []error
+	for _, integration := range integrations {
+		if err := s.poster.Post(ctx, integration.Provider, integration.WebhookURL, msg); err != nil {
+			slog.Warn("Chat notification failed",
+				"integrationID", integration.ID,
+				"provider", integration.Provider,
+				"notificationID", n.ID,
+				"error", err,
+			)
+			errs = append(errs, err)
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("chatIntegrationService.Notify: %w", err)
+	}
+	return nil
+}
+
+// findOwned は連携を取得し、所有者を確認します
+// 他人の連携は存在を隠すため NotFound として扱う
+func (s *chatIntegrationServiceImpl) findOwned(ctx context.Context, userID uuid.UUID, id uuid.UUID) (*models.ChatIntegration, error) {
+	integration, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: chatIntegrationID %s", apperr.ErrNotFound, id)
+		}
+		return nil, fmt.Errorf("chatIntegrationService.findOwned: %w", err)
+	}
+	if integration.UserID != userID {
+		return nil, fmt.Errorf("%w: chatIntegrationID %s for userID %s", apperr.ErrNotFound, id, userID)
+	}
+	return integration, nil
+}
+
+// chatMessageFor は通知からチャットメッセージを作成します
+func chatMessageFor(n *models.Notification) *chat.Message {
+	return &chat.Message{
+		Title:  notificationTitle(n.Type),
+		Text:   n.Message,
+		Fields: []chat.Field{{Name: "日時", Value: n.CreatedAt.In(utils.JST).Format(emailTimeLayout)}},
+		Color:  chatColors[n.Type],
+	}
+}
+
+func chatNotificationTypes(types []string) []string {
+	if len(types) == 0 {
+		return slices.Clone(models.ChatIntegrationDefaultTypes)
+	}
+	return types
+}
+
+// validateRequest は連携の内容を検証します
+// webhook_url は Webhook の購読と同じく https のみとし、内部アドレスは拒否する
+func (s *chatIntegrationServiceImpl) validateRequest(ctx context.Context, req *models.ChatIntegrationRequest) error {
+	if !slices.Contains(chat.Providers, req.Provider) {
+		return fmt.Errorf("%w: unknown provider %q", apperr.ErrValidation, req.Provider)
+	}
+	if req.WebhookURL != "" {
+		if err := s.guard.ValidateURL(ctx, req.WebhookURL); err != nil {
+			return fmt.Errorf("%w: webhook_url: %v", apperr.ErrValidation, err)
+		}
+	}
+	for _, t := range req.NotificationTypes {
+		if !slices.Contains(models.NotificationTypes, t) {
+			return fmt.Errorf("%w: unknown notification type %q", apperr.ErrValidation, t)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"my-portfolio-2025/internal/app/apperr"
+	"my-portfolio-2025/internal/app/models"
+	"my-portfolio-2025/internal/infrastructure/chat"
+	"my-portfolio-2025/internal/infrastructure/egress"
+	"my-portfolio-2025/internal/testutils/mock"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	mockPkg "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// chatStub は Incoming Webhook を模したローカルの HTTP サーバーです
+type chatStub struct {
+	*httptest.Server
+	mu       sync.Mutex
+	payloads []map[string]interface{}
+}
+
+func newChatStub(t *testing.T, status int) *chatStub {
+	s := &chatStub{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err == nil {
+			s.mu.Lock()
+			s.payloads = append(s.payloads, payload)
+			s.mu.Unlock()
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func TestChatNotify_PostsToEachIntegration(t *testing.T) {
+	userID := uuid.New()
+	slack := newChatStub(t, http.StatusOK)
+	teams := newChatStub(t, http.StatusOK)
+	broken := newChatStub(t, http.StatusNotFound)
+
+	repo := new(mock.MockChatIntegrationRepository)
+	repo.On("FindActiveByType", mockPkg.Anything, userID, models.NotificationTypeDeadline).Return([]models.ChatIntegration{
+		{ID: uuid.New(), UserID: userID, Provider: chat.ProviderSlack, WebhookURL: broken.URL},
+		{ID: uuid.New(), UserID: userID, Provider: chat.ProviderSlack, WebhookURL: slack.URL},
+		{ID: uuid.New(), UserID: userID, Provider: chat.ProviderTeams, WebhookURL: teams.URL},
+	}, nil)
+
+	n := &models.Notification{ID: uuid.New(), UserID: userID, Type: models.NotificationTypeDeadline, Message: "タスク「資料作成」の期限が近づいています", CreatedAt: time.Now()}
+	err := NewChatNotifier(NewChatIntegrationService(repo, chat.NewClient(localEgress))).Notify(context.Background(), n)
+
+	assert.Error(t, err, "失敗した連携があればエラーを返す")
+	require.Len(t, slack.payloads, 1, "1件の失敗で他の連携への投稿は止めない")
+	assert.Contains(t, slack.payloads[0], "blocks")
+	require.Len(t, teams.payloads, 1)
+	assert.Equal(t, "MessageCard", teams.payloads[0]["@type"])
+	repo.AssertExpectations(t)
+}
+
+// publicDNSEgress はどのホスト名も公開アドレスに解決する (外部の DNS に依存しない) 検査です
+var publicDNSEgress = &egress.Guard{
+	LookupIPAddr: func(ctx context.Context, host string) ([]net.IPAddr, error) {
+		return []net.IPAddr{{IP: net.ParseIP("93.184.216.34")}}, nil
+	},
+}
+
+func TestChatIntegrationCreate(t *testing.T) {
+	userID := uuid.New()
+
+	tests := []struct {
+		name          string
+		req           models.ChatIntegrationRequest
+		expectedTypes []string
+		expectedErr   error
+	}{
+		{
+			name:          "正常系：通知種別の省略時は期限とメンション",
+			req:           models.ChatIntegrationRequest{Provider: chat.ProviderSlack, WebhookURL: "https://hooks.slack.com/services/T/B/X"},
+			expectedTypes: []string{models.NotificationTypeDeadline, models.NotificationTypeMention},
+		},
+		{
+			name:        "異常系：URL は必須",
+			req:         models.ChatIntegrationRequest{Provider: chat.ProviderTeams},
+			expectedErr: apperr.ErrValidation,
+		},
+		{
+			name:        "異常系：http の URL",
+			req:         models.ChatIntegrationRequest{Provider: chat.ProviderSlack, WebhookURL: "http://hooks.slack.com/services/T/B/X"},
+			expectedErr: apperr.ErrValidation,
+		},
+		{
+			name:        "異常系：メタデータエンドポイント",
+			req:         models.ChatIntegrationRequest{Provider: chat.ProviderSlack, WebhookURL: "https://169.254.169.254/latest/meta-data/"},
+			expectedErr: apperr.ErrValidation,
+		},
+		{
+			name:        "異常系：プライベートアドレス",
+			req:         models.ChatIntegrationRequest{Provider: chat.ProviderTeams, WebhookURL: "https://192.168.0.10/hook"},
+			expectedErr: apperr.ErrValidation,
+		},
+		{
+			name:        "異常系：未知の通知種別",
+			req:         models.ChatIntegrationRequest{Provider: chat.ProviderTeams, WebhookURL: "https://example.com/hook", NotificationTypes: []string{"unknown"}},
+			expectedErr: apperr.ErrValidation,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mock.MockChatIntegrationRepository)
+			repo.On("Create", mockPkg.Anything, mockPkg.AnythingOfType("*models.ChatIntegration")).Return(nil).Maybe()
+
+			svc := NewChatIntegrationService(repo, chat.NewClient(egress.Default)).(*chatIntegrationServiceImpl)
+			svc.guard = publicDNSEgress
+			integration, err := svc.Create(context.Background(), userID, &tt.req)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				repo.AssertNotCalled(t, "Create", mockPkg.Anything, mockPkg.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedTypes, integration.NotificationTypes)
+			assert.True(t, integration.Active)
+		})
+	}
+}
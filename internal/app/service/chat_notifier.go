@@ -0,0 +1,23 @@
+package service
+
+import (
+	"context"
+	"my-portfolio-2025/internal/app/models"
+)
+
+// chatNotifier は通知をチャットツール (Slack / Teams) へ投稿します
+type chatNotifier struct {
+	integrations ChatIntegrationService
+}
+
+// NewChatNotifier はチャットチャネルの Notifier を作成します
+func NewChatNotifier(integrations ChatIntegrationService) Notifier {
+	return &chatNotifier{integrations: integrations}
+}
+
+func (c *chatNotifier) Channel() string { return models.NotificationChannelChat }
+
+// Notify は通知の種別を投稿対象にしている連携へ投稿します
+func (c *chatNotifier) Notify(ctx context.Context, n *models.Notification) error {
+	return c.integrations.Notify(ctx, n)
+}
@@ -0,0 +1,18 @@
+package service
+
+import (
+	"context"
+	"my-portfolio-2025/internal/app/models"
+)
+
+// DeadLetterService はワーカーが処理できずに DLQ へ送られたメッセージを管理します (管理用 CLI / API)
+type DeadLetterService interface {
+	// List は DLQ のメッセージを最大 limit 件、封筒をデコードして返します
+	List(ctx context.Context, limit int) (*models.DeadLetterList, error)
+
+	// Redrive は指定したメッセージ (All の場合はすべて) をメインキューへ戻します
+	Redrive(ctx context.Context, req *models.DeadLetterRedriveRequest) (*models.DeadLetterRedriveResult, error)
+
+	// Purge は DLQ のメッセージをすべて削除します
+	Purge(ctx context.Context) error
+}
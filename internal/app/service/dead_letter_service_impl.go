@@ -0,0 +1,218 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"my-portfolio-2025/internal/app/apperr"
+	"my-portfolio-2025/internal/app/models"
+	"my-portfolio-2025/internal/infrastructure/queue"
+	"time"
+)
+
+const (
+	// DLQ の一覧で返すメッセージの既定数と上限
+	deadLetterDefaultLimit = 20
+	deadLetterMaxLimit     = 100
+	// 一覧・再投入のために受信したメッセージを他の操作から隠しておく時間。終わったらすぐに見えるように戻す
+	deadLetterHoldTimeout = 30 * time.Second
+)
+
+type deadLetterService struct {
+	dlq queue.DeadLetterQueue
+	// 再投入先のメインキュー
+	target JobQueue
+}
+
+// NewDeadLetterService は DeadLetterService を作成します
+func NewDeadLetterService(dlq queue.DeadLetterQueue, target JobQueue) DeadLetterService {
+	return &deadLetterService{dlq: dlq, target: target}
+}
+
+func (s *deadLetterService) List(ctx context.Context, limit int) (*models.DeadLetterList, error) {
+	if limit <= 0 {
+		limit = deadLetterDefaultLimit
+	}
+	limit = min(limit, deadLetterMaxLimit)
+
+	count, err := s.dlq.Count(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("deadLetterService.List: %w", err)
+	}
+
+	list := &models.DeadLetterList{ApproximateCount: count, Messages: []models.DeadLetterMessage{}}
+	err = s.scan(ctx, func(msg queue.Message) bool {
+		list.Messages = append(list.Messages, decodeDeadLetter(msg))
+		return false
+	}, func() bool {
+		return len(list.Messages) >= limit
+	})
+	if err != nil {
+		return nil, fmt.Errorf("deadLetterService.List: %w", err)
+	}
+	return list, nil
+}
+
+// decodeDeadLetter は DLQ のメッセージの封筒をデコードします
+func decodeDeadLetter(msg queue.Message) models.DeadLetterMessage {
+	m := models.DeadLetterMessage{ID: msg.ID, ReceiveCount: msg.ReceiveCount, Body: msg.Body}
+	env, err := decodeJobEnvelope(msg.Body)
+	if err != nil {
+		m.DecodeError = err.Error()
+	} else {
+		m.Envelope = env
+	}
+	return m
+}
+
+func (s *deadLetterService) Redrive(ctx context.Context, req *models.DeadLetterRedriveRequest) (*models.DeadLetterRedriveResult, error) {
+	if req.All == (len(req.IDs) > 0) {
+		return nil, fmt.Errorf("%w: specify either ids or all", apperr.ErrValidation)
+	}
+	var patch map[string]any
+	if len(req.Patch) > 0 {
+		if err := json.Unmarshal(req.Patch, &patch); err != nil || patch == nil {
+			return nil, fmt.Errorf("%w: patch must be a JSON object", apperr.ErrValidation)
+		}
+	}
+
+	wanted := make(map[string]bool, len(req.IDs))
+	for _, id := range req.IDs {
+		wanted[id] = true
+	}
+
+	result := &models.DeadLetterRedriveResult{Redriven: []string{}}
+	err := s.scan(ctx, func(msg queue.Message) bool {
+		if !req.All && !wanted[msg.ID] {
+			return false
+		}
+		delete(wanted, msg.ID)
+
+		if err := s.redrive(ctx, msg, patch); err != nil {
+			slog.Error("Failed to redrive dead-letter message", "messageID", msg.ID, "error", err)
+			result.Failed = append(result.Failed, models.DeadLetterFailure{ID: msg.ID, Error: err.Error()})
+			return false
+		}
+		result.Redriven = append(result.Redriven, msg.ID)
+		return true
+	}, func() bool {
+		return !req.All && len(wanted) == 0
+	})
+	if err != nil {
+		return result, fmt.Errorf("deadLetterService.Redrive: %w", err)
+	}
+
+	for _, id := range req.IDs {
+		if wanted[id] {
+			result.NotFound = append(result.NotFound, id)
+		}
+	}
+	slog.Info("Redrove dead-letter messages", "redriven", len(result.Redriven), "failed", len(result.Failed), "notFound", len(result.NotFound))
+	return result, nil
+}
+
+// redrive はメッセージを (patch を適用して) メインキューへ送り、DLQ から削除します
+func (s *deadLetterService) redrive(ctx context.Context, msg queue.Message, patch map[string]any) error {
+	body := []byte(msg.Body)
+	if patch != nil {
+		patched, err := applyMergePatch(body, patch)
+		if err != nil {
+			return err
+		}
+		body = patched
+	}
+
+	if err := s.target.Enqueue(ctx, body, 0); err != nil {
+		return fmt.Errorf("enqueue to main queue: %w", err)
+	}
+	// メインキューには送ったため、削除に失敗しても DLQ に残るだけ (再び戻しても処理済みジョブの台帳で重複排除される)
+	if err := s.dlq.Ack(ctx, []queue.Message{msg}); err != nil {
+		return fmt.Errorf("sent to main queue but failed to delete from DLQ: %w", err)
+	}
+	return nil
+}
+
+func (s *deadLetterService) Purge(ctx context.Context) error {
+	if err := s.dlq.Purge(ctx); err != nil {
+		return fmt.Errorf("deadLetterService.Purge: %w", err)
+	}
+	slog.Warn("Purged dead-letter queue")
+	return nil
+}
+
+// scan は DLQ のメッセージを受信して visit に渡し、done が true を返すかメッセージが尽きるまで繰り返します
+// visit が true を返したメッセージ (DLQ から削除したもの) 以外は、最後にすぐ受信できるように戻す
+// 受信したメッセージは deadLetterHoldTimeout の間隠しておくため、同じメッセージを2回 visit に渡すことはない
+func (s *deadLetterService) scan(ctx context.Context, visit func(msg queue.Message) bool, done func() bool) error {
+	var held []queue.Message
+	defer func() {
+		// 呼び出し元の Context が切れていても戻せるよう、キャンセルされない Context を使う
+		releaseCtx := context.WithoutCancel(ctx)
+		for _, msg := range held {
+			if err := s.dlq.Nack(releaseCtx, msg, 0); err != nil {
+				slog.Warn("Failed to release dead-letter message", "messageID", msg.ID, "error", err)
+			}
+		}
+	}()
+
+	seen := make(map[string]bool)
+	for !done() {
+		msgs, err := s.dlq.Receive(ctx, 10, deadLetterHoldTimeout)
+		if err != nil {
+			return err
+		}
+
+		fresh := 0
+		for _, msg := range msgs {
+			if seen[msg.ID] {
+				// SQS の重複配信。同じメッセージの別の受信ハンドルも戻す
+				held = append(held, msg)
+				continue
+			}
+			seen[msg.ID] = true
+			fresh++
+			if done() || !visit(msg) {
+				held = append(held, msg)
+			}
+		}
+		if fresh == 0 {
+			return nil
+		}
+	}
+	return nil
+}
+
+// applyMergePatch は JSON の本文に JSON Merge Patch (RFC 7386) を適用します
+func applyMergePatch(body []byte, patch map[string]any) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	// ID などの大きな数値を丸めないよう json.Number のまま扱う
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, errors.New("body is not valid JSON; cannot apply patch")
+	}
+	return json.Marshal(mergePatch(doc, patch))
+}
+
+// mergePatch は target に patch を再帰的にマージします。patch の null はキーの削除を表す
+func mergePatch(target any, patch any) any {
+	p, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	t, ok := target.(map[string]any)
+	if !ok {
+		t = make(map[string]any, len(p))
+	}
+	for k, v := range p {
+		if v == nil {
+			delete(t, k)
+			continue
+		}
+		t[k] = mergePatch(t[k], v)
+	}
+	return t
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"my-portfolio-2025/internal/app/apperr"
+	"my-portfolio-2025/internal/app/models"
+	"my-portfolio-2025/internal/infrastructure/queue"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDeadLetterQueue はメッセージを受信中 (held) かどうかだけを管理するテスト用の DLQ です
+type fakeDeadLetterQueue struct {
+	mu     sync.Mutex
+	msgs   []queue.Message
+	held   map[string]bool
+	purged bool
+}
+
+func newFakeDeadLetterQueue(bodies ...string) *fakeDeadLetterQueue {
+	q := &fakeDeadLetterQueue{held: make(map[string]bool)}
+	for i, body := range bodies {
+		id := string(rune('a' + i))
+		q.msgs = append(q.msgs, queue.Message{ID: id, Handle: "h-" + id, Body: body, ReceiveCount: 5})
+	}
+	return q
+}
+
+func (q *fakeDeadLetterQueue) Receive(ctx context.Context, max int, visibility time.Duration) ([]queue.Message, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	var msgs []queue.Message
+	for _, m := range q.msgs {
+		if len(msgs) == max {
+			break
+		}
+		if !q.held[m.ID] {
+			q.held[m.ID] = true
+			msgs = append(msgs, m)
+		}
+	}
+	return msgs, nil
+}
+
+func (q *fakeDeadLetterQueue) Ack(ctx context.Context, msgs []queue.Message) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, acked := range msgs {
+		for i, m := range q.msgs {
+			if m.ID == acked.ID {
+				q.msgs = append(q.msgs[:i], q.msgs[i+1:]...)
+				break
+			}
+		}
+		delete(q.held, acked.ID)
+	}
+	return nil
+}
+
+func (q *fakeDeadLetterQueue) Nack(ctx context.Context, msg queue.Message, delay time.Duration) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	delete(q.held, msg.ID)
+	return nil
+}
+
+func (q *fakeDeadLetterQueue) Count(ctx context.Context) (int, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.msgs), nil
+}
+
+func (q *fakeDeadLetterQueue) Purge(ctx context.Context) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.msgs = nil
+	q.purged = true
+	return nil
+}
+
+const deadLetterEnvelopeBody = `{"type":"notification","version":1,"id":"0b5e4c1a-8c4f-4d6e-9a55-1f3c7d9e2b10","created_at":"2026-05-12T09:00:00+09:00","payload":{"user_id":"7b0c2f4e-1d3a-4b5c-8e6f-9a0b1c2d3e4f","type":"system","message":"hello"}}`
+
+func TestDeadLetterList_DecodesEnvelopesAndReleasesMessages(t *testing.T) {
+	dlq := newFakeDeadLetterQueue(deadLetterEnvelopeBody, `{"user_id":"7b0c2f4e-1d3a-4b5c-8e6f-9a0b1c2d3e4f","type":"system"}`, `{"user_id":`)
+	svc := NewDeadLetterService(dlq, &fakeJobQueue{})
+
+	list, err := svc.List(context.Background(), 10)
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, list.ApproximateCount)
+	require.Len(t, list.Messages, 3)
+	assert.Equal(t, models.JobTypeNotification, list.Messages[0].Envelope.Type)
+	assert.Equal(t, 5, list.Messages[0].ReceiveCount)
+	assert.Equal(t, models.JobTypeNotification, list.Messages[1].Envelope.Type, "封筒の導入前のメッセージも封筒として表示する")
+	assert.Nil(t, list.Messages[2].Envelope)
+	assert.NotEmpty(t, list.Messages[2].DecodeError)
+	assert.Empty(t, dlq.held, "一覧を返したらすぐに受信できるように戻す")
+}
+
+func TestDeadLetterList_StopsAtLimit(t *testing.T) {
+	dlq := newFakeDeadLetterQueue("1", "2", "3")
+
+	list, err := NewDeadLetterService(dlq, &fakeJobQueue{}).List(context.Background(), 2)
+	require.NoError(t, err)
+	assert.Len(t, list.Messages, 2)
+	assert.Empty(t, dlq.held)
+}
+
+func TestDeadLetterRedrive_SelectedWithPatch(t *testing.T) {
+	dlq := newFakeDeadLetterQueue(deadLetterEnvelopeBody, `{"n":2}`)
+	target := &fakeJobQueue{}
+	svc := NewDeadLetterService(dlq, target)
+
+	result, err := svc.Redrive(context.Background(), &models.DeadLetterRedriveRequest{
+		IDs:   []string{"a", "missing"},
+		Patch: json.RawMessage(`{"payload":{"message":"fixed","type":null}}`),
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"a"}, result.Redriven)
+	assert.Equal(t, []string{"missing"}, result.NotFound)
+	require.Len(t, target.bodies, 1)
+
+	var env models.JobEnvelope
+	require.NoError(t, json.Unmarshal(target.bodies[0], &env))
+	assert.Equal(t, "0b5e4c1a-8c4f-4d6e-9a55-1f3c7d9e2b10", env.ID.String(), "ジョブIDは変えない")
+	assert.JSONEq(t, `{"user_id":"7b0c2f4e-1d3a-4b5c-8e6f-9a0b1c2d3e4f","message":"fixed"}`, string(env.Payload))
+
+	// 戻したメッセージだけ DLQ から削除し、残りは受信できるように戻す
+	require.Len(t, dlq.msgs, 1)
+	assert.Equal(t, "b", dlq.msgs[0].ID)
+	assert.Empty(t, dlq.held)
+}
+
+func TestDeadLetterRedrive_AllKeepsFailedMessages(t *testing.T) {
+	dlq := newFakeDeadLetterQueue(`{"n":1}`, `{"n":2}`, `{"n":3}`)
+	target := &failingJobQueue{fail: `{"n":2}`}
+
+	result, err := NewDeadLetterService(dlq, target).Redrive(context.Background(), &models.DeadLetterRedriveRequest{All: true})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"a", "c"}, result.Redriven)
+	require.Len(t, result.Failed, 1)
+	assert.Equal(t, "b", result.Failed[0].ID)
+	require.Len(t, dlq.msgs, 1, "送れなかったメッセージは DLQ に残す")
+	assert.Empty(t, dlq.held)
+}
+
+func TestDeadLetterRedrive_Validation(t *testing.T) {
+	svc := NewDeadLetterService(newFakeDeadLetterQueue(), &fakeJobQueue{})
+
+	tests := []struct {
+		name string
+		req  models.DeadLetterRedriveRequest
+	}{
+		{name: "ids も all もない", req: models.DeadLetterRedriveRequest{}},
+		{name: "ids と all の両方", req: models.DeadLetterRedriveRequest{IDs: []string{"a"}, All: true}},
+		{name: "patch がオブジェクトでない", req: models.DeadLetterRedriveRequest{All: true, Patch: json.RawMessage(`[1]`)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.Redrive(context.Background(), &tt.req)
+			assert.True(t, errors.Is(err, apperr.ErrValidation))
+		})
+	}
+}
+
+func TestDeadLetterRedrive_PatchRequiresJSONBody(t *testing.T) {
+	dlq := newFakeDeadLetterQueue(`not json`)
+
+	result, err := NewDeadLetterService(dlq, &fakeJobQueue{}).Redrive(context.Background(), &models.DeadLetterRedriveRequest{
+		IDs:   []string{"a"},
+		Patch: json.RawMessage(`{"version":1}`),
+	})
+	require.NoError(t, err)
+	assert.Empty(t, result.Redriven)
+	require.Len(t, result.Failed, 1)
+	assert.Len(t, dlq.msgs, 1)
+}
@@ -0,0 +1,12 @@
+package service
+
+import (
+	"context"
+	"time"
+)
+
+// DigestService はダイジェスト設定のユーザーへ未読通知のまとめメールを送ります
+type DigestService interface {
+	// SendDueDigests は送信時刻を過ぎたユーザーのダイジェストを送信します (WorkerServiceから定期的に呼ばれます)
+	SendDueDigests(ctx context.Context, now time.Time) error
+}
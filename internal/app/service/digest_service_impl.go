@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"my-portfolio-2025/internal/app/models"
+	"my-portfolio-2025/internal/app/repository"
+	"my-portfolio-2025/internal/infrastructure/mail"
+	"time"
+)
+
+// ダイジェストを送信する時刻 (ユーザーのタイムゾーンでの時)
+const digestHour = 8
+
+// 1通のダイジェストに含める通知の最大件数
+const digestMaxItems = 50
+
+type digestServiceImpl struct {
+	prefRepo repository.NotificationPreferenceRepository
+	notiRepo repository.NotificationRepository
+	userRepo repository.UserRepository
+	sender   mail.Sender
+}
+
+// NewDigestService は DigestService の新しいインスタンスを作成します
+func NewDigestService(prefRepo repository.NotificationPreferenceRepository, notiRepo repository.NotificationRepository, userRepo repository.UserRepository, sender mail.Sender) DigestService {
+	return &digestServiceImpl{prefRepo: prefRepo, notiRepo: notiRepo, userRepo: userRepo, sender: sender}
+}
+
+// SendDueDigests はダイジェストの送信時刻を過ぎたユーザーへまとめメールを送ります
+// 1ユーザーの失敗で他のユーザーへの送信を止めないよう、個別のエラーはログに留める
+func (s *digestServiceImpl) SendDueDigests(ctx context.Context, now time.Time) error {
+	prefs, err := s.prefRepo.FindDigestSubscribers(ctx)
+	if err != nil {
+		return fmt.Errorf("digestService.SendDueDigests: %w", err)
+	}
+
+	for i := range prefs {
+		if err := s.sendDigest(ctx, &prefs[i], now); err != nil {
+			slog.Error("Failed to send digest", "userID", prefs[i].UserID, "error", err)
+		}
+	}
+	return nil
+}
+
+// sendDigest は1ユーザー分のダイジェストを送ります
+func (s *digestServiceImpl) sendDigest(ctx context.Context, pref *models.NotificationPreference, now time.Time) error {
+	scheduled, ok := lastDigestSchedule(pref, now)
+	if !ok || (pref.LastDigestAt != nil && !pref.LastDigestAt.Before(scheduled)) {
+		return nil
+	}
+
+	// 先に送信済みとして記録し、複数のワーカーが同じダイジェストを送らないようにする
+	// (送信に失敗した場合、その回のダイジェストは送られない: at-most-once)
+	claimed, err := s.prefRepo.MarkDigestSent(ctx, pref.UserID, pref.LastDigestAt, now)
+	if err != nil || !claimed {
+		return err
+	}
+
+	// 集計期間は前回の送信以降。初回は1周期分とする
+	since := scheduled.Add(-digestInterval(pref.EmailDigest))
+	if pref.LastDigestAt != nil {
+		since = *pref.LastDigestAt
+	}
+	unread := false
+	notifications, err := s.notiRepo.FindByUserID(ctx, pref.UserID, models.NotificationFilter{
+		IsRead:       &unread,
+		CreatedAfter: &since,
+		Limit:        digestMaxItems,
+	})
+	if err != nil {
+		return fmt.Errorf("digestService.sendDigest: %w", err)
+	}
+
+	items := make([]models.Notification, 0, len(notifications))
+	for _, n := range notifications {
+		if ruleEnabled(pref.Rules, n.Type, models.NotificationChannelEmail) {
+			items = append(items, n)
+		}
+	}
+	if len(items) == 0 {
+		return nil
+	}
+
+	user, err := s.userRepo.FindByID(pref.UserID)
+	if err != nil {
+		return fmt.Errorf("digestService.sendDigest: %w", err)
+	}
+	if user.Email == "" {
+		return nil
+	}
+
+	msg, err := renderDigestEmail(user, pref.EmailDigest, items)
+	if err != nil {
+		return fmt.Errorf("digestService.sendDigest (render): %w", err)
+	}
+	if err := s.sender.Send(ctx, msg); err != nil {
+		return fmt.Errorf("digestService.sendDigest: %w", err)
+	}
+
+	slog.Info("Digest sent", "userID", pref.UserID, "mode", pref.EmailDigest, "count", len(items))
+	return nil
+}
+
+// lastDigestSchedule は now 以前で直近のダイジェスト送信予定時刻を返します
+// daily は毎日 digestHour 時、weekly は毎週月曜の digestHour 時 (ユーザーのタイムゾーン)
+func lastDigestSchedule(pref *models.NotificationPreference, now time.Time) (time.Time, bool) {
+	if !digestEnabled(pref) {
+		return time.Time{}, false
+	}
+
+	loc, err := time.LoadLocation(pref.Timezone)
+	if err != nil {
+		loc, _ = time.LoadLocation(defaultPreferenceTimezone)
+	}
+	local := now.In(loc)
+	scheduled := time.Date(local.Year(), local.Month(), local.Day(), digestHour, 0, 0, 0, loc)
+	if local.Before(scheduled) {
+		scheduled = scheduled.AddDate(0, 0, -1)
+	}
+
+	if pref.EmailDigest == models.EmailDigestWeekly {
+		// 直近の月曜まで戻す
+		offset := (int(scheduled.Weekday()) - int(time.Monday) + 7) % 7
+		scheduled = scheduled.AddDate(0, 0, -offset)
+	}
+	return scheduled, true
+}
+
+// digestInterval はダイジェストの送信間隔です
+func digestInterval(mode string) time.Duration {
+	if mode == models.EmailDigestWeekly {
+		return 7 * 24 * time.Hour
+	}
+	return 24 * time.Hour
+}
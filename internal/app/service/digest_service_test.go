@@ -0,0 +1,62 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"my-portfolio-2025/internal/app/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLastDigestSchedule(t *testing.T) {
+	jst, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		mode     string
+		now      time.Time
+		expected time.Time
+	}{
+		{
+			name:     "daily：8時以降は当日8時",
+			mode:     models.EmailDigestDaily,
+			now:      time.Date(2026, 5, 13, 9, 30, 0, 0, jst),
+			expected: time.Date(2026, 5, 13, 8, 0, 0, 0, jst),
+		},
+		{
+			name:     "daily：8時前は前日8時",
+			mode:     models.EmailDigestDaily,
+			now:      time.Date(2026, 5, 13, 7, 59, 0, 0, jst),
+			expected: time.Date(2026, 5, 12, 8, 0, 0, 0, jst),
+		},
+		{
+			name:     "weekly：水曜は直前の月曜8時",
+			mode:     models.EmailDigestWeekly,
+			now:      time.Date(2026, 5, 13, 12, 0, 0, 0, jst), // 水曜
+			expected: time.Date(2026, 5, 11, 8, 0, 0, 0, jst),
+		},
+		{
+			name:     "weekly：月曜の8時前は前週の月曜8時",
+			mode:     models.EmailDigestWeekly,
+			now:      time.Date(2026, 5, 11, 7, 0, 0, 0, jst), // 月曜
+			expected: time.Date(2026, 5, 4, 8, 0, 0, 0, jst),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pref := &models.NotificationPreference{Timezone: "Asia/Tokyo", EmailDigest: tt.mode}
+
+			got, ok := lastDigestSchedule(pref, tt.now)
+
+			require.True(t, ok)
+			assert.True(t, tt.expected.Equal(got), "got %s", got)
+		})
+	}
+
+	_, ok := lastDigestSchedule(&models.NotificationPreference{EmailDigest: models.EmailDigestOff}, time.Now())
+	assert.False(t, ok, "off の場合はダイジェストを送らない")
+}
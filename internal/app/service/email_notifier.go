@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"my-portfolio-2025/internal/app/models"
+	"my-portfolio-2025/internal/app/repository"
+	"my-portfolio-2025/internal/infrastructure/mail"
+)
+
+// emailNotifier は通知を1件ずつメールで送信します
+type emailNotifier struct {
+	userRepo repository.UserRepository
+	sender   mail.Sender
+}
+
+// NewEmailNotifier はメールチャネルの Notifier を作成します
+func NewEmailNotifier(userRepo repository.UserRepository, sender mail.Sender) Notifier {
+	return &emailNotifier{userRepo: userRepo, sender: sender}
+}
+
+func (e *emailNotifier) Channel() string { return models.NotificationChannelEmail }
+
+// Notify は通知をメールで送信します。メールアドレス未登録のユーザーには送りません
+func (e *emailNotifier) Notify(ctx context.Context, n *models.Notification) error {
+	user, err := e.userRepo.FindByID(n.UserID)
+	if err != nil {
+		return fmt.Errorf("emailNotifier.Notify: %w", err)
+	}
+	if user.Email == "" {
+		slog.Debug("Email notification skipped: no address", "userID", n.UserID)
+		return nil
+	}
+
+	msg, err := renderNotificationEmail(user, n)
+	if err != nil {
+		return fmt.Errorf("emailNotifier.Notify (render): %w", err)
+	}
+	if err := e.sender.Send(ctx, msg); err != nil {
+		return fmt.Errorf("emailNotifier.Notify: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"mime"
+	"net"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"my-portfolio-2025/internal/app/models"
+	mailer "my-portfolio-2025/internal/infrastructure/mail"
+	"my-portfolio-2025/internal/testutils"
+	"my-portfolio-2025/internal/testutils/mock"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmailNotifier_SendsJapaneseMail(t *testing.T) {
+	server := testutils.NewFakeSMTPServer(t)
+	host, port, err := net.SplitHostPort(server.Addr)
+	require.NoError(t, err)
+
+	userID := uuid.New()
+	userRepo := new(mock.MockUserRepository)
+	userRepo.On("FindByID", userID).Return(&models.User{ID: userID, Username: "kota", Email: "kota@example.com"}, nil)
+
+	notifier := NewEmailNotifier(userRepo, mailer.NewSMTPSender(mailer.Config{Host: host, Port: port, From: "noreply@example.com"}))
+	n := &models.Notification{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Type:      models.NotificationTypeDeadline,
+		Message:   "タスク「資料作成」の期限が近づいています",
+		CreatedAt: time.Now(),
+	}
+
+	require.NoError(t, notifier.Notify(context.Background(), n))
+
+	mails := server.Mails()
+	require.Len(t, mails, 1)
+	assert.Equal(t, []string{"kota@example.com"}, mails[0].To)
+	parsed, err := mail.ReadMessage(strings.NewReader(mails[0].Data))
+	require.NoError(t, err)
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, notificationSubjects[models.NotificationTypeDeadline], subject)
+}
+
+func TestEmailNotifier_SkipsUserWithoutAddress(t *testing.T) {
+	userID := uuid.New()
+	userRepo := new(mock.MockUserRepository)
+	userRepo.On("FindByID", userID).Return(&models.User{ID: userID, Username: "kota"}, nil)
+
+	// 送信されると失敗する宛先 (接続先なし) を指定し、送信しないことを確認する
+	notifier := NewEmailNotifier(userRepo, mailer.NewSMTPSender(mailer.Config{Host: "127.0.0.1", Port: "1", From: "noreply@example.com"}))
+
+	err := notifier.Notify(context.Background(), &models.Notification{ID: uuid.New(), UserID: userID, Message: "hello"})
+
+	assert.NoError(t, err)
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"bytes"
+	"embed"
+	htmltemplate "html/template"
+	"my-portfolio-2025/internal/app/models"
+	"my-portfolio-2025/internal/infrastructure/mail"
+	"my-portfolio-2025/pkg/utils"
+	texttemplate "text/template"
+)
+
+//go:embed templates/email/*.tmpl
+var emailTemplateFS embed.FS
+
+var (
+	emailTextTemplates = texttemplate.Must(texttemplate.ParseFS(emailTemplateFS, "templates/email/*.txt.tmpl"))
+	emailHTMLTemplates = htmltemplate.Must(htmltemplate.ParseFS(emailTemplateFS, "templates/email/*.html.tmpl"))
+)
+
+// メールに表示する日時の書式 (JST)
+const emailTimeLayout = "2006/01/02 15:04"
+
+// emailItem はテンプレートに渡す通知1件分の表示用データです
+type emailItem struct {
+	Message   string
+	CreatedAt string
+}
+
+// notificationEmailData は notification テンプレートに渡すデータです
+type notificationEmailData struct {
+	Subject  string
+	Username string
+	emailItem
+}
+
+// digestEmailData は digest テンプレートに渡すデータです
+type digestEmailData struct {
+	Subject     string
+	Username    string
+	PeriodLabel string
+	Items       []emailItem
+}
+
+// notificationSubjects は通知種別ごとのメール件名です
+var notificationSubjects = map[string]string{
+	models.NotificationTypeDeadline: "【Kota Todo】タスクの期限が近づいています",
+	models.NotificationTypeOverdue:  "【Kota Todo】タスクの期限を過ぎています",
+	models.NotificationTypeMention:  "【Kota Todo】あなた宛てのメンションがあります",
+	models.NotificationTypeSystem:   "【Kota Todo】お知らせ",
+}
+
+// renderNotificationEmail は通知1件分のメールを作成します
+func renderNotificationEmail(user *models.User, n *models.Notification) (*mail.Message, error) {
+	subject, ok := notificationSubjects[n.Type]
+	if !ok {
+		subject = notificationSubjects[models.NotificationTypeSystem]
+	}
+
+	data := notificationEmailData{
+		Subject:   subject,
+		Username:  user.Username,
+		emailItem: toEmailItem(n),
+	}
+	return renderEmail(user.Email, subject, "notification", data)
+}
+
+// renderDigestEmail は未読通知をまとめたダイジェストメールを作成します
+func renderDigestEmail(user *models.User, mode string, notifications []models.Notification) (*mail.Message, error) {
+	label := "今日"
+	if mode == models.EmailDigestWeekly {
+		label = "今週"
+	}
+	subject := "【Kota Todo】" + label + "の未読通知のまとめ"
+
+	data := digestEmailData{
+		Subject:     subject,
+		Username:    user.Username,
+		PeriodLabel: label,
+		Items:       make([]emailItem, 0, len(notifications)),
+	}
+	for i := range notifications {
+		data.Items = append(data.Items, toEmailItem(&notifications[i]))
+	}
+	return renderEmail(user.Email, subject, "digest", data)
+}
+
+func renderEmail(to, subject, name string, data interface{}) (*mail.Message, error) {
+	var text, html bytes.Buffer
+	if err := emailTextTemplates.ExecuteTemplate(&text, name+".txt.tmpl", data); err != nil {
+		return nil, err
+	}
+	if err := emailHTMLTemplates.ExecuteTemplate(&html, name+".html.tmpl", data); err != nil {
+		return nil, err
+	}
+	return &mail.Message{To: to, Subject: subject, Text: text.String(), HTML: html.String()}, nil
+}
+
+func toEmailItem(n *models.Notification) emailItem {
+	return emailItem{
+		Message:   n.Message,
+		CreatedAt: n.CreatedAt.In(utils.JST).Format(emailTimeLayout),
+	}
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"my-portfolio-2025/internal/app/models"
+	"time"
+)
+
+// EscalationService は期限切れタスクへの督促 (overdue 通知) の送信対象を扱います
+type EscalationService interface {
+	// ClaimDue は送信時刻を迎えた督促を返します (WorkerService から呼ばれます)
+	// 返した督促は送信済みとして次回の送信時刻まで進めます
+	ClaimDue(ctx context.Context, now time.Time, limit int) ([]models.DueEscalation, error)
+
+	// Release は送信に失敗した督促を戻し、次の監視ループで再送させます
+	Release(ctx context.Context, escalation *models.TaskEscalation) error
+
+	// WithheldChannels は level 件目の overdue 通知でまだ使わないチャネルを返します
+	WithheldChannels(level int) []string
+
+	// PublishTaskEvent はタスクの期限変更・削除に合わせて督促をやり直します (TaskEventPublisher)
+	PublishTaskEvent(ctx context.Context, event *models.TaskEvent) error
+}
+
+// EscalationPolicy は期限切れタスクへの督促の方針です
+type EscalationPolicy struct {
+	// Backoff は overdue 通知の間隔です。1件目は期限からの経過時間、2件目以降は前回からの間隔で、最後の値を繰り返します
+	Backoff []time.Duration
+	// MaxNotifications は1つのタスクに送る overdue 通知の上限です
+	MaxNotifications int
+	// EscalationChannel は督促が続いた場合に追加する配信チャネルです。空の場合は追加しない
+	EscalationChannel string
+	// EscalateAfter は EscalationChannel にも送り始める overdue 通知の件数目です
+	EscalateAfter int
+}
+
+// DefaultEscalationPolicy は期限の1時間後、その4時間後、以降は1日ごとに計5回まで督促します
+func DefaultEscalationPolicy() EscalationPolicy {
+	return EscalationPolicy{
+		Backoff:          []time.Duration{time.Hour, 4 * time.Hour, 24 * time.Hour},
+		MaxNotifications: 5,
+		EscalateAfter:    3,
+	}
+}
+
+// interval は level 件目の overdue 通知から次の通知までの間隔を返します (level 0 は期限から1件目まで)
+func (p EscalationPolicy) interval(level int) time.Duration {
+	if len(p.Backoff) == 0 {
+		return 24 * time.Hour
+	}
+	return p.Backoff[min(level, len(p.Backoff)-1)]
+}
+
+// withheld は level 件目の overdue 通知でまだ使わないチャネルを返します
+func (p EscalationPolicy) withheld(level int) []string {
+	if p.EscalationChannel == "" || level >= p.EscalateAfter {
+		return nil
+	}
+	return []string{p.EscalationChannel}
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"my-portfolio-2025/internal/app/models"
+	"my-portfolio-2025/internal/app/repository"
+	"my-portfolio-2025/pkg/utils"
+	"slices"
+	"time"
+)
+
+type escalationServiceImpl struct {
+	repo     repository.TaskEscalationRepository
+	taskRepo repository.TaskRepository
+	policy   EscalationPolicy
+}
+
+// NewEscalationService は EscalationService の新しいインスタンスを作成します
+func NewEscalationService(repo repository.TaskEscalationRepository, taskRepo repository.TaskRepository, policy EscalationPolicy) EscalationService {
+	return &escalationServiceImpl{repo: repo, taskRepo: taskRepo, policy: policy}
+}
+
+// ClaimDue は期限切れのタスクの督促を開始し、送信時刻を迎えた督促を返します
+func (s *escalationServiceImpl) ClaimDue(ctx context.Context, now time.Time, limit int) ([]models.DueEscalation, error) {
+	if s.policy.MaxNotifications <= 0 {
+		return nil, nil
+	}
+	if err := s.repo.StartOverdue(ctx, now, s.policy.interval(0)); err != nil {
+		return nil, fmt.Errorf("escalationService.ClaimDue: %w", err)
+	}
+
+	next := func(level int) time.Time { return now.Add(s.policy.interval(level)) }
+	claimed, err := s.repo.ClaimDue(ctx, now, s.policy.MaxNotifications, limit, next)
+	if err != nil {
+		return nil, fmt.Errorf("escalationService.ClaimDue: %w", err)
+	}
+
+	due := make([]models.DueEscalation, 0, len(claimed))
+	for _, escalation := range claimed {
+		task, err := s.taskRepo.FindByID(escalation.TaskID)
+		if err != nil {
+			// 取得と同時にタスクが削除された場合など。送信済みのまま捨てる
+			slog.Warn("Escalation dropped: task not found", "taskID", escalation.TaskID, "error", err)
+			continue
+		}
+		due = append(due, models.DueEscalation{Escalation: escalation, Task: *task})
+	}
+	return due, nil
+}
+
+// Release は督促の Level を1つ戻します
+func (s *escalationServiceImpl) Release(ctx context.Context, escalation *models.TaskEscalation) error {
+	if err := s.repo.Unclaim(ctx, escalation.TaskID, escalation.Level, utils.NowJST()); err != nil {
+		return fmt.Errorf("escalationService.Release: %w", err)
+	}
+	return nil
+}
+
+// WithheldChannels は level 件目の overdue 通知でまだ使わないチャネルを返します
+func (s *escalationServiceImpl) WithheldChannels(level int) []string {
+	return s.policy.withheld(level)
+}
+
+// PublishTaskEvent は期限の変更・タスクの削除で督促状態を削除します
+// 期限が変わった場合、新しい期限を過ぎた時点で1件目から督促をやり直します
+func (s *escalationServiceImpl) PublishTaskEvent(ctx context.Context, event *models.TaskEvent) error {
+	switch {
+	case event.Type == models.WSEventTaskDeleted,
+		event.Type == models.WSEventTaskUpdated && slices.Contains(event.ChangedFields, "due_date"):
+		if err := s.repo.DeleteByTaskID(ctx, event.TaskID); err != nil {
+			return fmt.Errorf("escalationService.PublishTaskEvent: %w", err)
+		}
+	}
+	return nil
+}
+
+// overdueMessage は overdue 通知のメッセージを作成します
+func overdueMessage(task *models.Task, now time.Time) string {
+	due := task.DueDate.In(utils.JST).Format("01/02 15:04")
+	elapsed := formatReminderOffset(int(now.Sub(task.DueDate).Minutes()))
+	if elapsed == "" {
+		return fmt.Sprintf("タスク「%s」の期限を過ぎています（期限: %s）", task.Title, due)
+	}
+	return fmt.Sprintf("タスク「%s」の期限を過ぎています（期限: %s、%s超過）", task.Title, due, elapsed)
+}
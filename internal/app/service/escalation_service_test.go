@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"my-portfolio-2025/internal/app/models"
+	"my-portfolio-2025/internal/testutils/mock"
+	"my-portfolio-2025/pkg/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	mockPkg "github.com/stretchr/testify/mock"
+)
+
+func TestEscalationPolicy_Backoff(t *testing.T) {
+	policy := DefaultEscalationPolicy()
+
+	// 期限の1時間後に1件目、その4時間後に2件目、以降は1日ごと
+	assert.Equal(t, time.Hour, policy.interval(0))
+	assert.Equal(t, 4*time.Hour, policy.interval(1))
+	assert.Equal(t, 24*time.Hour, policy.interval(2))
+	assert.Equal(t, 24*time.Hour, policy.interval(7))
+}
+
+func TestEscalationPolicy_Withheld(t *testing.T) {
+	policy := DefaultEscalationPolicy()
+	assert.Nil(t, policy.withheld(1), "エスカレーション先が無ければ何も除かない")
+
+	policy.EscalationChannel = models.NotificationChannelEmail
+	policy.EscalateAfter = 3
+	assert.Equal(t, []string{models.NotificationChannelEmail}, policy.withheld(1))
+	assert.Equal(t, []string{models.NotificationChannelEmail}, policy.withheld(2))
+	assert.Nil(t, policy.withheld(3))
+	assert.Nil(t, policy.withheld(5))
+}
+
+func TestEscalationClaimDue(t *testing.T) {
+	now := time.Date(2026, 5, 12, 9, 0, 0, 0, utils.JST)
+	task := &models.Task{ID: uuid.New(), UserID: uuid.New(), Title: "資料作成", DueDate: now.Add(-5 * time.Hour)}
+
+	repo := new(mock.MockTaskEscalationRepository)
+	taskRepo := new(mock.MockTaskRepository)
+	repo.On("StartOverdue", mockPkg.Anything, now, time.Hour).Return(nil).Once()
+	repo.On("ClaimDue", mockPkg.Anything, now, 5, 10, mockPkg.MatchedBy(func(next func(int) time.Time) bool {
+		// 1件目を送った後は4時間後、2件目以降は1日後
+		return next(1).Equal(now.Add(4*time.Hour)) && next(2).Equal(now.Add(24*time.Hour))
+	})).Return([]models.TaskEscalation{{TaskID: task.ID, UserID: task.UserID, Level: 2}}, nil).Once()
+	taskRepo.On("FindByID", task.ID).Return(task, nil).Once()
+
+	svc := NewEscalationService(repo, taskRepo, DefaultEscalationPolicy())
+	due, err := svc.ClaimDue(context.Background(), now, 10)
+
+	assert.NoError(t, err)
+	if assert.Len(t, due, 1) {
+		assert.Equal(t, 2, due[0].Escalation.Level)
+		assert.Equal(t, task.ID, due[0].Task.ID)
+	}
+	repo.AssertExpectations(t)
+	taskRepo.AssertExpectations(t)
+}
+
+func TestEscalationClaimDue_Disabled(t *testing.T) {
+	repo := new(mock.MockTaskEscalationRepository)
+	policy := DefaultEscalationPolicy()
+	policy.MaxNotifications = 0
+
+	svc := NewEscalationService(repo, nil, policy)
+	due, err := svc.ClaimDue(context.Background(), utils.NowJST(), 10)
+
+	assert.NoError(t, err)
+	assert.Empty(t, due)
+	repo.AssertNotCalled(t, "StartOverdue", mockPkg.Anything, mockPkg.Anything, mockPkg.Anything)
+}
+
+func TestEscalationPublishTaskEvent(t *testing.T) {
+	taskID := uuid.New()
+
+	tests := []struct {
+		name    string
+		event   *models.TaskEvent
+		deleted bool
+	}{
+		{
+			name:    "期限の変更で督促をやり直す",
+			event:   &models.TaskEvent{Type: models.WSEventTaskUpdated, TaskID: taskID, ChangedFields: []string{"title", "due_date"}},
+			deleted: true,
+		},
+		{
+			name:    "タスクの削除で督促を削除する",
+			event:   &models.TaskEvent{Type: models.WSEventTaskDeleted, TaskID: taskID},
+			deleted: true,
+		},
+		{
+			name:  "期限以外の変更では何もしない",
+			event: &models.TaskEvent{Type: models.WSEventTaskUpdated, TaskID: taskID, ChangedFields: []string{"title"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mock.MockTaskEscalationRepository)
+			repo.On("DeleteByTaskID", mockPkg.Anything, taskID).Return(nil)
+
+			svc := NewEscalationService(repo, nil, DefaultEscalationPolicy())
+			assert.NoError(t, svc.PublishTaskEvent(context.Background(), tt.event))
+
+			if tt.deleted {
+				repo.AssertCalled(t, "DeleteByTaskID", mockPkg.Anything, taskID)
+			} else {
+				repo.AssertNotCalled(t, "DeleteByTaskID", mockPkg.Anything, mockPkg.Anything)
+			}
+		})
+	}
+}
+
+func TestOverdueMessage(t *testing.T) {
+	task := &models.Task{Title: "資料作成", DueDate: time.Date(2026, 5, 13, 18, 0, 0, 0, utils.JST)}
+
+	assert.Equal(t, "タスク「資料作成」の期限を過ぎています（期限: 05/13 18:00、1日5時間超過）", overdueMessage(task, task.DueDate.Add(29*time.Hour)))
+	assert.Equal(t, "タスク「資料作成」の期限を過ぎています（期限: 05/13 18:00）", overdueMessage(task, task.DueDate))
+}
@@ -7,6 +7,7 @@ import (
 
 	"my-portfolio-2025/internal/app/models"
 	"my-portfolio-2025/internal/infrastructure/aws"
+	"my-portfolio-2025/pkg/utils"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -24,12 +25,14 @@ func SetupTestDB(t *testing.T) *gorm.DB {
 	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
 		// Logger: logger.Default.LogMode(logger.Silent),
 		Logger: logger.Discard,
+		// 本番 (cmd/api) と同じく、自動で設定される時刻を JST にする
+		NowFunc: utils.NowJST,
 	})
 	if err != nil {
 		t.Fatalf("テストDBへの接続に失敗しました: %v", err)
 	}
 
-	err = db.AutoMigrate(&models.Task{}, &models.Notification{}, &models.User{})
+	err = db.AutoMigrate(&models.Task{}, &models.Notification{}, &models.User{}, &models.NotificationPreference{}, &models.NotificationRule{}, &models.WebhookSubscription{}, &models.WebhookDelivery{}, &models.ChatIntegration{}, &models.PushSubscription{}, &models.TaskReminder{}, &models.TaskEscalation{}, &models.OutboxMessage{}, &models.ProcessedJob{})
 	if err != nil {
 		t.Fatalf("マイグレーションに失敗しました: %v", err)
 	}
@@ -44,10 +47,12 @@ func SetupTestDB(t *testing.T) *gorm.DB {
 // SetupTestSQS はテスト用のSQSクライアントを初期化します
 func SetupTestSQS(t *testing.T) *aws.SQSClient {
 	ctx := context.Background()
-	queueName := "portfolio-notifications" // テスト用キュー名
+	// 未設定の場合は LocalStack のテスト用キューを使う (CI と同じキュー)
+	if os.Getenv("SQS_QUEUE_URL") == "" {
+		t.Setenv("SQS_QUEUE_URL", "http://localhost:4566/000000000000/portfolio-notifications")
+	}
 
-	// 先ほど修正したNewSQSClientを利用
-	client, err := aws.NewSQSClient(ctx, queueName)
+	client, err := aws.NewSQSClient(ctx)
 	if err != nil {
 		t.Fatalf("テスト用SQSの初期化に失敗しました: %v", err)
 	}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"my-portfolio-2025/internal/app/models"
+	"my-portfolio-2025/pkg/utils"
+	"time"
+)
+
+// Job はキューから受信したジョブです
+type Job struct {
+	Envelope *models.JobEnvelope
+	// MessageID はキューのメッセージID
+	MessageID string
+	// Redelivered は同じメッセージを以前にも受信したか (前回の処理が途中で失敗したか) を表します
+	Redelivered bool
+}
+
+// Decode は Payload をジョブ種別ごとの型にデコードします
+// デコードできないジョブは何度処理しても成功しないため、poison メッセージとして扱う
+func (j *Job) Decode(v interface{}) error {
+	if err := json.Unmarshal(j.Envelope.Payload, v); err != nil {
+		return fmt.Errorf("%w: decode %s job payload: %v", errPoisonMessage, j.Envelope.Type, err)
+	}
+	return nil
+}
+
+// JobHandler は1種類のジョブを処理します
+// エラーを返すとメッセージは再試行され、errPoisonMessage を包んだエラーの場合は DLQ へ送られます
+type JobHandler func(ctx context.Context, job *Job) error
+
+type jobRoute struct {
+	version int
+	handle  JobHandler
+}
+
+// JobRegistry はジョブ種別ごとのハンドラーの一覧です
+type JobRegistry struct {
+	routes map[string]jobRoute
+}
+
+// NewJobRegistry は空の JobRegistry を作成します
+func NewJobRegistry() *JobRegistry {
+	return &JobRegistry{routes: make(map[string]jobRoute)}
+}
+
+// Register は jobType のハンドラーを登録します。version には処理できる最新の封筒のバージョンを渡します
+func (r *JobRegistry) Register(jobType string, version int, handle JobHandler) {
+	r.routes[jobType] = jobRoute{version: version, handle: handle}
+}
+
+// Dispatch はジョブ種別に応じたハンドラーでジョブを処理します
+//   - 未知の種別: どのワーカーも処理できないため poison メッセージとして DLQ へ送る
+//   - 処理できるより新しいバージョン: 新しいワーカーへの入れ替え中の可能性があるため、エラーとして再試行させる
+func (r *JobRegistry) Dispatch(ctx context.Context, job *Job) error {
+	env := job.Envelope
+	route, ok := r.routes[env.Type]
+	if !ok {
+		return fmt.Errorf("%w: unknown job type %q", errPoisonMessage, env.Type)
+	}
+	if env.Version > route.version {
+		return fmt.Errorf("JobRegistry.Dispatch: %s job version %d is newer than supported version %d", env.Type, env.Version, route.version)
+	}
+	return route.handle(ctx, job)
+}
+
+// encodeJob は payload をジョブの封筒に入れ、キューに送る本文を作成します
+func encodeJob(jobType string, correlationID string, payload interface{}) ([]byte, error) {
+	env, err := models.NewJobEnvelope(jobType, correlationID, payload, utils.NowJST())
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(env)
+}
+
+// enqueueJob は payload をジョブの封筒に入れてキューに送ります
+func enqueueJob(ctx context.Context, queue JobQueue, jobType string, correlationID string, payload interface{}, delay time.Duration) error {
+	body, err := encodeJob(jobType, correlationID, payload)
+	if err != nil {
+		return err
+	}
+	return queue.Enqueue(ctx, body, delay)
+}
+
+// decodeJobEnvelope は受信したメッセージの本文から封筒を取り出します
+// 封筒の導入前に送られたメッセージ (通知メッセージ・kind 付きの Webhook ジョブ) は、本文を Payload とする封筒として扱う
+func decodeJobEnvelope(body string) (*models.JobEnvelope, error) {
+	var env models.JobEnvelope
+	if err := json.Unmarshal([]byte(body), &env); err != nil {
+		return nil, fmt.Errorf("%w: unmarshal job envelope: %v", errPoisonMessage, err)
+	}
+	if env.Version >= 1 {
+		if env.Type == "" || len(env.Payload) == 0 {
+			return nil, fmt.Errorf("%w: job envelope requires type and payload", errPoisonMessage)
+		}
+		return &env, nil
+	}
+
+	legacy := &models.JobEnvelope{Type: models.JobTypeNotification, Version: 1, Payload: json.RawMessage(body)}
+	if isWebhookJob(body) {
+		legacy.Type = models.JobTypeWebhookDelivery
+	}
+	return legacy, nil
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"my-portfolio-2025/internal/app/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobRegistryDispatch(t *testing.T) {
+	registry := NewJobRegistry()
+	var handled *Job
+	registry.Register("purge", 2, func(ctx context.Context, job *Job) error {
+		handled = job
+		return nil
+	})
+
+	job := &Job{Envelope: &models.JobEnvelope{Type: "purge", Version: 2, Payload: json.RawMessage(`{}`)}}
+	require.NoError(t, registry.Dispatch(context.Background(), job))
+	assert.Same(t, job, handled)
+
+	// 未知の種別はどのワーカーも処理できないため DLQ へ送る
+	err := registry.Dispatch(context.Background(), &Job{Envelope: &models.JobEnvelope{Type: "report", Version: 1}})
+	assert.ErrorIs(t, err, errPoisonMessage)
+
+	// 新しいバージョンは新しいワーカーが処理できるよう再試行する
+	err = registry.Dispatch(context.Background(), &Job{Envelope: &models.JobEnvelope{Type: "purge", Version: 3}})
+	require.Error(t, err)
+	assert.False(t, errors.Is(err, errPoisonMessage))
+}
+
+func TestJobDecode(t *testing.T) {
+	var msg models.NotificationMessage
+	job := &Job{Envelope: &models.JobEnvelope{Type: models.JobTypeNotification, Payload: json.RawMessage(`{"user_id":"not-a-uuid"}`)}}
+	assert.ErrorIs(t, job.Decode(&msg), errPoisonMessage)
+}
+
+func TestDecodeJobEnvelope(t *testing.T) {
+	userID := uuid.New()
+	body, err := encodeJob(models.JobTypeNotification, "task-1", &models.NotificationMessage{UserID: userID, Message: "hello"})
+	require.NoError(t, err)
+
+	env, err := decodeJobEnvelope(string(body))
+	require.NoError(t, err)
+	assert.Equal(t, models.JobTypeNotification, env.Type)
+	assert.Equal(t, models.JobEnvelopeVersion, env.Version)
+	assert.Equal(t, "task-1", env.CorrelationID)
+	assert.NotEqual(t, uuid.Nil, env.ID)
+
+	var msg models.NotificationMessage
+	require.NoError(t, (&Job{Envelope: env}).Decode(&msg))
+	assert.Equal(t, userID, msg.UserID)
+}
+
+func TestDecodeJobEnvelope_Legacy(t *testing.T) {
+	// 封筒の導入前に送られたメッセージも処理できる
+	env, err := decodeJobEnvelope(`{"user_id":"` + uuid.NewString() + `","type":"deadline","message":"hello"}`)
+	require.NoError(t, err)
+	assert.Equal(t, models.JobTypeNotification, env.Type)
+	assert.Equal(t, uuid.Nil, env.ID)
+
+	env, err = decodeJobEnvelope(`{"kind":"webhook_delivery","subscription_id":"` + uuid.NewString() + `","attempt":1}`)
+	require.NoError(t, err)
+	assert.Equal(t, models.JobTypeWebhookDelivery, env.Type)
+
+	_, err = decodeJobEnvelope(`not json`)
+	assert.ErrorIs(t, err, errPoisonMessage)
+}
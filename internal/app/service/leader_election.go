@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TaskWatcherLeaseName は StartTaskWatcher を実行するレプリカを決めるリースの名前です
+const TaskWatcherLeaseName = "task_watcher"
+
+// DefaultLeaderLeaseTTL はリースの既定の期限です
+// リーダーが落ちた場合、他のレプリカはこの時間以内に引き継ぐ (正常終了時はリースを手放すためすぐに引き継ぐ)
+const DefaultLeaderLeaseTTL = 15 * time.Second
+
+// LeaderStatus はこのレプリカのリーダー選出の状態です (ログ・ヘルスチェック用)
+type LeaderStatus struct {
+	Lease    string     `json:"lease"`
+	Identity string     `json:"identity"`
+	Leader   bool       `json:"leader"`
+	Since    *time.Time `json:"since,omitempty"` // リーダーになった時刻
+}
+
+// LeaderElection はリースを取得できたレプリカだけで処理を実行します
+// リースは ttl の 1/3 ごとに延長し、他のレプリカは同じ間隔で取得を試みる
+type LeaderElection struct {
+	lease    LeaderLease
+	identity string
+	ttl      time.Duration
+
+	mu     sync.Mutex
+	leader bool
+	since  time.Time
+}
+
+// NewLeaderElection は LeaderElection を作成します。ttl が 0 以下の場合は DefaultLeaderLeaseTTL を使う
+func NewLeaderElection(lease LeaderLease, ttl time.Duration) *LeaderElection {
+	if ttl <= 0 {
+		ttl = DefaultLeaderLeaseTTL
+	}
+	return &LeaderElection{lease: lease, identity: leaderIdentity(), ttl: ttl}
+}
+
+// leaderIdentity はこのプロセスを識別する保持者名を返します (ホスト名 + ランダムな接尾辞)
+func leaderIdentity() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "unknown"
+	}
+	return fmt.Sprintf("%s-%s", host, uuid.NewString()[:8])
+}
+
+// Status は現在の状態を返します
+func (e *LeaderElection) Status() LeaderStatus {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	status := LeaderStatus{Lease: e.lease.Name(), Identity: e.identity, Leader: e.leader}
+	if e.leader {
+		since := e.since
+		status.Since = &since
+	}
+	return status
+}
+
+func (e *LeaderElection) setLeader(leader bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.leader = leader
+	e.since = time.Now()
+}
+
+// Run はリースを取得できたら lead を実行し、ctx がキャンセルされるまで選出を続けます
+// lead に渡す Context はリースを失うとキャンセルされるため、lead はその時点で処理を止めて戻ってください
+func (e *LeaderElection) Run(ctx context.Context, lead func(ctx context.Context)) {
+	interval := e.ttl / 3
+	slog.Info("Leader election started", "lease", e.lease.Name(), "identity", e.identity, "ttl", e.ttl)
+
+	standby := false
+	for {
+		acquired, err := e.lease.Acquire(ctx, e.identity, e.ttl)
+		switch {
+		case err != nil:
+			if ctx.Err() == nil {
+				slog.Error("Failed to acquire leader lease", "lease", e.lease.Name(), "error", err)
+			}
+		case acquired:
+			e.lead(ctx, lead)
+			standby = false
+		case !standby:
+			// 待機に入ったときだけ現在のリーダーをログに出す
+			holder, _ := e.lease.Holder(ctx)
+			slog.Info("Standing by as follower", "lease", e.lease.Name(), "identity", e.identity, "leader", holder)
+			standby = true
+		}
+
+		select {
+		case <-ctx.Done():
+			slog.Info("Leader election stopped", "lease", e.lease.Name(), "identity", e.identity)
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// RunOnce はリースを取得できた場合だけ lead を1回実行し、終わったらリースを手放します
+// 他のレプリカが保持している場合は lead を実行せずに false を返す。lead に渡す Context は Run と同じくリースを失うとキャンセルされる
+func (e *LeaderElection) RunOnce(ctx context.Context, lead func(ctx context.Context)) (bool, error) {
+	acquired, err := e.lease.Acquire(ctx, e.identity, e.ttl)
+	if err != nil || !acquired {
+		return false, err
+	}
+	e.lead(ctx, lead)
+	return true, nil
+}
+
+// lead はリーダーとして lead を実行し、リースを延長し続けます
+// ctx のキャンセル・リースの喪失・lead の終了のいずれかで lead を止め、保持している場合はリースを手放して戻ります
+func (e *LeaderElection) lead(ctx context.Context, lead func(ctx context.Context)) {
+	e.setLeader(true)
+	slog.Info("Became leader", "lease", e.lease.Name(), "identity", e.identity)
+
+	leadCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		lead(leadCtx)
+	}()
+
+	stop := func(reason string, release bool) {
+		cancel()
+		<-done
+		if release {
+			// 次のリーダーが期限切れを待たずに引き継げるよう、キャンセルされない Context で手放す
+			releaseCtx, cancelRelease := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+			defer cancelRelease()
+			if err := e.lease.Release(releaseCtx, e.identity); err != nil {
+				slog.Warn("Failed to release leader lease", "lease", e.lease.Name(), "error", err)
+			}
+		}
+		e.setLeader(false)
+		slog.Info("Stepped down as leader", "lease", e.lease.Name(), "identity", e.identity, "reason", reason)
+	}
+
+	ticker := time.NewTicker(e.ttl / 3)
+	defer ticker.Stop()
+	renewedAt := time.Now()
+
+	for {
+		select {
+		case <-ctx.Done():
+			stop("shutdown", true)
+			return
+		case <-done:
+			stop("finished", true)
+			return
+		case <-ticker.C:
+			renewed, err := e.lease.Renew(ctx, e.identity, e.ttl)
+			switch {
+			case err != nil:
+				// 延長できたか分からない間も処理は続けるが、期限が切れる前には止める (2つのレプリカが同時に動かないように)
+				slog.Warn("Failed to renew leader lease", "lease", e.lease.Name(), "error", err)
+				if time.Since(renewedAt) >= e.ttl*2/3 {
+					stop("renew failed", false)
+					return
+				}
+			case !renewed:
+				stop("lease lost", false)
+				return
+			default:
+				renewedAt = time.Now()
+			}
+		}
+	}
+}
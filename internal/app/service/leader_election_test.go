@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeLease はメモリ上で保持者を管理するテスト用の LeaderLease です (期限は扱わない)
+type fakeLease struct {
+	mu       sync.Mutex
+	holder   string
+	renewErr error
+	released []string
+}
+
+func (f *fakeLease) Name() string { return "test" }
+
+func (f *fakeLease) Acquire(ctx context.Context, holder string, ttl time.Duration) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.holder != "" {
+		return false, nil
+	}
+	f.holder = holder
+	return true, nil
+}
+
+func (f *fakeLease) Renew(ctx context.Context, holder string, ttl time.Duration) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.renewErr != nil {
+		return false, f.renewErr
+	}
+	return f.holder == holder, nil
+}
+
+func (f *fakeLease) Release(ctx context.Context, holder string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.released = append(f.released, holder)
+	if f.holder == holder {
+		f.holder = ""
+	}
+	return nil
+}
+
+func (f *fakeLease) Holder(ctx context.Context) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.holder, nil
+}
+
+func (f *fakeLease) set(holder string, renewErr error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.holder = holder
+	f.renewErr = renewErr
+}
+
+// runElection は election を別の goroutine で実行し、lead の開始・終了を通知するチャネルを返します
+func runElection(ctx context.Context, election *LeaderElection) (started, stopped chan struct{}, finished chan struct{}) {
+	started = make(chan struct{}, 10)
+	stopped = make(chan struct{}, 10)
+	finished = make(chan struct{})
+	go func() {
+		defer close(finished)
+		election.Run(ctx, func(ctx context.Context) {
+			started <- struct{}{}
+			<-ctx.Done()
+			stopped <- struct{}{}
+		})
+	}()
+	return started, stopped, finished
+}
+
+func waitSignal(t *testing.T, ch <-chan struct{}, msg string) {
+	t.Helper()
+	select {
+	case <-ch:
+	case <-time.After(time.Second):
+		t.Fatal(msg)
+	}
+}
+
+func TestLeaderElection_OnlyOneReplicaLeads(t *testing.T) {
+	lease := &fakeLease{}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	first := NewLeaderElection(lease, 30*time.Millisecond)
+	second := NewLeaderElection(lease, 30*time.Millisecond)
+	started1, _, done1 := runElection(ctx, first)
+	waitSignal(t, started1, "1つ目のレプリカがリーダーにならない")
+	started2, _, done2 := runElection(ctx, second)
+
+	time.Sleep(50 * time.Millisecond)
+	assert.Empty(t, started2, "リースを保持されている間は実行しない")
+	assert.True(t, first.Status().Leader)
+	assert.NotNil(t, first.Status().Since)
+	assert.False(t, second.Status().Leader)
+
+	// 終了時は期限切れを待たずに引き継げるようリースを手放す
+	cancel()
+	<-done1
+	<-done2
+	assert.False(t, first.Status().Leader)
+	assert.Contains(t, lease.released, first.Status().Identity)
+}
+
+func TestLeaderElection_StepsDownWhenLeaseIsLost(t *testing.T) {
+	lease := &fakeLease{}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	election := NewLeaderElection(lease, 30*time.Millisecond)
+	started, stopped, done := runElection(ctx, election)
+	waitSignal(t, started, "リーダーにならない")
+
+	// 期限切れなどで他のレプリカにリースが移った
+	lease.set("other", nil)
+	waitSignal(t, stopped, "リースを失っても処理を止めない")
+	assert.Eventually(t, func() bool { return !election.Status().Leader }, time.Second, time.Millisecond)
+	assert.NotContains(t, lease.released, election.Status().Identity, "他の保持者のリースは手放さない")
+
+	// リースが空けば再びリーダーになる
+	lease.set("", nil)
+	waitSignal(t, started, "リースが空いてもリーダーに戻らない")
+
+	cancel()
+	<-done
+}
+
+func TestLeaderElection_StepsDownBeforeExpiryWhenRenewFails(t *testing.T) {
+	lease := &fakeLease{}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ttl := 60 * time.Millisecond
+	election := NewLeaderElection(lease, ttl)
+	started, stopped, done := runElection(ctx, election)
+	waitSignal(t, started, "リーダーにならない")
+
+	failedAt := time.Now()
+	lease.set(election.Status().Identity, errors.New("redis: connection refused"))
+	waitSignal(t, stopped, "延長できないまま処理を続けている")
+	require.Less(t, time.Since(failedAt), ttl+ttl/3, "リースの期限が切れる前に止める")
+	assert.Eventually(t, func() bool { return !election.Status().Leader }, time.Second, time.Millisecond)
+
+	cancel()
+	<-done
+}
+
+func TestLeaderElection_RunOnce(t *testing.T) {
+	lease := &fakeLease{}
+	election := NewLeaderElection(lease, time.Second)
+
+	ran := 0
+	ok, err := election.RunOnce(context.Background(), func(ctx context.Context) { ran++ })
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 1, ran)
+	assert.Contains(t, lease.released, election.Status().Identity, "終わったらリースを手放す")
+	assert.False(t, election.Status().Leader)
+
+	// 他のレプリカが保持している間は実行しない
+	lease.set("other", nil)
+	ok, err = election.RunOnce(context.Background(), func(ctx context.Context) { ran++ })
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, 1, ran)
+}
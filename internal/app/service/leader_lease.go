@@ -0,0 +1,25 @@
+package service
+
+import (
+	"context"
+	"time"
+)
+
+// LeaderLease は複数のレプリカのうち1つだけが処理を行うためのリース (期限付きのロック) です
+// 保持者は期限が切れる前に Renew で延長し続け、延長できなくなったら処理を止めます
+type LeaderLease interface {
+	// Name はリースの名前を返します (例: task_watcher)
+	Name() string
+
+	// Acquire は誰も保持していない場合に holder としてリースを取得し、取得できたら true を返します
+	Acquire(ctx context.Context, holder string, ttl time.Duration) (bool, error)
+
+	// Renew は holder が保持している場合に期限を延長し、延長できたら true を返します (他の保持者に移っていたら false)
+	Renew(ctx context.Context, holder string, ttl time.Duration) (bool, error)
+
+	// Release は holder が保持している場合にリースを手放し、すぐに他のレプリカが取得できるようにします
+	Release(ctx context.Context, holder string) error
+
+	// Holder は現在の保持者を返します。誰も保持していない場合は空文字
+	Holder(ctx context.Context) (string, error)
+}
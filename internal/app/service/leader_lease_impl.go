@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// 保持者が自分の場合だけ期限を延長する (GET と PEXPIRE の間に他のレプリカへ移るのを防ぐため Lua で実行する)
+var renewLeaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+
+// 保持者が自分の場合だけ削除する
+var releaseLeaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+type redisLeaderLease struct {
+	rdb  *redis.Client
+	name string
+}
+
+// NewRedisLeaderLease は Redis のキーを使う LeaderLease を作成します
+// リースはキーの値に保持者、TTL に期限を持つため、保持者が落ちても期限が切れれば他のレプリカが取得できる
+func NewRedisLeaderLease(rdb *redis.Client, name string) LeaderLease {
+	return &redisLeaderLease{rdb: rdb, name: name}
+}
+
+func (l *redisLeaderLease) key() string {
+	return "leader:" + l.name
+}
+
+func (l *redisLeaderLease) Name() string {
+	return l.name
+}
+
+func (l *redisLeaderLease) Acquire(ctx context.Context, holder string, ttl time.Duration) (bool, error) {
+	ok, err := l.rdb.SetNX(ctx, l.key(), holder, ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("leaderLease.Acquire (%s): %w", l.name, err)
+	}
+	return ok, nil
+}
+
+func (l *redisLeaderLease) Renew(ctx context.Context, holder string, ttl time.Duration) (bool, error) {
+	n, err := renewLeaseScript.Run(ctx, l.rdb, []string{l.key()}, holder, ttl.Milliseconds()).Int()
+	if err != nil {
+		return false, fmt.Errorf("leaderLease.Renew (%s): %w", l.name, err)
+	}
+	return n == 1, nil
+}
+
+func (l *redisLeaderLease) Release(ctx context.Context, holder string) error {
+	if err := releaseLeaseScript.Run(ctx, l.rdb, []string{l.key()}, holder).Err(); err != nil {
+		return fmt.Errorf("leaderLease.Release (%s): %w", l.name, err)
+	}
+	return nil
+}
+
+func (l *redisLeaderLease) Holder(ctx context.Context) (string, error) {
+	holder, err := l.rdb.Get(ctx, l.key()).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("leaderLease.Holder (%s): %w", l.name, err)
+	}
+	return holder, nil
+}
@@ -0,0 +1,15 @@
+package service
+
+import (
+	"context"
+	"my-portfolio-2025/internal/app/models"
+
+	"github.com/google/uuid"
+)
+
+// NotificationActionService は通知に付いた操作 (タスクの完了・スヌーズなど) を実行します
+type NotificationActionService interface {
+	// Perform は通知の操作を TaskService 経由で実行し、通知を既読にします
+	// 通知が持たない操作は ErrValidation、他人の通知は ErrNotFound、他人のタスクは ErrForbidden を返します
+	Perform(ctx context.Context, userID uuid.UUID, notificationID uuid.UUID, action string) (*models.NotificationActionResult, error)
+}
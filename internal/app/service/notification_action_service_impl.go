@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"my-portfolio-2025/internal/app/apperr"
+	"my-portfolio-2025/internal/app/models"
+
+	"github.com/google/uuid"
+)
+
+type notificationActionServiceImpl struct {
+	notiService NotificationService
+	taskService TaskService
+}
+
+// NewNotificationActionService は NotificationActionService の新しいインスタンスを作成します
+func NewNotificationActionService(notiService NotificationService, taskService TaskService) NotificationActionService {
+	return &notificationActionServiceImpl{notiService: notiService, taskService: taskService}
+}
+
+// Perform は通知の操作を実行します
+// タスクの所有者チェックは TaskService が行う
+func (s *notificationActionServiceImpl) Perform(ctx context.Context, userID uuid.UUID, notificationID uuid.UUID, action string) (*models.NotificationActionResult, error) {
+	n, err := s.notiService.GetByID(ctx, notificationID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !n.HasAction(action) || n.TaskID == nil {
+		return nil, fmt.Errorf("%w: notification %s has no action %q", apperr.ErrValidation, notificationID, action)
+	}
+
+	var task *models.Task
+	switch action {
+	case models.NotificationActionCompleteTask:
+		status := models.TaskStatusCompleted
+		task, err = s.taskService.UpdateTask(userID, *n.TaskID, &models.TaskUpdateRequest{Status: &status})
+	case models.NotificationActionSnooze1h:
+		task, err = s.taskService.SnoozeTask(userID, *n.TaskID, &models.SnoozeRequest{DurationMinutes: 60})
+	case models.NotificationActionOpenTask:
+		task, err = s.taskService.GetTaskByID(userID, *n.TaskID)
+	default:
+		return nil, fmt.Errorf("%w: unknown action %q", apperr.ErrValidation, action)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !n.IsRead {
+		if err := s.notiService.MarkAsRead(ctx, notificationID, userID); err != nil {
+			return nil, err
+		}
+	}
+	return &models.NotificationActionResult{NotificationID: notificationID, Action: action, Task: task}, nil
+}
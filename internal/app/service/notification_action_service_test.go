@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"my-portfolio-2025/internal/app/apperr"
+	"my-portfolio-2025/internal/app/models"
+	"my-portfolio-2025/internal/testutils/mock"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	mockPkg "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func newActionTestNotification(userID uuid.UUID) *models.Notification {
+	taskID := uuid.New()
+	return &models.Notification{
+		ID:      uuid.New(),
+		UserID:  userID,
+		TaskID:  &taskID,
+		Type:    models.NotificationTypeDeadline,
+		Actions: models.TaskNotificationActions(),
+	}
+}
+
+func TestPerformNotificationAction_CompleteTask(t *testing.T) {
+	userID := uuid.New()
+	n := newActionTestNotification(userID)
+	repo := new(mock.MockNotificationRepository)
+	taskSvc := new(mock.TaskServiceMock)
+	svc := NewNotificationActionService(NewNotificationService(repo, nil), taskSvc)
+
+	repo.On("FindByID", mockPkg.Anything, n.ID, userID).Return(n, nil).Once()
+	taskSvc.On("UpdateTask", userID, *n.TaskID, mockPkg.MatchedBy(func(req *models.TaskUpdateRequest) bool {
+		return req.Status != nil && *req.Status == models.TaskStatusCompleted && req.Title == nil
+	})).Return(&models.Task{ID: *n.TaskID, Status: models.TaskStatusCompleted}, nil).Once()
+	repo.On("MarkAsRead", mockPkg.Anything, n.ID, userID).Return(nil).Once()
+
+	result, err := svc.Perform(context.Background(), userID, n.ID, models.NotificationActionCompleteTask)
+
+	require.NoError(t, err)
+	assert.Equal(t, models.TaskStatusCompleted, result.Task.Status)
+	repo.AssertExpectations(t)
+	taskSvc.AssertExpectations(t)
+}
+
+func TestPerformNotificationAction_Snooze(t *testing.T) {
+	userID := uuid.New()
+	n := newActionTestNotification(userID)
+	n.IsRead = true
+	repo := new(mock.MockNotificationRepository)
+	taskSvc := new(mock.TaskServiceMock)
+	svc := NewNotificationActionService(NewNotificationService(repo, nil), taskSvc)
+
+	repo.On("FindByID", mockPkg.Anything, n.ID, userID).Return(n, nil).Once()
+	taskSvc.On("SnoozeTask", userID, *n.TaskID, &models.SnoozeRequest{DurationMinutes: 60}).
+		Return(&models.Task{ID: *n.TaskID}, nil).Once()
+
+	_, err := svc.Perform(context.Background(), userID, n.ID, models.NotificationActionSnooze1h)
+
+	require.NoError(t, err)
+	taskSvc.AssertExpectations(t)
+	repo.AssertNotCalled(t, "MarkAsRead", mockPkg.Anything, mockPkg.Anything, mockPkg.Anything)
+}
+
+func TestPerformNotificationAction_Errors(t *testing.T) {
+	userID := uuid.New()
+
+	t.Run("通知に無い操作は実行しない", func(t *testing.T) {
+		n := newActionTestNotification(userID)
+		n.Actions = nil
+		repo := new(mock.MockNotificationRepository)
+		taskSvc := new(mock.TaskServiceMock)
+		repo.On("FindByID", mockPkg.Anything, n.ID, userID).Return(n, nil).Once()
+
+		svc := NewNotificationActionService(NewNotificationService(repo, nil), taskSvc)
+		_, err := svc.Perform(context.Background(), userID, n.ID, models.NotificationActionCompleteTask)
+
+		assert.ErrorIs(t, err, apperr.ErrValidation)
+		taskSvc.AssertNotCalled(t, "UpdateTask", mockPkg.Anything, mockPkg.Anything, mockPkg.Anything)
+	})
+
+	t.Run("他人の通知は見つからない", func(t *testing.T) {
+		repo := new(mock.MockNotificationRepository)
+		repo.On("FindByID", mockPkg.Anything, mockPkg.Anything, userID).Return(nil, gorm.ErrRecordNotFound).Once()
+
+		svc := NewNotificationActionService(NewNotificationService(repo, nil), new(mock.TaskServiceMock))
+		_, err := svc.Perform(context.Background(), userID, uuid.New(), models.NotificationActionOpenTask)
+
+		assert.ErrorIs(t, err, apperr.ErrNotFound)
+	})
+
+	t.Run("他人のタスクは操作できず既読にもしない", func(t *testing.T) {
+		n := newActionTestNotification(userID)
+		repo := new(mock.MockNotificationRepository)
+		taskSvc := new(mock.TaskServiceMock)
+		repo.On("FindByID", mockPkg.Anything, n.ID, userID).Return(n, nil).Once()
+		taskSvc.On("GetTaskByID", userID, *n.TaskID).Return(nil, apperr.ErrForbidden).Once()
+
+		svc := NewNotificationActionService(NewNotificationService(repo, nil), taskSvc)
+		_, err := svc.Perform(context.Background(), userID, n.ID, models.NotificationActionOpenTask)
+
+		assert.ErrorIs(t, err, apperr.ErrForbidden)
+		repo.AssertNotCalled(t, "MarkAsRead", mockPkg.Anything, mockPkg.Anything, mockPkg.Anything)
+	})
+}
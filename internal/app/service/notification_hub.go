@@ -6,56 +6,212 @@ import (
 	"fmt"
 	"log/slog"
 	"my-portfolio-2025/internal/app/models"
+	"strconv"
 	"sync"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
 	"github.com/redis/go-redis/v9"
 )
 
+// WebSocket への書き込みタイムアウト
+const wsWriteTimeout = 10 * time.Second
+
+const (
+	// 停止時に close フレームを送ってから、クライアントの close フレームを待つ時間。過ぎたら接続を閉じる
+	wsCloseGracePeriod = 5 * time.Second
+	// 停止時に全クライアントの切断を確認する間隔
+	wsShutdownPollInterval = 50 * time.Millisecond
+)
+
+const (
+	// 接続中のセッションを Redis に記録する間隔
+	presenceHeartbeat = 30 * time.Second
+	// この時間 heartbeat が無いセッションは切断済みとみなす (API サーバーが落ちた場合など)
+	presenceTTL = 3 * presenceHeartbeat
+	// presence の読み書きのタイムアウト。Hub のループを長く止めないようにする
+	presenceTimeout = 2 * time.Second
+)
+
+// Client は1つのWebSocket接続を表します
+type Client struct {
+	UserID    uuid.UUID
+	sessionID string // presence の記録に使う接続ごとのID
+	conn      *websocket.Conn
+
+	// gorilla/websocket は同時書き込みを許可しないため、
+	// Hub とコマンド応答の書き込みをこのロックで直列化します
+	writeMu sync.Mutex
+
+	// subscribe_task で購読中のタスク
+	subMu         sync.RWMutex
+	subscriptions map[uuid.UUID]struct{}
+}
+
+// NewClient は新しいクライアントを作成します
+func NewClient(userID uuid.UUID, conn *websocket.Conn) *Client {
+	return &Client{
+		UserID:        userID,
+		sessionID:     uuid.NewString(),
+		conn:          conn,
+		subscriptions: make(map[uuid.UUID]struct{}),
+	}
+}
+
+// Send はイベントをクライアントへ書き込みます
+func (c *Client) Send(env *models.WSEnvelope) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	c.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+	return c.conn.WriteJSON(env)
+}
+
+// SubscribeTask はタスクの変更イベントを購読します
+func (c *Client) SubscribeTask(taskID uuid.UUID) {
+	c.subMu.Lock()
+	defer c.subMu.Unlock()
+	c.subscriptions[taskID] = struct{}{}
+}
+
+// wantsTask は task.updated を配信すべきかを判定します
+// 1件も購読していないクライアントには全タスクのイベントを配信します
+func (c *Client) wantsTask(taskID uuid.UUID) bool {
+	c.subMu.RLock()
+	defer c.subMu.RUnlock()
+	if len(c.subscriptions) == 0 {
+		return true
+	}
+	_, ok := c.subscriptions[taskID]
+	return ok
+}
+
+// CloseGoingAway はサーバーの停止を close フレーム (1001 Going Away) で通知します
+// クライアントが close フレームを返すと読み込みループが終わる。返さない場合も wsCloseGracePeriod 後に読み込みを打ち切る
+func (c *Client) CloseGoingAway() error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	msg := websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down")
+	err := c.conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(wsWriteTimeout))
+	c.conn.SetReadDeadline(time.Now().Add(wsCloseGracePeriod))
+	return err
+}
+
+// Close は接続を閉じます
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
 // Hub は全てのWebSocket接続を管理し、メッセージを配信します
 type NotificationHub struct {
-	// 接続中のクライアントを管理 (ユーザーID -> WebSocket接続)
-	// 1ユーザーが複数デバイスで繋ぐ場合は [] *websocket.Conn にしますが、
-	// 今回はシンプルに 1ユーザー1接続として実装します
-	clients map[uuid.UUID]*websocket.Conn
+	// 接続中のクライアントを管理 (ユーザーID -> クライアントの集合)
+	// 1ユーザーが複数デバイス・複数タブで接続できるようにします
+	clients map[uuid.UUID]map[*Client]struct{}
 
 	// クライアントからの新規接続通知用チャネル
-	Register chan *ClientRegistration
+	Register chan *Client
 
 	// クライアントの切断通知用チャネル
-	Unregister chan uuid.UUID
+	Unregister chan *Client
 
 	// 配信メッセージ用チャネル
-	Broadcast chan *models.NotificationMessage
+	Broadcast chan *models.HubMessage
 
 	// マップ操作時の排他制御用
 	mu sync.Mutex
 
+	// closing は Shutdown が呼ばれたかどうか (h.mu で保護)。以降の新規接続は受け付けない
+	closing bool
+
+	// done は Run が終了すると閉じられる
+	done chan struct{}
+
 	// Redisクライアント
 	redisClient *redis.Client
 }
 
-// 登録用構造体
-type ClientRegistration struct {
-	UserID uuid.UUID
-	Conn   *websocket.Conn
-}
-
 // NewNotificationHub は新しいハブを作成します
 func NewNotificationHub(redisClient *redis.Client) *NotificationHub {
 	return &NotificationHub{
-		clients:     make(map[uuid.UUID]*websocket.Conn),
-		Register:    make(chan *ClientRegistration),
-		Unregister:  make(chan uuid.UUID),
-		Broadcast:   make(chan *models.NotificationMessage),
+		clients:     make(map[uuid.UUID]map[*Client]struct{}),
+		Register:    make(chan *Client),
+		Unregister:  make(chan *Client),
+		Broadcast:   make(chan *models.HubMessage),
+		done:        make(chan struct{}),
 		redisClient: redisClient,
 	}
 }
 
+// Join はクライアントをハブに登録します
+// ハブが停止中・停止済みの場合は登録せずに false を返すため、呼び出し側で接続を閉じること
+func (h *NotificationHub) Join(client *Client) bool {
+	h.mu.Lock()
+	closing := h.closing
+	h.mu.Unlock()
+	if closing {
+		return false
+	}
+
+	select {
+	case h.Register <- client:
+		return true
+	case <-h.done:
+		return false
+	}
+}
+
+// Leave はクライアントをハブから外します。ハブが停止済みの場合は接続を閉じるだけです
+func (h *NotificationHub) Leave(client *Client) {
+	select {
+	case h.Unregister <- client:
+	case <-h.done:
+		client.Close()
+	}
+}
+
+// Shutdown は接続中の全クライアントに close フレームを送り、全員が切断するまで待ちます
+// ctx の期限までに切断しないクライアントが残っている場合は ctx のエラーを返します (接続は Run の終了時に閉じられる)
+// Shutdown の間も切断を処理するため、Run は Shutdown が戻るまで止めないこと
+func (h *NotificationHub) Shutdown(ctx context.Context) error {
+	h.mu.Lock()
+	h.closing = true
+	h.mu.Unlock()
+
+	clients := h.snapshot()
+	slog.Info("Closing WebSocket connections", "clients", len(clients))
+	for _, client := range clients {
+		if err := client.CloseGoingAway(); err != nil {
+			slog.Warn("Failed to send WebSocket close frame", "userID", client.UserID, "error", err)
+		}
+	}
+
+	ticker := time.NewTicker(wsShutdownPollInterval)
+	defer ticker.Stop()
+	for {
+		h.mu.Lock()
+		remaining := len(h.clients)
+		h.mu.Unlock()
+		if remaining == 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("NotificationHub.Shutdown: %d users still connected: %w", remaining, ctx.Err())
+		case <-h.done:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
 // Run はハブのメインループを実行します（Goルーチンとして起動）
+// ctx がキャンセルされると、残っているクライアントの接続を閉じてから戻ります
 func (h *NotificationHub) Run(ctx context.Context) {
 	slog.Info("Notification Hub is running...")
+	defer close(h.done)
 
 	// --- 1. Redis監視ループを独立したGoroutineで動かす (以前の SubscribeRedis 相当) ---
 	go func() {
@@ -75,65 +231,213 @@ func (h *NotificationHub) Run(ctx context.Context) {
 				// 受信ログ (Debug)
 				fmt.Printf("DEBUG: Received something from Redis: %s\n", msg.Payload)
 
-				var notif models.NotificationMessage
-				if err := json.Unmarshal([]byte(msg.Payload), &notif); err != nil {
+				var hubMsg models.HubMessage
+				if err := json.Unmarshal([]byte(msg.Payload), &hubMsg); err != nil {
 					slog.Error("Failed to unmarshal Redis message", "error", err)
 					continue
 				}
 
 				// 独立した外側から Broadcast チャネルへ流し込む
-				h.Broadcast <- &notif
+				select {
+				case h.Broadcast <- &hubMsg:
+				case <-ctx.Done():
+					return
+				}
 			}
 		}
 	}()
 
 	// --- 2. Hub管理ループ (以前の Run 相当) ---
+	heartbeat := time.NewTicker(presenceHeartbeat)
+	defer heartbeat.Stop()
+
 	for {
 		select {
 		case <-ctx.Done():
 			slog.Info("Notification Hub shutting down")
+			h.closeAll(context.WithoutCancel(ctx))
 			return
 
-		case reg := <-h.Register:
+		case <-heartbeat.C:
+			h.refreshPresence(ctx)
+
+		case client := <-h.Register:
 			h.mu.Lock()
-			h.clients[reg.UserID] = reg.Conn
+			if h.clients[client.UserID] == nil {
+				h.clients[client.UserID] = make(map[*Client]struct{})
+			}
+			h.clients[client.UserID][client] = struct{}{}
+			sessions := len(h.clients[client.UserID])
 			h.mu.Unlock()
-			slog.Info("User connected", "userID", reg.UserID)
+			h.markOnline(ctx, client)
+			slog.Info("User connected", "userID", client.UserID, "sessions", sessions)
 
-		case userID := <-h.Unregister:
+		case client := <-h.Unregister:
 			h.mu.Lock()
-			if conn, ok := h.clients[userID]; ok {
-				conn.Close()
-				delete(h.clients, userID)
-				slog.Info("User disconnected", "userID", userID)
-			}
+			h.removeClient(client)
 			h.mu.Unlock()
+			h.markOffline(ctx, client)
+			slog.Info("User disconnected", "userID", client.UserID)
 
 		case msg := <-h.Broadcast:
 			// 配信ログを追加
-			slog.Info("Attempting to broadcast message", "targetUserID", msg.UserID)
+			slog.Info("Attempting to broadcast message", "targetUserID", msg.UserID, "type", msg.Event.Type)
 
 			h.mu.Lock()
-			if conn, ok := h.clients[msg.UserID]; ok {
-				err := conn.WriteJSON(msg)
-				if err != nil {
+			sessions, ok := h.clients[msg.UserID]
+			if !ok {
+				slog.Warn("Recipient not found in active connections", "userID", msg.UserID)
+			}
+			// 同じユーザーの全セッションへ配信する
+			for client := range sessions {
+				if !h.shouldDeliver(client, msg) {
+					continue
+				}
+				if err := client.Send(&msg.Event); err != nil {
 					slog.Error("Failed to send WebSocket message", "userID", msg.UserID, "error", err)
-					conn.Close()
-					delete(h.clients, msg.UserID)
+					h.removeClient(client)
 				} else {
 					slog.Info("✅ Notification sent successfully", "userID", msg.UserID)
 				}
-			} else {
-				slog.Warn("Recipient not found in active connections", "userID", msg.UserID)
 			}
 			h.mu.Unlock()
 		}
 	}
 }
 
+// removeClient はクライアントを管理対象から外して接続を閉じます（h.mu を保持して呼ぶこと）
+func (h *NotificationHub) removeClient(client *Client) {
+	if sessions, ok := h.clients[client.UserID]; ok {
+		delete(sessions, client)
+		if len(sessions) == 0 {
+			delete(h.clients, client.UserID)
+		}
+	}
+	client.Close()
+}
+
+// closeAll は残っている全クライアントの接続を閉じ、presence から外します
+func (h *NotificationHub) closeAll(ctx context.Context) {
+	clients := h.snapshot()
+	h.mu.Lock()
+	h.closing = true
+	for _, client := range clients {
+		h.removeClient(client)
+	}
+	h.mu.Unlock()
+
+	for _, client := range clients {
+		h.markOffline(ctx, client)
+	}
+}
+
+func presenceKey(userID uuid.UUID) string {
+	return "ws_presence:" + userID.String()
+}
+
+// markOnline はセッションを Redis の sorted set (score = 最終 heartbeat 時刻) に記録します
+// 別プロセスのワーカーからもユーザーが接続中かどうかを判定できるようにするためです
+func (h *NotificationHub) markOnline(ctx context.Context, clients ...*Client) {
+	ctx, cancel := context.WithTimeout(ctx, presenceTimeout)
+	defer cancel()
+
+	now := float64(time.Now().Unix())
+	pipe := h.redisClient.Pipeline()
+	for _, client := range clients {
+		key := presenceKey(client.UserID)
+		pipe.ZAdd(ctx, key, redis.Z{Score: now, Member: client.sessionID})
+		pipe.Expire(ctx, key, presenceTTL)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		slog.Warn("Failed to update WebSocket presence", "error", err)
+	}
+}
+
+// markOffline はセッションを presence から外します
+func (h *NotificationHub) markOffline(ctx context.Context, client *Client) {
+	ctx, cancel := context.WithTimeout(ctx, presenceTimeout)
+	defer cancel()
+
+	if err := h.redisClient.ZRem(ctx, presenceKey(client.UserID), client.sessionID).Err(); err != nil {
+		slog.Warn("Failed to remove WebSocket presence", "userID", client.UserID, "error", err)
+	}
+}
+
+// refreshPresence はこのプロセスで接続中の全セッションの heartbeat を更新します
+func (h *NotificationHub) refreshPresence(ctx context.Context) {
+	if clients := h.snapshot(); len(clients) > 0 {
+		h.markOnline(ctx, clients...)
+	}
+}
+
+// snapshot は接続中の全クライアントを返します
+func (h *NotificationHub) snapshot() []*Client {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	clients := make([]*Client, 0, len(h.clients))
+	for _, sessions := range h.clients {
+		for client := range sessions {
+			clients = append(clients, client)
+		}
+	}
+	return clients
+}
+
+// IsOnline はユーザーがいずれかの API サーバーに WebSocket で接続中かを返します
+// heartbeat が presenceTTL より古いセッションは切断済みとして取り除きます
+func (h *NotificationHub) IsOnline(ctx context.Context, userID uuid.UUID) (bool, error) {
+	key := presenceKey(userID)
+	stale := strconv.FormatInt(time.Now().Add(-presenceTTL).Unix(), 10)
+
+	pipe := h.redisClient.TxPipeline()
+	pipe.ZRemRangeByScore(ctx, key, "-inf", "("+stale)
+	count := pipe.ZCard(ctx, key)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return false, fmt.Errorf("notificationHub.IsOnline: %w", err)
+	}
+	return count.Val() > 0, nil
+}
+
+// shouldDeliver はタスクイベントの購読状態を確認します
+// 購読で絞り込むのは task.updated だけ。task.created は事前に購読できず、
+// task.deleted はクライアントの一覧から消すために必要なので、購読に関わらず全セッションへ配信する
+func (h *NotificationHub) shouldDeliver(client *Client, msg *models.HubMessage) bool {
+	if msg.TaskID == nil || msg.Event.Type != models.WSEventTaskUpdated {
+		return true
+	}
+	return client.wantsTask(*msg.TaskID)
+}
+
 // 1. Redisへの「出版」処理
 func (h *NotificationHub) PublishMessage(ctx context.Context, msg models.NotificationMessage) error {
-	payload, _ := json.Marshal(msg)
+	return h.PublishEvent(ctx, msg.UserID, nil, models.WSEventNotification, msg)
+}
+
+// PublishEvent は任意のサーバーイベントを Redis 経由で全インスタンスの Hub へ配信します
+// taskID を指定した task.updated は、そのタスクを購読しているクライアントにだけ届けます (shouldDeliver)
+func (h *NotificationHub) PublishEvent(ctx context.Context, userID uuid.UUID, taskID *uuid.UUID, eventType string, payload interface{}) error {
+	env, err := models.NewWSEnvelope(eventType, "", payload)
+	if err != nil {
+		return fmt.Errorf("NotificationHub.PublishEvent (marshal payload): %w", err)
+	}
+
+	data, err := json.Marshal(models.HubMessage{UserID: userID, TaskID: taskID, Event: *env})
+	if err != nil {
+		return fmt.Errorf("NotificationHub.PublishEvent (marshal): %w", err)
+	}
 	// "notifications" チャンネルへ送信
-	return h.redisClient.Publish(ctx, "notifications", payload).Err()
+	return h.redisClient.Publish(ctx, "notifications", data).Err()
+}
+
+// PublishTaskEvent はタスクのドメインイベントをそのユーザーの全セッションへ配信します
+// TaskEventPublisher インターフェースの実装です
+func (h *NotificationHub) PublishTaskEvent(ctx context.Context, event *models.TaskEvent) error {
+	payload := models.WSTaskEventPayload{
+		TaskID:        event.TaskID,
+		Version:       event.Version,
+		ChangedFields: event.ChangedFields,
+		Task:          event.Task,
+	}
+	return h.PublishEvent(ctx, event.UserID, &event.TaskID, event.Type, payload)
 }
@@ -0,0 +1,36 @@
+package service
+
+import (
+	"testing"
+
+	"my-portfolio-2025/internal/app/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShouldDeliver_TaskSubscriptionFiltersOnlyUpdates(t *testing.T) {
+	hub := &NotificationHub{}
+	client := NewClient(uuid.New(), nil)
+	subscribed, other := uuid.New(), uuid.New()
+	client.SubscribeTask(subscribed)
+
+	tests := []struct {
+		name      string
+		eventType string
+		taskID    uuid.UUID
+		expected  bool
+	}{
+		{name: "購読中のタスクの更新", eventType: models.WSEventTaskUpdated, taskID: subscribed, expected: true},
+		{name: "購読していないタスクの更新は届かない", eventType: models.WSEventTaskUpdated, taskID: other},
+		{name: "作成は購読に関わらず届く", eventType: models.WSEventTaskCreated, taskID: other, expected: true},
+		{name: "削除は購読に関わらず届く", eventType: models.WSEventTaskDeleted, taskID: other, expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := &models.HubMessage{UserID: client.UserID, TaskID: &tt.taskID, Event: models.WSEnvelope{Type: tt.eventType}}
+			assert.Equal(t, tt.expected, hub.shouldDeliver(client, msg))
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package service
+
+import (
+	"context"
+	"my-portfolio-2025/internal/app/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NotificationPreferenceService は通知設定（種別・チャネルごとの有効/無効、おやすみ時間帯）を扱います
+type NotificationPreferenceService interface {
+	// Get はユーザーの通知設定を返します。未設定の場合は既定値を返します
+	Get(ctx context.Context, userID uuid.UUID) (*models.NotificationPreference, error)
+
+	// Update は通知設定を更新します
+	Update(ctx context.Context, userID uuid.UUID, req *models.NotificationPreferenceUpdateRequest) (*models.NotificationPreference, error)
+
+	// Evaluate は通知を作成・配信する前に、配信可能なチャネルと配信時刻を判定します (WorkerServiceから呼ばれます)
+	Evaluate(ctx context.Context, userID uuid.UUID, notificationType string, now time.Time) (*models.DeliveryDecision, error)
+}
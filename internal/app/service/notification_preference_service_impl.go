@@ -0,0 +1,212 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"my-portfolio-2025/internal/app/apperr"
+	"my-portfolio-2025/internal/app/models"
+	"my-portfolio-2025/internal/app/repository"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// 既定のタイムゾーン (ユーザーが未設定の場合)
+const defaultPreferenceTimezone = "Asia/Tokyo"
+
+type notificationPreferenceServiceImpl struct {
+	repo repository.NotificationPreferenceRepository
+}
+
+// NewNotificationPreferenceService は NotificationPreferenceService の新しいインスタンスを作成します
+func NewNotificationPreferenceService(repo repository.NotificationPreferenceRepository) NotificationPreferenceService {
+	return &notificationPreferenceServiceImpl{repo: repo}
+}
+
+// Get は通知設定を取得します
+func (s *notificationPreferenceServiceImpl) Get(ctx context.Context, userID uuid.UUID) (*models.NotificationPreference, error) {
+	pref, err := s.repo.FindByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return defaultPreference(userID), nil
+		}
+		return nil, fmt.Errorf("notificationPreferenceService.Get: %w", err)
+	}
+	return pref, nil
+}
+
+// Update は入力を検証したうえで通知設定を更新します
+func (s *notificationPreferenceServiceImpl) Update(ctx context.Context, userID uuid.UUID, req *models.NotificationPreferenceUpdateRequest) (*models.NotificationPreference, error) {
+	pref, err := s.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Timezone != nil {
+		if _, err := time.LoadLocation(*req.Timezone); err != nil || *req.Timezone == "" {
+			return nil, fmt.Errorf("%w: unknown timezone %q", apperr.ErrValidation, *req.Timezone)
+		}
+		pref.Timezone = *req.Timezone
+	}
+
+	if req.QuietHours != nil {
+		if req.QuietHours.Enabled {
+			if _, err := parseClock(req.QuietHours.Start); err != nil {
+				return nil, fmt.Errorf("%w: quiet_hours.start must be HH:MM", apperr.ErrValidation)
+			}
+			if _, err := parseClock(req.QuietHours.End); err != nil {
+				return nil, fmt.Errorf("%w: quiet_hours.end must be HH:MM", apperr.ErrValidation)
+			}
+		}
+		pref.QuietHoursEnabled = req.QuietHours.Enabled
+		pref.QuietHoursStart = req.QuietHours.Start
+		pref.QuietHoursEnd = req.QuietHours.End
+	}
+
+	if req.EmailDigest != nil {
+		pref.EmailDigest = *req.EmailDigest
+	}
+
+	if req.ReminderOffsets != nil {
+		offsets, err := normalizeReminderOffsets(req.ReminderOffsets)
+		if err != nil {
+			return nil, err
+		}
+		pref.ReminderOffsets = offsets
+	}
+
+	for _, r := range req.Rules {
+		if !slices.Contains(models.NotificationTypes, r.Type) {
+			return nil, fmt.Errorf("%w: unknown notification type %q", apperr.ErrValidation, r.Type)
+		}
+		if !slices.Contains(models.NotificationChannels, r.Channel) {
+			return nil, fmt.Errorf("%w: unknown channel %q", apperr.ErrValidation, r.Channel)
+		}
+		pref.Rules = upsertRule(pref.Rules, models.NotificationRule{UserID: userID, Type: r.Type, Channel: r.Channel, Enabled: r.Enabled})
+	}
+
+	if err := s.repo.Save(ctx, pref); err != nil {
+		return nil, fmt.Errorf("notificationPreferenceService.Update: %w", err)
+	}
+	return pref, nil
+}
+
+// Evaluate は通知設定に基づいて配信先チャネルと配信時刻を決定します
+func (s *notificationPreferenceServiceImpl) Evaluate(ctx context.Context, userID uuid.UUID, notificationType string, now time.Time) (*models.DeliveryDecision, error) {
+	pref, err := s.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return evaluatePreference(pref, notificationType, now), nil
+}
+
+// evaluatePreference は設定と現在時刻から配信可否を判定します (DBに依存しない純粋関数)
+func evaluatePreference(pref *models.NotificationPreference, notificationType string, now time.Time) *models.DeliveryDecision {
+	decision := &models.DeliveryDecision{}
+	for _, channel := range models.NotificationChannels {
+		// ダイジェスト配信中のメールは即時には送らず、DigestService がまとめて送る
+		if channel == models.NotificationChannelEmail && digestEnabled(pref) {
+			continue
+		}
+		if ruleEnabled(pref.Rules, notificationType, channel) {
+			decision.Channels = append(decision.Channels, channel)
+		}
+	}
+	if len(decision.Channels) == 0 {
+		return decision
+	}
+
+	if end, ok := quietHoursEnd(pref, now); ok {
+		decision.DeferUntil = &end
+	}
+	return decision
+}
+
+// ruleEnabled はルールを参照します。ルールが無い組み合わせは有効です
+func ruleEnabled(rules []models.NotificationRule, notificationType, channel string) bool {
+	for _, r := range rules {
+		if r.Type == notificationType && r.Channel == channel {
+			return r.Enabled
+		}
+	}
+	return true
+}
+
+// digestEnabled はメールをダイジェストで送る設定かを返します
+func digestEnabled(pref *models.NotificationPreference) bool {
+	return pref.EmailDigest == models.EmailDigestDaily || pref.EmailDigest == models.EmailDigestWeekly
+}
+
+// quietHoursEnd は now がおやすみ時間帯に含まれる場合、その時間帯が終わる時刻を返します
+// 開始 > 終了の場合は日付をまたぐ時間帯 (例: 22:00〜07:00) として扱います
+func quietHoursEnd(pref *models.NotificationPreference, now time.Time) (time.Time, bool) {
+	if !pref.QuietHoursEnabled {
+		return time.Time{}, false
+	}
+	start, err := parseClock(pref.QuietHoursStart)
+	if err != nil {
+		return time.Time{}, false
+	}
+	end, err := parseClock(pref.QuietHoursEnd)
+	if err != nil || start == end {
+		return time.Time{}, false
+	}
+
+	loc, err := time.LoadLocation(pref.Timezone)
+	if err != nil {
+		loc, _ = time.LoadLocation(defaultPreferenceTimezone)
+	}
+	local := now.In(loc)
+	minutes := local.Hour()*60 + local.Minute()
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	endToday := midnight.Add(time.Duration(end) * time.Minute)
+
+	if start < end {
+		if minutes >= start && minutes < end {
+			return endToday, true
+		}
+		return time.Time{}, false
+	}
+
+	// 日付をまたぐ時間帯
+	switch {
+	case minutes >= start:
+		return endToday.AddDate(0, 0, 1), true
+	case minutes < end:
+		return endToday, true
+	}
+	return time.Time{}, false
+}
+
+// parseClock は "HH:MM" を0時からの経過分に変換します
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// upsertRule は同じ (種別, チャネル) のルールを置き換え、無ければ追加します
+func upsertRule(rules []models.NotificationRule, rule models.NotificationRule) []models.NotificationRule {
+	for i := range rules {
+		if rules[i].Type == rule.Type && rules[i].Channel == rule.Channel {
+			rules[i].Enabled = rule.Enabled
+			return rules
+		}
+	}
+	return append(rules, rule)
+}
+
+// defaultPreference は未設定ユーザー向けの既定設定です (全て有効、おやすみ時間帯なし、メールは即時送信)
+func defaultPreference(userID uuid.UUID) *models.NotificationPreference {
+	return &models.NotificationPreference{
+		UserID:      userID,
+		Timezone:    defaultPreferenceTimezone,
+		EmailDigest: models.EmailDigestOff,
+		Rules:       []models.NotificationRule{},
+	}
+}
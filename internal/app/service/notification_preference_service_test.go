@@ -0,0 +1,95 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"my-portfolio-2025/internal/app/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluatePreference_QuietHours(t *testing.T) {
+	jst, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		start, end    string
+		now           time.Time
+		expectedDefer *time.Time
+	}{
+		{
+			name:          "日付をまたぐ時間帯：開始後は翌日の終了時刻まで遅らせる",
+			start:         "22:00",
+			end:           "07:00",
+			now:           time.Date(2026, 3, 1, 23, 30, 0, 0, jst),
+			expectedDefer: ptrTime(time.Date(2026, 3, 2, 7, 0, 0, 0, jst)),
+		},
+		{
+			name:          "日付をまたぐ時間帯：0時以降は当日の終了時刻まで遅らせる",
+			start:         "22:00",
+			end:           "07:00",
+			now:           time.Date(2026, 3, 2, 3, 0, 0, 0, jst),
+			expectedDefer: ptrTime(time.Date(2026, 3, 2, 7, 0, 0, 0, jst)),
+		},
+		{
+			name:  "日付をまたぐ時間帯：時間帯の外ならすぐ配信",
+			start: "22:00",
+			end:   "07:00",
+			now:   time.Date(2026, 3, 2, 7, 0, 0, 0, jst),
+		},
+		{
+			name:          "同日内の時間帯：UTCで渡してもユーザーのタイムゾーンで判定する",
+			start:         "12:00",
+			end:           "13:00",
+			now:           time.Date(2026, 3, 2, 3, 15, 0, 0, time.UTC), // JST 12:15
+			expectedDefer: ptrTime(time.Date(2026, 3, 2, 13, 0, 0, 0, jst)),
+		},
+		{
+			name:  "開始と終了が同じなら無効",
+			start: "09:00",
+			end:   "09:00",
+			now:   time.Date(2026, 3, 2, 9, 0, 0, 0, jst),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pref := &models.NotificationPreference{
+				UserID:            uuid.New(),
+				Timezone:          "Asia/Tokyo",
+				QuietHoursEnabled: true,
+				QuietHoursStart:   tt.start,
+				QuietHoursEnd:     tt.end,
+			}
+
+			decision := evaluatePreference(pref, models.NotificationTypeDeadline, tt.now)
+
+			if tt.expectedDefer == nil {
+				assert.Nil(t, decision.DeferUntil)
+				return
+			}
+			require.NotNil(t, decision.DeferUntil)
+			assert.True(t, tt.expectedDefer.Equal(*decision.DeferUntil), "got %s", decision.DeferUntil)
+		})
+	}
+}
+
+func TestEvaluatePreference_Rules(t *testing.T) {
+	pref := &models.NotificationPreference{
+		Timezone: "Asia/Tokyo",
+		Rules: []models.NotificationRule{
+			{Type: models.NotificationTypeDeadline, Channel: models.NotificationChannelEmail, Enabled: false},
+		},
+	}
+
+	decision := evaluatePreference(pref, models.NotificationTypeDeadline, time.Now())
+
+	assert.True(t, decision.Allows(models.NotificationChannelWebSocket), "ルールが無いチャネルは有効")
+	assert.False(t, decision.Allows(models.NotificationChannelEmail))
+}
+
+func ptrTime(t time.Time) *time.Time { return &t }
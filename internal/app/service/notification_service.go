@@ -3,18 +3,49 @@ package service
 import (
 	"context"
 	"my-portfolio-2025/internal/app/models"
+	"time"
 
 	"github.com/google/uuid"
 )
 
+// NotificationEventPublisher は未読件数などのイベントをユーザーの全セッションへ配信します (NotificationHub が実装)
+type NotificationEventPublisher interface {
+	PublishEvent(ctx context.Context, userID uuid.UUID, taskID *uuid.UUID, eventType string, payload interface{}) error
+}
+
 type NotificationService interface {
 
 	// Create は新しい通知をDBに保存します (WorkerServiceから呼ばれます)
-	Create(ctx context.Context, notification *models.Notification) error
+	// DedupKey が同じ通知が既にある場合は作成せず false を返し、notification に既存の通知を読み込みます (SQS の再配信などで何度呼んでもよい)
+	Create(ctx context.Context, notification *models.Notification) (bool, error)
+
+	// MarkDelivered は通知のリアルタイム配信を終えたことを記録します (WorkerServiceから呼ばれます)
+	MarkDelivered(ctx context.Context, id uuid.UUID) error
+
+	// GetNotifications はユーザーの通知を絞り込み条件とカーソルページネーション付きで取得します
+	GetNotifications(ctx context.Context, userID uuid.UUID, query *models.NotificationListQuery) (*models.NotificationPage, error)
+
+	// GetByID はユーザーの通知を1件返します
+	GetByID(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*models.Notification, error)
 
-	// GetNotifications はユーザーの通知をページネーション付きで取得します
-	GetNotifications(ctx context.Context, userID uuid.UUID, page int) ([]models.Notification, error)
+	// UnreadCount は未読件数を返します
+	UnreadCount(ctx context.Context, userID uuid.UUID) (int64, error)
 
 	// MarkAsRead は指定された通知を既読にします
 	MarkAsRead(ctx context.Context, id uuid.UUID, userID uuid.UUID) error
+
+	// MarkAllAsRead は未読の通知をすべて既読にし、更新件数を返します
+	MarkAllAsRead(ctx context.Context, userID uuid.UUID) (int64, error)
+
+	// Delete は指定された通知を削除します
+	Delete(ctx context.Context, id uuid.UUID, userID uuid.UUID) error
+
+	// Acknowledge はクライアントが通知を受信したことを記録します (WebSocketの ack コマンド)
+	Acknowledge(ctx context.Context, id uuid.UUID, userID uuid.UUID) error
+
+	// Snooze は通知を一覧から隠し、指定時刻に再配信します。再配信の時刻を返します
+	Snooze(ctx context.Context, id uuid.UUID, userID uuid.UUID, req *models.SnoozeRequest) (time.Time, error)
+
+	// ReleaseDeferred は配信予定時刻を過ぎた保留中の通知の保留を解除し、解除できた通知を返します (WorkerServiceから呼ばれます)
+	ReleaseDeferred(ctx context.Context, now time.Time, limit int) ([]models.Notification, error)
 }
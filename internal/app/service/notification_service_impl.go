@@ -2,38 +2,105 @@ package service
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"log/slog"
 	"my-portfolio-2025/internal/app/apperr"
 	"my-portfolio-2025/internal/app/models"
 	"my-portfolio-2025/internal/app/repository"
+	"my-portfolio-2025/pkg/utils"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
+// 通知一覧の既定・最大の取得件数
+const (
+	defaultNotificationPageSize = 20
+	maxNotificationPageSize     = 100
+)
+
 type notificationServiceImpl struct {
-	repo repository.NotificationRepository
+	repo   repository.NotificationRepository
+	events NotificationEventPublisher
 }
 
-func NewNotificationService(repo repository.NotificationRepository) NotificationService {
-	return &notificationServiceImpl{repo: repo}
+// NewNotificationService は NotificationService を作成します
+// events が nil の場合、未読件数の配信は行いません
+func NewNotificationService(repo repository.NotificationRepository, events NotificationEventPublisher) NotificationService {
+	return &notificationServiceImpl{repo: repo, events: events}
 }
 
-// GetNotifications はユーザーの通知を10件ずつ取得します
-func (s *notificationServiceImpl) GetNotifications(ctx context.Context, userID uuid.UUID, page int) ([]models.Notification, error) {
-	limit := 10
-	if page < 1 {
-		page = 1
+// GetNotifications はユーザーの通知を新しい順に取得します
+func (s *notificationServiceImpl) GetNotifications(ctx context.Context, userID uuid.UUID, query *models.NotificationListQuery) (*models.NotificationPage, error) {
+	filter := models.NotificationFilter{
+		Type:   query.Type,
+		IsRead: query.IsRead,
+		Limit:  query.Limit,
+		// まとめる単位は今のところタスクのみ
+		GroupByTask: query.Group == models.NotificationGroupByTask,
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultNotificationPageSize
+	}
+	if filter.Limit > maxNotificationPageSize {
+		filter.Limit = maxNotificationPageSize
+	}
+	if query.TaskID != "" {
+		taskID, err := uuid.Parse(query.TaskID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid task_id", apperr.ErrValidation)
+		}
+		filter.TaskID = &taskID
 	}
-	offset := (page - 1) * limit
+	cursor, err := decodeNotificationCursor(query.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	filter.Before = cursor
 
-	notifications, err := s.repo.FindByUserID(ctx, userID, limit, offset)
+	// 次のページの有無を判定するため1件多く取得する
+	limit := filter.Limit
+	filter.Limit++
+	notifications, err := s.repo.FindByUserID(ctx, userID, filter)
 	if err != nil {
 		return nil, fmt.Errorf("notificationService.GetNotifications: %w", err)
 	}
 
-	return notifications, nil
+	page := &models.NotificationPage{Items: notifications}
+	if page.Items == nil {
+		page.Items = []models.Notification{}
+	}
+	if len(page.Items) > limit {
+		page.Items = page.Items[:limit]
+		last := page.Items[limit-1]
+		page.NextCursor = encodeNotificationCursor(models.NotificationCursor{CreatedAt: last.CreatedAt, ID: last.ID})
+	}
+	return page, nil
+}
+
+// GetByID はユーザーの通知を1件返します
+func (s *notificationServiceImpl) GetByID(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*models.Notification, error) {
+	n, err := s.repo.FindByID(ctx, id, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: notificationID %s for userID %s", apperr.ErrNotFound, id, userID)
+		}
+		return nil, fmt.Errorf("notificationService.GetByID: %w", err)
+	}
+	return n, nil
+}
+
+// UnreadCount は未読件数を返します
+func (s *notificationServiceImpl) UnreadCount(ctx context.Context, userID uuid.UUID) (int64, error) {
+	count, err := s.repo.CountUnread(ctx, userID)
+	if err != nil {
+		return 0, fmt.Errorf("notificationService.UnreadCount: %w", err)
+	}
+	return count, nil
 }
 
 // MarkAsRead は指定された通知を既読にします
@@ -48,17 +115,180 @@ func (s *notificationServiceImpl) MarkAsRead(ctx context.Context, id uuid.UUID,
 		// その他のエラーはそのまま返す
 		return fmt.Errorf("notificationService.MarkAsRead: %w", err)
 	}
+
+	s.publishUnreadCount(ctx, userID)
 	return nil
 }
 
+// MarkAllAsRead は未読の通知をすべて既読にします
+func (s *notificationServiceImpl) MarkAllAsRead(ctx context.Context, userID uuid.UUID) (int64, error) {
+	updated, err := s.repo.MarkAllAsRead(ctx, userID)
+	if err != nil {
+		return 0, fmt.Errorf("notificationService.MarkAllAsRead: %w", err)
+	}
+
+	if updated > 0 {
+		s.publishUnreadCount(ctx, userID)
+	}
+	return updated, nil
+}
+
+// Delete は通知を削除します
+func (s *notificationServiceImpl) Delete(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
+	err := s.repo.Delete(ctx, id, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: notificationID %s for userID %s", apperr.ErrNotFound, id, userID)
+		}
+		return fmt.Errorf("notificationService.Delete: %w", err)
+	}
+
+	s.publishUnreadCount(ctx, userID)
+	return nil
+}
+
+// Acknowledge はクライアントからの受信確認を記録します
+func (s *notificationServiceImpl) Acknowledge(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
+	err := s.repo.MarkAsAcked(ctx, id, userID, utils.NowJST())
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: notificationID %s for userID %s", apperr.ErrNotFound, id, userID)
+		}
+		return fmt.Errorf("notificationService.Acknowledge: %w", err)
+	}
+	return nil
+}
+
+// Snooze は通知をスヌーズします
+// 再配信はおやすみ時間帯の保留と同じ仕組み (deferred_until) で WorkerService の監視ループが行う
+func (s *notificationServiceImpl) Snooze(ctx context.Context, id uuid.UUID, userID uuid.UUID, req *models.SnoozeRequest) (time.Time, error) {
+	until, err := resolveSnooze(req, utils.NowJST())
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if err := s.repo.Snooze(ctx, id, userID, until); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return time.Time{}, fmt.Errorf("%w: notificationID %s for userID %s", apperr.ErrNotFound, id, userID)
+		}
+		return time.Time{}, fmt.Errorf("notificationService.Snooze: %w", err)
+	}
+
+	// 他のセッションの一覧からも隠せるようにイベントを配信する
+	if s.events != nil {
+		payload := models.WSNotificationSnoozedPayload{NotificationID: id, SnoozedUntil: until}
+		if err := s.events.PublishEvent(ctx, userID, nil, models.WSEventNotificationSnoozed, payload); err != nil {
+			slog.Error("Failed to publish notification snoozed event", "userID", userID, "error", err)
+		}
+	}
+	s.publishUnreadCount(ctx, userID)
+	return until, nil
+}
+
 // Create は通知を作成します
-func (s *notificationServiceImpl) Create(ctx context.Context, notification *models.Notification) error {
+func (s *notificationServiceImpl) Create(ctx context.Context, notification *models.Notification) (bool, error) {
 	if notification.UserID == uuid.Nil {
-		return fmt.Errorf("%w: userID is required for notification", apperr.ErrValidation)
+		return false, fmt.Errorf("%w: userID is required for notification", apperr.ErrValidation)
+	}
+
+	created, err := s.repo.Create(ctx, notification)
+	if err != nil {
+		return false, fmt.Errorf("notificationService.Create: %w", err)
+	}
+	if !created {
+		return false, nil
 	}
 
-	if err := s.repo.Create(ctx, notification); err != nil {
-		return fmt.Errorf("notificationService.Create: %w", err)
+	// 保留中の通知は保留解除時に件数を配信する
+	if notification.DeferredUntil == nil {
+		s.publishUnreadCount(ctx, notification.UserID)
+	}
+	return true, nil
+}
+
+// MarkDelivered は通知のリアルタイム配信を終えたことを記録します
+func (s *notificationServiceImpl) MarkDelivered(ctx context.Context, id uuid.UUID) error {
+	if err := s.repo.MarkDelivered(ctx, id, utils.NowJST()); err != nil {
+		return fmt.Errorf("notificationService.MarkDelivered: %w", err)
 	}
 	return nil
 }
+
+// ReleaseDeferred は保留中の通知のうち配信時刻を過ぎたものを解除します
+// 他のワーカーが先に解除した通知は結果に含めません
+func (s *notificationServiceImpl) ReleaseDeferred(ctx context.Context, now time.Time, limit int) ([]models.Notification, error) {
+	due, err := s.repo.FindDueDeferred(ctx, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("notificationService.ReleaseDeferred: %w", err)
+	}
+
+	released := make([]models.Notification, 0, len(due))
+	for _, n := range due {
+		ok, err := s.repo.ClearDeferred(ctx, n.ID)
+		if err != nil {
+			return released, fmt.Errorf("notificationService.ReleaseDeferred: %w", err)
+		}
+		if ok {
+			n.DeferredUntil = nil
+			released = append(released, n)
+		}
+	}
+
+	notified := make(map[uuid.UUID]struct{})
+	for _, n := range released {
+		if _, ok := notified[n.UserID]; !ok {
+			notified[n.UserID] = struct{}{}
+			s.publishUnreadCount(ctx, n.UserID)
+		}
+	}
+	return released, nil
+}
+
+// publishUnreadCount は最新の未読件数をユーザーの全セッションへ配信します
+// 配信の失敗で本処理を失敗させないよう、エラーはログに留める
+func (s *notificationServiceImpl) publishUnreadCount(ctx context.Context, userID uuid.UUID) {
+	if s.events == nil {
+		return
+	}
+
+	count, err := s.repo.CountUnread(ctx, userID)
+	if err != nil {
+		slog.Error("Failed to count unread notifications", "userID", userID, "error", err)
+		return
+	}
+
+	payload := models.WSUnreadCountPayload{UnreadCount: count}
+	if err := s.events.PublishEvent(ctx, userID, nil, models.WSEventUnreadCount, payload); err != nil {
+		slog.Error("Failed to publish unread count", "userID", userID, "error", err)
+	}
+}
+
+// encodeNotificationCursor はカーソルを不透明な文字列にします
+func encodeNotificationCursor(c models.NotificationCursor) string {
+	raw := c.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + c.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeNotificationCursor はカーソル文字列を戻します。空文字は先頭ページとして nil を返します
+func decodeNotificationCursor(cursor string) (*models.NotificationCursor, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid cursor", apperr.ErrValidation)
+	}
+	ts, id, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return nil, fmt.Errorf("%w: invalid cursor", apperr.ErrValidation)
+	}
+	createdAt, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid cursor", apperr.ErrValidation)
+	}
+	notificationID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid cursor", apperr.ErrValidation)
+	}
+	return &models.NotificationCursor{CreatedAt: createdAt, ID: notificationID}, nil
+}
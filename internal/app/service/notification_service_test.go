@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"my-portfolio-2025/internal/app/apperr"
+	"my-portfolio-2025/internal/app/models"
+	"my-portfolio-2025/internal/testutils/mock"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	mockPkg "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestGetNotifications_CursorPagination(t *testing.T) {
+	userID := uuid.New()
+	base := time.Date(2026, 4, 1, 9, 0, 0, 0, time.UTC)
+	items := []models.Notification{
+		{ID: uuid.New(), UserID: userID, CreatedAt: base.Add(2 * time.Minute)},
+		{ID: uuid.New(), UserID: userID, CreatedAt: base.Add(1 * time.Minute)},
+		{ID: uuid.New(), UserID: userID, CreatedAt: base},
+	}
+
+	repo := new(mock.MockNotificationRepository)
+	svc := NewNotificationService(repo, nil)
+
+	// 1ページ目: limit+1 件取得できたので next_cursor が返る
+	repo.On("FindByUserID", mockPkg.Anything, userID, models.NotificationFilter{Limit: 3}).Return(items, nil).Once()
+	page, err := svc.GetNotifications(context.Background(), userID, &models.NotificationListQuery{Limit: 2})
+	require.NoError(t, err)
+	assert.Len(t, page.Items, 2)
+	require.NotEmpty(t, page.NextCursor)
+
+	// 2ページ目: カーソルが1ページ目の最後の通知を指している
+	cursor := &models.NotificationCursor{CreatedAt: items[1].CreatedAt, ID: items[1].ID}
+	repo.On("FindByUserID", mockPkg.Anything, userID, mockPkg.MatchedBy(func(f models.NotificationFilter) bool {
+		return f.Before != nil && f.Before.ID == cursor.ID && f.Before.CreatedAt.Equal(cursor.CreatedAt)
+	})).Return(items[2:], nil).Once()
+	page, err = svc.GetNotifications(context.Background(), userID, &models.NotificationListQuery{Limit: 2, Cursor: page.NextCursor})
+	require.NoError(t, err)
+	assert.Len(t, page.Items, 1)
+	assert.Empty(t, page.NextCursor, "最後のページには next_cursor が無い")
+
+	repo.AssertExpectations(t)
+}
+
+func TestGetNotifications_InvalidCursor(t *testing.T) {
+	svc := NewNotificationService(new(mock.MockNotificationRepository), nil)
+
+	_, err := svc.GetNotifications(context.Background(), uuid.New(), &models.NotificationListQuery{Cursor: "broken"})
+
+	assert.ErrorIs(t, err, apperr.ErrValidation)
+}
+
+func TestMarkAllAsRead_PublishesUnreadCount(t *testing.T) {
+	userID := uuid.New()
+	repo := new(mock.MockNotificationRepository)
+	events := new(mock.MockNotificationEventPublisher)
+	svc := NewNotificationService(repo, events)
+
+	repo.On("MarkAllAsRead", mockPkg.Anything, userID).Return(int64(3), nil).Once()
+	repo.On("CountUnread", mockPkg.Anything, userID).Return(int64(0), nil).Once()
+	events.On("PublishEvent", mockPkg.Anything, userID, (*uuid.UUID)(nil), models.WSEventUnreadCount,
+		models.WSUnreadCountPayload{UnreadCount: 0}).Return(nil).Once()
+
+	updated, err := svc.MarkAllAsRead(context.Background(), userID)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), updated)
+	repo.AssertExpectations(t)
+	events.AssertExpectations(t)
+}
+
+func TestResolveSnooze(t *testing.T) {
+	now := time.Date(2026, 5, 12, 9, 0, 0, 0, time.UTC)
+	until := now.Add(3 * time.Hour)
+	past := now.Add(-time.Minute)
+	tooLate := now.Add((models.MaxSnoozeMinutes + 1) * time.Minute)
+
+	tests := []struct {
+		name     string
+		req      models.SnoozeRequest
+		expected time.Time
+		invalid  bool
+	}{
+		{name: "時間で指定", req: models.SnoozeRequest{DurationMinutes: 90}, expected: now.Add(90 * time.Minute)},
+		{name: "時刻で指定", req: models.SnoozeRequest{Until: &until}, expected: until},
+		{name: "両方の指定は不可", req: models.SnoozeRequest{DurationMinutes: 90, Until: &until}, invalid: true},
+		{name: "指定なしは不可", req: models.SnoozeRequest{}, invalid: true},
+		{name: "過去の時刻は不可", req: models.SnoozeRequest{Until: &past}, invalid: true},
+		{name: "30日より先は不可", req: models.SnoozeRequest{Until: &tooLate}, invalid: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveSnooze(&tt.req, now)
+			if tt.invalid {
+				assert.ErrorIs(t, err, apperr.ErrValidation)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, tt.expected.Equal(got))
+		})
+	}
+}
+
+func TestSnoozeNotification(t *testing.T) {
+	userID := uuid.New()
+	id := uuid.New()
+	repo := new(mock.MockNotificationRepository)
+	events := new(mock.MockNotificationEventPublisher)
+	svc := NewNotificationService(repo, events)
+
+	repo.On("Snooze", mockPkg.Anything, id, userID, mockPkg.AnythingOfType("time.Time")).Return(nil).Once()
+	repo.On("CountUnread", mockPkg.Anything, userID).Return(int64(1), nil).Once()
+	events.On("PublishEvent", mockPkg.Anything, userID, (*uuid.UUID)(nil), models.WSEventNotificationSnoozed,
+		mockPkg.AnythingOfType("models.WSNotificationSnoozedPayload")).Return(nil).Once()
+	events.On("PublishEvent", mockPkg.Anything, userID, (*uuid.UUID)(nil), models.WSEventUnreadCount,
+		models.WSUnreadCountPayload{UnreadCount: 1}).Return(nil).Once()
+
+	until, err := svc.Snooze(context.Background(), id, userID, &models.SnoozeRequest{DurationMinutes: 30})
+
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(30*time.Minute), until, 5*time.Second)
+	repo.AssertExpectations(t)
+	events.AssertExpectations(t)
+}
+
+func TestSnoozeNotification_NotFound(t *testing.T) {
+	repo := new(mock.MockNotificationRepository)
+	svc := NewNotificationService(repo, nil)
+
+	repo.On("Snooze", mockPkg.Anything, mockPkg.Anything, mockPkg.Anything, mockPkg.Anything).
+		Return(gorm.ErrRecordNotFound).Once()
+
+	_, err := svc.Snooze(context.Background(), uuid.New(), uuid.New(), &models.SnoozeRequest{DurationMinutes: 30})
+
+	assert.ErrorIs(t, err, apperr.ErrNotFound)
+}
+
+func TestCreateNotification_Duplicate(t *testing.T) {
+	userID := uuid.New()
+	repo := new(mock.MockNotificationRepository)
+	events := new(mock.MockNotificationEventPublisher)
+	svc := NewNotificationService(repo, events)
+
+	n := &models.Notification{ID: uuid.New(), UserID: userID}
+	repo.On("Create", mockPkg.Anything, n).Return(false, nil).Once()
+
+	created, err := svc.Create(context.Background(), n)
+
+	require.NoError(t, err)
+	assert.False(t, created)
+	// 作成していないので未読件数も配信しない
+	events.AssertNotCalled(t, "PublishEvent", mockPkg.Anything, mockPkg.Anything, mockPkg.Anything, mockPkg.Anything, mockPkg.Anything)
+}
+
+func TestGetNotifications_GroupByTask(t *testing.T) {
+	userID := uuid.New()
+	repo := new(mock.MockNotificationRepository)
+	svc := NewNotificationService(repo, nil)
+
+	grouped := []models.Notification{{ID: uuid.New(), UserID: userID, GroupCount: 3}}
+	repo.On("FindByUserID", mockPkg.Anything, userID, models.NotificationFilter{GroupByTask: true, Limit: 21}).Return(grouped, nil).Once()
+
+	page, err := svc.GetNotifications(context.Background(), userID, &models.NotificationListQuery{Group: models.NotificationGroupByTask})
+
+	require.NoError(t, err)
+	require.Len(t, page.Items, 1)
+	assert.Equal(t, 3, page.Items[0].GroupCount)
+	repo.AssertExpectations(t)
+}
+
+func TestNotificationDedupKey(t *testing.T) {
+	taskID := uuid.New()
+	at := time.Date(2026, 5, 13, 17, 0, 12, 0, time.UTC)
+
+	// 同じ時間枠 (1分) の同じタスク・種別は同じキー
+	assert.Equal(t,
+		models.NotificationDedupKey(models.NotificationTypeDeadline, taskID, at),
+		models.NotificationDedupKey(models.NotificationTypeDeadline, taskID, at.Add(40*time.Second)))
+	assert.NotEqual(t,
+		models.NotificationDedupKey(models.NotificationTypeDeadline, taskID, at),
+		models.NotificationDedupKey(models.NotificationTypeDeadline, taskID, at.Add(time.Minute)))
+	assert.NotEqual(t,
+		models.NotificationDedupKey(models.NotificationTypeDeadline, taskID, at),
+		models.NotificationDedupKey(models.NotificationTypeOverdue, taskID, at))
+
+	// 予定時刻が無い場合は受信時刻を使い、タスクに紐づかない通知は重複排除しない
+	msg := &models.NotificationMessage{TaskID: &taskID, Type: models.NotificationTypeDeadline}
+	assert.Equal(t, models.NotificationDedupKey(models.NotificationTypeDeadline, taskID, at), *dedupKey(msg, at))
+	assert.Nil(t, dedupKey(&models.NotificationMessage{Type: models.NotificationTypeSystem}, at))
+}
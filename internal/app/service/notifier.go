@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"my-portfolio-2025/internal/app/models"
+)
+
+// Notifier は通知を1つのチャネル (WebSocket / メール / Webhook など) へ配信します
+// WorkerService は通知設定で許可されたチャネルの Notifier だけを呼び出します
+type Notifier interface {
+	// Channel は配信チャネル名 (models.NotificationChannel*) を返します
+	Channel() string
+
+	// Notify は保存済みの通知を配信します
+	Notify(ctx context.Context, n *models.Notification) error
+}
+
+// notificationTitles は通知種別ごとの見出しです (チャット・Web Push で使用)
+var notificationTitles = map[string]string{
+	models.NotificationTypeDeadline: "タスクの期限が近づいています",
+	models.NotificationTypeOverdue:  "タスクの期限を過ぎています",
+	models.NotificationTypeMention:  "あなた宛てのメンションがあります",
+	models.NotificationTypeSystem:   "Kota Todo からのお知らせ",
+}
+
+// notificationTitle は通知種別の見出しを返します。未知の種別はお知らせとして扱います
+func notificationTitle(notificationType string) string {
+	if title, ok := notificationTitles[notificationType]; ok {
+		return title
+	}
+	return notificationTitles[models.NotificationTypeSystem]
+}
+
+// webSocketNotifier は Hub (Redis Pub/Sub) 経由で接続中のセッションへ配信します
+type webSocketNotifier struct {
+	hub *NotificationHub
+}
+
+// NewWebSocketNotifier は WebSocket チャネルの Notifier を作成します
+func NewWebSocketNotifier(hub *NotificationHub) Notifier {
+	return &webSocketNotifier{hub: hub}
+}
+
+func (w *webSocketNotifier) Channel() string { return models.NotificationChannelWebSocket }
+
+// Notify は通知を notification イベントとして配信します
+func (w *webSocketNotifier) Notify(ctx context.Context, n *models.Notification) error {
+	return w.hub.PublishMessage(ctx, models.NotificationMessage{
+		ID:      n.ID,
+		UserID:  n.UserID,
+		TaskID:  n.TaskID,
+		Type:    n.Type,
+		Message: n.Message,
+		Actions: n.Actions,
+	})
+}
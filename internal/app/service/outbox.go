@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"my-portfolio-2025/internal/app/models"
+	"my-portfolio-2025/internal/app/repository"
+	"my-portfolio-2025/pkg/utils"
+	"time"
+)
+
+const (
+	// リレーが1回のトランザクションで送るジョブの最大数
+	outboxBatchSize = 100
+	// 未送信のジョブを確認する間隔
+	outboxPollInterval = time.Second
+	// 送信に失敗したジョブの再送間隔 (失敗するごとに倍にし、outboxRetryMaxDelay で頭打ちにする)
+	outboxRetryBaseDelay = 5 * time.Second
+	outboxRetryMaxDelay  = 5 * time.Minute
+	// 送信済みのジョブを残す期間 (削除は定期ジョブ purge_outbox で行う)
+	outboxRetention = 7 * 24 * time.Hour
+)
+
+// Outbox はキューへ送るジョブを DB に書き込み、コミット後にリレーが SQS へ送ります (transactional outbox)
+// ドメインの変更とジョブの書き込みを Within で1つのトランザクションにまとめることで、
+// 「DB は更新されたがジョブが送られない」「ジョブは送られたが DB の更新はロールバックされた」状態を防ぐ
+//
+// nil の *Outbox も使えます (Within は fn をそのまま実行し、RecordTaskEvent は何もしない)
+type Outbox struct {
+	tx    repository.Transactor
+	repo  repository.OutboxRepository
+	queue JobQueue // リレーの送信先。nil の場合 Run は何もしない
+}
+
+// NewOutbox は Outbox を作成します
+func NewOutbox(tx repository.Transactor, repo repository.OutboxRepository, queue JobQueue) *Outbox {
+	return &Outbox{tx: tx, repo: repo, queue: queue}
+}
+
+// Within は fn を1つのトランザクション内で実行します
+// fn の中では渡された ctx を使ってリポジトリと Enqueue を呼び出してください
+func (o *Outbox) Within(ctx context.Context, fn func(ctx context.Context) error) error {
+	if o == nil || o.tx == nil {
+		return fn(ctx)
+	}
+	return o.tx.Transaction(ctx, fn)
+}
+
+// Enqueue はジョブを outbox に書き込みます (JobQueue の実装)
+// body は JobEnvelope の JSON で、封筒の種別と相関ID (無ければジョブID) ごとに書き込み順に送られます
+func (o *Outbox) Enqueue(ctx context.Context, body []byte, delay time.Duration) error {
+	if o == nil {
+		return errors.New("outbox.Enqueue: outbox is not configured")
+	}
+
+	var env models.JobEnvelope
+	if err := json.Unmarshal(body, &env); err != nil {
+		return fmt.Errorf("outbox.Enqueue: unmarshal job envelope: %w", err)
+	}
+	if env.Type == "" {
+		return errors.New("outbox.Enqueue: job envelope requires type")
+	}
+	aggregateID := env.CorrelationID
+	if aggregateID == "" {
+		aggregateID = env.ID.String()
+	}
+
+	now := utils.NowJST()
+	msg := &models.OutboxMessage{
+		AggregateType: env.Type,
+		AggregateID:   aggregateID,
+		Body:          string(body),
+		DelaySeconds:  int(delay / time.Second),
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}
+	if err := o.repo.Add(ctx, msg); err != nil {
+		return fmt.Errorf("outbox.Enqueue: %w", err)
+	}
+	return nil
+}
+
+// RecordTaskEvent はタスクの変更イベントを task_event ジョブとして書き込みます
+// ワーカーがこのジョブから Webhook の配信ジョブを作成するため、タスクの変更と同じトランザクションで呼んでください
+func (o *Outbox) RecordTaskEvent(ctx context.Context, event *models.TaskEvent) error {
+	if o == nil {
+		return nil
+	}
+	return enqueueJob(ctx, o, models.JobTypeTaskEvent, event.TaskID.String(), event, 0)
+}
+
+// Run は未送信のジョブを SQS へ送り続けるリレーです。ctx がキャンセルされるまで戻りません
+// 複数のワーカーで動かしても、行ロック (SKIP LOCKED) により同じジョブを同時に送ることはない
+func (o *Outbox) Run(ctx context.Context) {
+	if o == nil || o.queue == nil {
+		return
+	}
+	slog.Info("Outbox relay started")
+
+	ticker := time.NewTicker(outboxPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("Outbox relay stopped")
+			return
+		case <-ticker.C:
+			// 1回で送りきれなかった場合は、未送信のジョブが無くなるまで続けて送る
+			for {
+				n, err := o.relayBatch(ctx)
+				if err != nil {
+					if ctx.Err() == nil {
+						slog.Error("Failed to relay outbox messages", "error", err)
+					}
+					break
+				}
+				if n < outboxBatchSize {
+					break
+				}
+			}
+		}
+	}
+}
+
+// relayBatch は送信できるジョブをロックして SQS へ送り、処理したジョブの数を返します
+// SQS へ送った後に送信済みの記録がコミットできなかった場合は再送されるため、ジョブは at-least-once で届く
+func (o *Outbox) relayBatch(ctx context.Context) (int, error) {
+	var n int
+	err := o.tx.Transaction(ctx, func(ctx context.Context) error {
+		now := utils.NowJST()
+		msgs, err := o.repo.LockPending(ctx, now, outboxBatchSize)
+		if err != nil {
+			return err
+		}
+		n = len(msgs)
+
+		for _, msg := range msgs {
+			delay := time.Duration(msg.DelaySeconds) * time.Second
+			if err := o.queue.Enqueue(ctx, []byte(msg.Body), delay); err != nil {
+				slog.Warn("Failed to send outbox message",
+					"id", msg.ID,
+					"aggregateType", msg.AggregateType,
+					"aggregateID", msg.AggregateID,
+					"attempts", msg.Attempts+1,
+					"error", err,
+				)
+				// 同じ集約の後続のジョブは、このジョブが送られるまで LockPending で返されない
+				if err := o.repo.MarkFailed(ctx, msg.ID, err.Error(), now.Add(outboxRetryDelay(msg.Attempts+1))); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := o.repo.MarkSent(ctx, msg.ID, now); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("outbox.relayBatch: %w", err)
+	}
+	return n, nil
+}
+
+// PurgeSent は保持期間を過ぎた送信済みのジョブを削除します (定期ジョブとして実行する)
+func (o *Outbox) PurgeSent(ctx context.Context) error {
+	if o == nil {
+		return nil
+	}
+	deleted, err := o.repo.DeleteSentBefore(ctx, utils.NowJST().Add(-outboxRetention))
+	if err != nil {
+		return fmt.Errorf("Outbox.PurgeSent: %w", err)
+	}
+	if deleted > 0 {
+		slog.Info("Purged sent outbox messages", "count", deleted)
+	}
+	return nil
+}
+
+// outboxRetryDelay は attempts 回目の送信失敗の後、次に送るまでの待ち時間を返します
+func outboxRetryDelay(attempts int) time.Duration {
+	delay := outboxRetryBaseDelay
+	for i := 1; i < attempts && delay < outboxRetryMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, outboxRetryMaxDelay)
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"my-portfolio-2025/internal/app/models"
+	"my-portfolio-2025/internal/testutils/mock"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	mockPkg "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTransactor は fn をそのまま実行し、トランザクションの開始回数を記録するテスト用の Transactor です
+type fakeTransactor struct {
+	calls int
+}
+
+func (f *fakeTransactor) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	f.calls++
+	return fn(ctx)
+}
+
+// failingJobQueue は指定した本文の送信だけ失敗させるテスト用の JobQueue です
+type failingJobQueue struct {
+	fakeJobQueue
+	fail string
+}
+
+func (q *failingJobQueue) Enqueue(ctx context.Context, body []byte, delay time.Duration) error {
+	if string(body) == q.fail {
+		return errors.New("sqs unavailable")
+	}
+	return q.fakeJobQueue.Enqueue(ctx, body, delay)
+}
+
+func TestOutboxEnqueue_GroupsByEnvelope(t *testing.T) {
+	repo := new(mock.MockOutboxRepository)
+	outbox := NewOutbox(&fakeTransactor{}, repo, nil)
+
+	taskID := uuid.New()
+	withCorrelation, err := encodeJob(models.JobTypeNotification, taskID.String(), map[string]string{"a": "b"})
+	require.NoError(t, err)
+	withoutCorrelation, err := encodeJob(models.JobTypeNotification, "", map[string]string{"a": "b"})
+	require.NoError(t, err)
+	env, err := decodeJobEnvelope(string(withoutCorrelation))
+	require.NoError(t, err)
+
+	var added []*models.OutboxMessage
+	repo.On("Add", mockPkg.Anything, mockPkg.AnythingOfType("*models.OutboxMessage")).
+		Run(func(args mockPkg.Arguments) { added = append(added, args.Get(1).(*models.OutboxMessage)) }).
+		Return(nil)
+
+	require.NoError(t, outbox.Enqueue(context.Background(), withCorrelation, 90*time.Second))
+	require.NoError(t, outbox.Enqueue(context.Background(), withoutCorrelation, 0))
+
+	require.Len(t, added, 2)
+	// 相関ID があれば相関ID、無ければジョブIDごとに順序を保つ
+	assert.Equal(t, models.JobTypeNotification, added[0].AggregateType)
+	assert.Equal(t, taskID.String(), added[0].AggregateID)
+	assert.Equal(t, 90, added[0].DelaySeconds)
+	assert.Equal(t, string(withCorrelation), added[0].Body)
+	assert.Equal(t, env.ID.String(), added[1].AggregateID)
+
+	// 封筒でない本文は書き込まない
+	assert.Error(t, outbox.Enqueue(context.Background(), []byte(`not json`), 0))
+	repo.AssertNumberOfCalls(t, "Add", 2)
+}
+
+func TestOutboxRelayBatch_MarksSentAndBacksOffFailures(t *testing.T) {
+	repo := new(mock.MockOutboxRepository)
+	tx := &fakeTransactor{}
+	queue := &failingJobQueue{fail: `{"n":2}`}
+	outbox := NewOutbox(tx, repo, queue)
+
+	pending := []models.OutboxMessage{
+		{ID: 1, Body: `{"n":1}`, DelaySeconds: 30},
+		{ID: 2, Body: `{"n":2}`, Attempts: 2},
+	}
+	repo.On("LockPending", mockPkg.Anything, mockPkg.Anything, outboxBatchSize).Return(pending, nil).Once()
+	repo.On("MarkSent", mockPkg.Anything, int64(1), mockPkg.Anything).Return(nil).Once()
+	var nextAttempt time.Time
+	repo.On("MarkFailed", mockPkg.Anything, int64(2), "sqs unavailable", mockPkg.Anything).
+		Run(func(args mockPkg.Arguments) { nextAttempt = args.Get(3).(time.Time) }).
+		Return(nil).Once()
+
+	n, err := outbox.relayBatch(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, 1, tx.calls)
+	// 送れたジョブだけを遅延付きで送る
+	require.Len(t, queue.bodies, 1)
+	assert.Equal(t, `{"n":1}`, string(queue.bodies[0]))
+	assert.Equal(t, 30*time.Second, queue.delays[0])
+	// 3回目の失敗なので基本の待ち時間の4倍待つ
+	lockedAt := repo.Calls[0].Arguments.Get(1).(time.Time)
+	assert.Equal(t, 4*outboxRetryBaseDelay, nextAttempt.Sub(lockedAt))
+	repo.AssertExpectations(t)
+}
+
+func TestOutboxRelayBatch_RollsBackOnDBError(t *testing.T) {
+	repo := new(mock.MockOutboxRepository)
+	queue := &fakeJobQueue{}
+	outbox := NewOutbox(&fakeTransactor{}, repo, queue)
+
+	repo.On("LockPending", mockPkg.Anything, mockPkg.Anything, outboxBatchSize).
+		Return([]models.OutboxMessage{{ID: 1, Body: `{}`}}, nil).Once()
+	repo.On("MarkSent", mockPkg.Anything, int64(1), mockPkg.Anything).Return(errors.New("db down")).Once()
+
+	n, err := outbox.relayBatch(context.Background())
+
+	// 送信済みを記録できなかったジョブは次回に再送される (at-least-once)
+	assert.Error(t, err)
+	assert.Zero(t, n)
+	assert.Len(t, queue.bodies, 1)
+}
+
+func TestOutboxRetryDelay(t *testing.T) {
+	assert.Equal(t, outboxRetryBaseDelay, outboxRetryDelay(1))
+	assert.Equal(t, 2*outboxRetryBaseDelay, outboxRetryDelay(2))
+	assert.Equal(t, 8*outboxRetryBaseDelay, outboxRetryDelay(4))
+	assert.Equal(t, outboxRetryMaxDelay, outboxRetryDelay(100))
+}
+
+func TestOutboxPurgeSent_DeletesBeforeRetention(t *testing.T) {
+	repo := new(mock.MockOutboxRepository)
+	outbox := NewOutbox(&fakeTransactor{}, repo, nil)
+	repo.On("DeleteSentBefore", mockPkg.Anything, mockPkg.MatchedBy(func(before time.Time) bool {
+		// 保持期間 (7日) より前に送信したジョブだけを削除する
+		return time.Since(before) > outboxRetention-time.Minute && time.Since(before) < outboxRetention+time.Minute
+	})).Return(int64(3), nil).Once()
+
+	require.NoError(t, outbox.PurgeSent(context.Background()))
+	repo.AssertExpectations(t)
+
+	repo.On("DeleteSentBefore", mockPkg.Anything, mockPkg.Anything).Return(int64(0), errors.New("connection refused")).Once()
+	assert.Error(t, outbox.PurgeSent(context.Background()), "失敗は定期ジョブの実行履歴に記録する")
+}
+
+func TestOutbox_NilRunsWithoutTransaction(t *testing.T) {
+	var outbox *Outbox
+	called := false
+
+	err := outbox.Within(context.Background(), func(ctx context.Context) error {
+		called = true
+		return outbox.RecordTaskEvent(ctx, &models.TaskEvent{TaskID: uuid.New()})
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, called)
+	assert.Error(t, outbox.Enqueue(context.Background(), []byte(`{}`), 0))
+}
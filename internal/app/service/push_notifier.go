@@ -0,0 +1,34 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"my-portfolio-2025/internal/app/models"
+)
+
+// pushNotifier は WebSocket で接続していないユーザーへ Web Push で通知します
+type pushNotifier struct {
+	push     PushService
+	presence PresenceChecker
+}
+
+// NewPushNotifier は Web Push チャネルの Notifier を作成します
+func NewPushNotifier(push PushService, presence PresenceChecker) Notifier {
+	return &pushNotifier{push: push, presence: presence}
+}
+
+func (p *pushNotifier) Channel() string { return models.NotificationChannelPush }
+
+// Notify はユーザーがアプリを開いていない場合だけ Web Push を送ります
+// 接続中なら WebSocket で届くため、二重に通知しないようにする
+func (p *pushNotifier) Notify(ctx context.Context, n *models.Notification) error {
+	online, err := p.presence.IsOnline(ctx, n.UserID)
+	if err != nil {
+		// 判定できない場合は届かないよりは重複する方を選ぶ
+		slog.Warn("Presence check failed; sending web push anyway", "userID", n.UserID, "error", err)
+	}
+	if online {
+		return nil
+	}
+	return p.push.Notify(ctx, n)
+}
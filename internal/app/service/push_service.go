@@ -0,0 +1,35 @@
+package service
+
+import (
+	"context"
+	"my-portfolio-2025/internal/app/models"
+	"my-portfolio-2025/internal/infrastructure/webpush"
+
+	"github.com/google/uuid"
+)
+
+// PushSender はプッシュサービスへの送信を抽象化します (webpush.Client が実装)
+type PushSender interface {
+	PublicKey() string
+	Send(ctx context.Context, sub *webpush.Subscription, payload []byte, opts webpush.Options) error
+}
+
+// PresenceChecker はユーザーが WebSocket で接続中かを判定します (NotificationHub が実装)
+type PresenceChecker interface {
+	IsOnline(ctx context.Context, userID uuid.UUID) (bool, error)
+}
+
+// PushService は Web Push の購読管理と送信を扱います
+type PushService interface {
+	// PublicKey はブラウザの購読に使う VAPID 公開鍵を返します
+	PublicKey() (string, error)
+
+	// Subscribe はブラウザの購読を保存します
+	Subscribe(ctx context.Context, userID uuid.UUID, req *models.PushSubscriptionRequest, userAgent string) (*models.PushSubscription, error)
+
+	// Unsubscribe はブラウザの購読を削除します
+	Unsubscribe(ctx context.Context, userID uuid.UUID, endpoint string) error
+
+	// Notify は通知をユーザーの全ての購読へ送信し、失効した購読を削除します
+	Notify(ctx context.Context, n *models.Notification) error
+}
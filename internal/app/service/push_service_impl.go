@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"my-portfolio-2025/internal/app/apperr"
+	"my-portfolio-2025/internal/app/models"
+	"my-portfolio-2025/internal/app/repository"
+	"my-portfolio-2025/internal/infrastructure/egress"
+	"my-portfolio-2025/internal/infrastructure/webpush"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ブラウザがオフラインの間、プッシュサービスに保持してもらう時間
+const pushTTL = 24 * time.Hour
+
+type pushServiceImpl struct {
+	repo   repository.PushSubscriptionRepository
+	sender PushSender
+	guard  *egress.Guard // エンドポイントの検査 (Webhook と同じルール)
+}
+
+// NewPushService は PushService の新しいインスタンスを作成します
+// sender が nil の場合 (VAPID 未設定) は Web Push を無効として扱います
+func NewPushService(repo repository.PushSubscriptionRepository, sender PushSender) PushService {
+	return &pushServiceImpl{repo: repo, sender: sender, guard: egress.Default}
+}
+
+// PublicKey は VAPID 公開鍵を返します
+func (s *pushServiceImpl) PublicKey() (string, error) {
+	if s.sender == nil {
+		return "", fmt.Errorf("%w: web push is not configured", apperr.ErrNotFound)
+	}
+	return s.sender.PublicKey(), nil
+}
+
+// Subscribe は購読を保存します
+func (s *pushServiceImpl) Subscribe(ctx context.Context, userID uuid.UUID, req *models.PushSubscriptionRequest, userAgent string) (*models.PushSubscription, error) {
+	if s.sender == nil {
+		return nil, fmt.Errorf("%w: web push is not configured", apperr.ErrNotFound)
+	}
+	if err := webpush.ValidateKeys(req.Keys.P256dh, req.Keys.Auth); err != nil {
+		return nil, fmt.Errorf("%w: invalid subscription keys: %v", apperr.ErrValidation, err)
+	}
+	// エンドポイントはワーカーが POST する送信先のため、https のみとし内部アドレスを拒否する
+	if err := s.guard.ValidateURL(ctx, req.Endpoint); err != nil {
+		return nil, fmt.Errorf("%w: endpoint: %v", apperr.ErrValidation, err)
+	}
+	if len(userAgent) > 255 {
+		userAgent = userAgent[:255]
+	}
+
+	sub := &models.PushSubscription{
+		UserID:    userID,
+		Endpoint:  req.Endpoint,
+		P256dh:    req.Keys.P256dh,
+		Auth:      req.Keys.Auth,
+		UserAgent: userAgent,
+	}
+	if err := s.repo.Upsert(ctx, sub); err != nil {
+		return nil, fmt.Errorf("pushService.Subscribe: %w", err)
+	}
+	return sub, nil
+}
+
+// Unsubscribe は購読を削除します
+func (s *pushServiceImpl) Unsubscribe(ctx context.Context, userID uuid.UUID, endpoint string) error {
+	if err := s.repo.DeleteByEndpoint(ctx, userID, endpoint); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: push subscription", apperr.ErrNotFound)
+		}
+		return fmt.Errorf("pushService.Unsubscribe: %w", err)
+	}
+	return nil
+}
+
+// Notify は通知をユーザーの全ての購読へ送信します
+// 404 / 410 が返った購読は失効しているため削除します
+func (s *pushServiceImpl) Notify(ctx context.Context, n *models.Notification) error {
+	if s.sender == nil {
+		return nil
+	}
+	subs, err := s.repo.FindByUserID(ctx, n.UserID)
+	if err != nil {
+		return fmt.Errorf("pushService.Notify: %w", err)
+	}
+	if len(subs) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(models.PushPayload{
+		NotificationID: n.ID,
+		Type:           n.Type,
+		Title:          notificationTitle(n.Type),
+		Body:           n.Message,
+		TaskID:         n.TaskID,
+		Actions:        n.Actions,
+		CreatedAt:      n.CreatedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("pushService.Notify (marshal): %w", err)
+	}
+	opts := webpush.Options{TTL: pushTTL, Urgency: pushUrgency(n.Type)}
+
+	var errs []error
+	for _, sub := range subs {
+		target := &webpush.Subscription{Endpoint: sub.Endpoint, P256dh: sub.P256dh, Auth: sub.Auth}
+		err := s.sender.Send(ctx, target, payload, opts)
+		switch {
+		case err == nil:
+		case errors.Is(err, webpush.ErrSubscriptionGone):
+			slog.Info("Removing expired push subscription", "subscriptionID", sub.ID, "userID", sub.UserID)
+			if err := s.repo.Delete(ctx, sub.ID); err != nil {
+				errs = append(errs, err)
+			}
+		default:
+			slog.Warn("Web push failed", "subscriptionID", sub.ID, "notificationID", n.ID, "error", err)
+			errs = append(errs, err)
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("pushService.Notify: %w", err)
+	}
+	return nil
+}
+
+// pushUrgency は通知種別ごとの Urgency です
+// 期限・期限切れとメンションは端末が省電力中でもすぐに届けてもらう
+func pushUrgency(notificationType string) string {
+	switch notificationType {
+	case models.NotificationTypeDeadline, models.NotificationTypeOverdue, models.NotificationTypeMention:
+		return "high"
+	default:
+		return "normal"
+	}
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"my-portfolio-2025/internal/app/apperr"
+	"my-portfolio-2025/internal/app/models"
+	"my-portfolio-2025/internal/infrastructure/webpush"
+	"my-portfolio-2025/internal/testutils/mock"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	mockPkg "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePushSender は送信内容を記録し、エンドポイントごとに決めた結果を返すテスト用の PushSender です
+type fakePushSender struct {
+	results  map[string]error
+	payloads map[string][]byte
+}
+
+func (f *fakePushSender) PublicKey() string { return "test-public-key" }
+
+func (f *fakePushSender) Send(ctx context.Context, sub *webpush.Subscription, payload []byte, opts webpush.Options) error {
+	if f.payloads == nil {
+		f.payloads = make(map[string][]byte)
+	}
+	f.payloads[sub.Endpoint] = payload
+	return f.results[sub.Endpoint]
+}
+
+func TestPushSubscribe_RejectsInternalEndpoint(t *testing.T) {
+	key, err := ecdh.P256().GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	auth := make([]byte, 16)
+	_, err = rand.Read(auth)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		endpoint string
+	}{
+		{name: "http", endpoint: "http://93.184.216.34/push/abc"},
+		{name: "メタデータエンドポイント", endpoint: "https://169.254.169.254/latest/meta-data/"},
+		{name: "プライベートアドレス", endpoint: "https://10.0.0.12/push/abc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mock.MockPushSubscriptionRepository)
+			req := &models.PushSubscriptionRequest{Endpoint: tt.endpoint}
+			req.Keys.P256dh = base64.RawURLEncoding.EncodeToString(key.PublicKey().Bytes())
+			req.Keys.Auth = base64.RawURLEncoding.EncodeToString(auth)
+
+			_, err := NewPushService(repo, &fakePushSender{}).Subscribe(context.Background(), uuid.New(), req, "test")
+
+			assert.ErrorIs(t, err, apperr.ErrValidation)
+			repo.AssertNotCalled(t, "Upsert", mockPkg.Anything, mockPkg.Anything)
+		})
+	}
+}
+
+func TestPushNotify_RemovesExpiredSubscriptions(t *testing.T) {
+	userID := uuid.New()
+	active := models.PushSubscription{ID: uuid.New(), UserID: userID, Endpoint: "https://push.example.com/active"}
+	expired := models.PushSubscription{ID: uuid.New(), UserID: userID, Endpoint: "https://push.example.com/expired"}
+
+	repo := new(mock.MockPushSubscriptionRepository)
+	repo.On("FindByUserID", mockPkg.Anything, userID).Return([]models.PushSubscription{expired, active}, nil)
+	repo.On("Delete", mockPkg.Anything, expired.ID).Return(nil).Once()
+
+	sender := &fakePushSender{results: map[string]error{
+		expired.Endpoint: fmt.Errorf("webpush.Client.Send (status=410): %w", webpush.ErrSubscriptionGone),
+	}}
+
+	n := &models.Notification{ID: uuid.New(), UserID: userID, Type: models.NotificationTypeMention, Message: "@kota 確認お願いします"}
+	require.NoError(t, NewPushService(repo, sender).Notify(context.Background(), n))
+
+	var payload models.PushPayload
+	require.NoError(t, json.Unmarshal(sender.payloads[active.Endpoint], &payload))
+	assert.Equal(t, n.ID, payload.NotificationID)
+	assert.Equal(t, "あなた宛てのメンションがあります", payload.Title)
+	repo.AssertExpectations(t)
+}
+
+func TestPushNotifier_SkipsOnlineUsers(t *testing.T) {
+	tests := []struct {
+		name       string
+		online     bool
+		presErr    error
+		expectSent bool
+	}{
+		{name: "接続中のユーザーには送らない (WebSocket で届く)", online: true},
+		{name: "接続していないユーザーには送る", online: false, expectSent: true},
+		{name: "接続状態が分からない場合は送る", presErr: fmt.Errorf("redis down"), expectSent: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userID := uuid.New()
+			sub := models.PushSubscription{ID: uuid.New(), UserID: userID, Endpoint: "https://push.example.com/a"}
+
+			repo := new(mock.MockPushSubscriptionRepository)
+			repo.On("FindByUserID", mockPkg.Anything, userID).Return([]models.PushSubscription{sub}, nil).Maybe()
+			presence := new(mock.MockPresenceChecker)
+			presence.On("IsOnline", mockPkg.Anything, userID).Return(tt.online, tt.presErr)
+			sender := &fakePushSender{}
+
+			n := &models.Notification{ID: uuid.New(), UserID: userID, Type: models.NotificationTypeDeadline}
+			require.NoError(t, NewPushNotifier(NewPushService(repo, sender), presence).Notify(context.Background(), n))
+
+			_, sent := sender.payloads[sub.Endpoint]
+			assert.Equal(t, tt.expectSent, sent)
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package service
+
+import (
+	"context"
+	"my-portfolio-2025/internal/app/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReminderService はタスクの期限リマインダーの設定と送信対象の取得を扱います
+type ReminderService interface {
+	// List はタスクのリマインダーを返します
+	List(ctx context.Context, userID uuid.UUID, taskID uuid.UUID) ([]models.TaskReminder, error)
+
+	// Replace はタスクのリマインダーを置き換えます (offsets は期限の何分前か)
+	Replace(ctx context.Context, userID uuid.UUID, taskID uuid.UUID, offsets []int) ([]models.TaskReminder, error)
+
+	// ClaimDue は送信時刻を迎えたリマインダーを送信済みにして返します (WorkerService から呼ばれます)
+	ClaimDue(ctx context.Context, now time.Time, limit int) ([]models.DueReminder, error)
+
+	// Release は送信に失敗したリマインダーを未送信に戻し、次の監視ループで再送させます
+	Release(ctx context.Context, reminderID uuid.UUID) error
+
+	// PublishTaskEvent はタスクの作成・期限変更・削除に合わせてリマインダーを設定し直します (TaskEventPublisher)
+	PublishTaskEvent(ctx context.Context, event *models.TaskEvent) error
+}
@@ -0,0 +1,266 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"my-portfolio-2025/internal/app/apperr"
+	"my-portfolio-2025/internal/app/models"
+	"my-portfolio-2025/internal/app/repository"
+	"my-portfolio-2025/pkg/utils"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type reminderServiceImpl struct {
+	repo        repository.TaskReminderRepository
+	taskRepo    repository.TaskRepository
+	prefService NotificationPreferenceService
+}
+
+// NewReminderService は ReminderService の新しいインスタンスを作成します
+func NewReminderService(repo repository.TaskReminderRepository, taskRepo repository.TaskRepository, prefService NotificationPreferenceService) ReminderService {
+	return &reminderServiceImpl{repo: repo, taskRepo: taskRepo, prefService: prefService}
+}
+
+// List はタスクのリマインダーを返します
+func (s *reminderServiceImpl) List(ctx context.Context, userID uuid.UUID, taskID uuid.UUID) ([]models.TaskReminder, error) {
+	if _, err := s.findOwnedTask(userID, taskID); err != nil {
+		return nil, err
+	}
+	reminders, err := s.repo.FindByTaskID(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("reminderService.List: %w", err)
+	}
+	if reminders == nil {
+		reminders = []models.TaskReminder{}
+	}
+	return reminders, nil
+}
+
+// Replace はタスクのリマインダーを置き換えます
+// 既に送信済みのリマインダーは、送信時刻が変わらず過去のままなら送信済みのまま残します
+func (s *reminderServiceImpl) Replace(ctx context.Context, userID uuid.UUID, taskID uuid.UUID, offsets []int) ([]models.TaskReminder, error) {
+	normalized, err := normalizeReminderOffsets(offsets)
+	if err != nil {
+		return nil, err
+	}
+	task, err := s.findOwnedTask(userID, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if task.DueDate.IsZero() && len(normalized) > 0 {
+		return nil, fmt.Errorf("%w: task has no due date", apperr.ErrValidation)
+	}
+
+	existing, err := s.repo.FindByTaskID(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("reminderService.Replace: %w", err)
+	}
+	reminders, err := s.schedule(ctx, task, normalized, firedOffsets(existing))
+	if err != nil {
+		return nil, fmt.Errorf("reminderService.Replace: %w", err)
+	}
+	return reminders, nil
+}
+
+// ClaimDue は送信時刻を迎えたリマインダーと対象タスクを返します
+func (s *reminderServiceImpl) ClaimDue(ctx context.Context, now time.Time, limit int) ([]models.DueReminder, error) {
+	claimed, err := s.repo.ClaimDue(ctx, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("reminderService.ClaimDue: %w", err)
+	}
+
+	due := make([]models.DueReminder, 0, len(claimed))
+	for _, reminder := range claimed {
+		task, err := s.taskRepo.FindByID(reminder.TaskID)
+		if err != nil {
+			// 取得と同時にタスクが削除された場合など。送信済みのまま捨てる
+			slog.Warn("Reminder dropped: task not found", "reminderID", reminder.ID, "taskID", reminder.TaskID, "error", err)
+			continue
+		}
+		due = append(due, models.DueReminder{Reminder: reminder, Task: *task})
+	}
+	return due, nil
+}
+
+// Release はリマインダーを未送信に戻します
+func (s *reminderServiceImpl) Release(ctx context.Context, reminderID uuid.UUID) error {
+	if err := s.repo.Unclaim(ctx, reminderID); err != nil {
+		return fmt.Errorf("reminderService.Release: %w", err)
+	}
+	return nil
+}
+
+// PublishTaskEvent はタスクの変更に合わせてリマインダーを設定し直します
+//   - 作成: ユーザーの既定のリマインダーを設定する
+//   - 期限の変更: 同じ offset のまま送信時刻を計算し直す (リマインダーが無ければ既定値を設定する)
+//   - 削除: リマインダーを削除する
+func (s *reminderServiceImpl) PublishTaskEvent(ctx context.Context, event *models.TaskEvent) error {
+	switch event.Type {
+	case models.WSEventTaskDeleted:
+		if err := s.repo.DeleteByTaskID(ctx, event.TaskID); err != nil {
+			return fmt.Errorf("reminderService.PublishTaskEvent: %w", err)
+		}
+		return nil
+
+	case models.WSEventTaskCreated, models.WSEventTaskUpdated:
+		if event.Task == nil {
+			return nil
+		}
+		if event.Type == models.WSEventTaskUpdated && !slices.Contains(event.ChangedFields, "due_date") {
+			return nil
+		}
+
+		var existing []models.TaskReminder
+		if event.Type == models.WSEventTaskUpdated {
+			var err error
+			if existing, err = s.repo.FindByTaskID(ctx, event.TaskID); err != nil {
+				return fmt.Errorf("reminderService.PublishTaskEvent: %w", err)
+			}
+		}
+
+		offsets := make([]int, 0, len(existing))
+		for _, r := range existing {
+			offsets = append(offsets, r.OffsetMinutes)
+		}
+		if len(offsets) == 0 {
+			pref, err := s.prefService.Get(ctx, event.UserID)
+			if err != nil {
+				return fmt.Errorf("reminderService.PublishTaskEvent: %w", err)
+			}
+			offsets = pref.EffectiveReminderOffsets()
+		}
+
+		if _, err := s.schedule(ctx, event.Task, offsets, firedOffsets(existing)); err != nil {
+			return fmt.Errorf("reminderService.PublishTaskEvent: %w", err)
+		}
+		return nil
+	}
+	return nil
+}
+
+// schedule はタスクの期限からリマインダーを作成して保存します
+func (s *reminderServiceImpl) schedule(ctx context.Context, task *models.Task, offsets []int, fired map[int]bool) ([]models.TaskReminder, error) {
+	var reminders []models.TaskReminder
+	if !task.DueDate.IsZero() {
+		reminders = planReminders(task.DueDate, offsets, fired, utils.NowJST())
+	}
+	for i := range reminders {
+		reminders[i].TaskID = task.ID
+		reminders[i].UserID = task.UserID
+	}
+
+	if err := s.repo.ReplaceForTask(ctx, task.ID, reminders); err != nil {
+		return nil, err
+	}
+	if reminders == nil {
+		reminders = []models.TaskReminder{}
+	}
+	return reminders, nil
+}
+
+// findOwnedTask はタスクを取得し、所有者を確認します (TaskService と同じ認可方針)
+func (s *reminderServiceImpl) findOwnedTask(userID uuid.UUID, taskID uuid.UUID) (*models.Task, error) {
+	task, err := s.taskRepo.FindByID(taskID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: taskID %s", apperr.ErrNotFound, taskID)
+		}
+		return nil, fmt.Errorf("reminderService.findOwnedTask: %w", err)
+	}
+	if task.UserID != userID {
+		return nil, fmt.Errorf("%w: user %s has no permission for task %s", apperr.ErrForbidden, userID, taskID)
+	}
+	return task, nil
+}
+
+// planReminders は期限と offset からリマインダーを作成します (DBに依存しない純粋関数)
+// 送信時刻が既に過ぎているものは送信済みとして作成しますが、期限がまだ先なら
+// 過ぎたもののうち最も新しい1件だけは未送信にして、すぐに1回通知します
+// (例: 期限の30分前に作成したタスクの「1時間前」リマインダー)
+// fired に含まれる offset は送信済みなので、送信時刻が過去のままなら再送しません
+func planReminders(due time.Time, offsets []int, fired map[int]bool, now time.Time) []models.TaskReminder {
+	reminders := make([]models.TaskReminder, 0, len(offsets))
+	latestMissed := -1
+	for _, offset := range sortedOffsets(offsets) {
+		r := models.TaskReminder{
+			OffsetMinutes: offset,
+			RemindAt:      due.Add(-time.Duration(offset) * time.Minute),
+		}
+		if !r.RemindAt.After(now) {
+			firedAt := now
+			r.FiredAt = &firedAt
+			latestMissed = len(reminders)
+		}
+		reminders = append(reminders, r)
+	}
+
+	if latestMissed >= 0 && due.After(now) && !fired[reminders[latestMissed].OffsetMinutes] {
+		reminders[latestMissed].FiredAt = nil
+	}
+	return reminders
+}
+
+// normalizeReminderOffsets は offset を検証し、重複を除いて送信順 (期限から遠い順) に並べます
+func normalizeReminderOffsets(offsets []int) ([]int, error) {
+	if len(offsets) > models.MaxRemindersPerTask {
+		return nil, fmt.Errorf("%w: at most %d reminders are allowed", apperr.ErrValidation, models.MaxRemindersPerTask)
+	}
+	for _, offset := range offsets {
+		if offset < 0 || offset > models.MaxReminderOffset {
+			return nil, fmt.Errorf("%w: reminder offset must be between 0 and %d minutes", apperr.ErrValidation, models.MaxReminderOffset)
+		}
+	}
+	return sortedOffsets(offsets), nil
+}
+
+func sortedOffsets(offsets []int) []int {
+	sorted := slices.Clone(offsets)
+	slices.Sort(sorted)
+	sorted = slices.Compact(sorted)
+	slices.Reverse(sorted)
+	if sorted == nil {
+		sorted = []int{}
+	}
+	return sorted
+}
+
+func firedOffsets(reminders []models.TaskReminder) map[int]bool {
+	fired := make(map[int]bool, len(reminders))
+	for _, r := range reminders {
+		if r.FiredAt != nil {
+			fired[r.OffsetMinutes] = true
+		}
+	}
+	return fired
+}
+
+// reminderMessage はリマインダーの通知メッセージを作成します
+func reminderMessage(task *models.Task, offset int) string {
+	due := task.DueDate.In(utils.JST).Format("01/02 15:04")
+	if offset == 0 {
+		return fmt.Sprintf("タスク「%s」の期限になりました（期限: %s）", task.Title, due)
+	}
+	return fmt.Sprintf("タスク「%s」の期限が近づいています（期限: %s、%s前）", task.Title, due, formatReminderOffset(offset))
+}
+
+// formatReminderOffset は offset を「1日」「1時間30分」のような表記にします
+func formatReminderOffset(offset int) string {
+	days, hours, minutes := offset/(24*60), offset/60%24, offset%60
+	var s string
+	if days > 0 {
+		s += fmt.Sprintf("%d日", days)
+	}
+	if hours > 0 {
+		s += fmt.Sprintf("%d時間", hours)
+	}
+	if minutes > 0 {
+		s += fmt.Sprintf("%d分", minutes)
+	}
+	return s
+}
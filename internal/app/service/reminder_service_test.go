@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"my-portfolio-2025/internal/app/models"
+	"my-portfolio-2025/internal/testutils/mock"
+	"my-portfolio-2025/pkg/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	mockPkg "github.com/stretchr/testify/mock"
+)
+
+func TestPlanReminders(t *testing.T) {
+	now := time.Date(2026, 5, 12, 9, 0, 0, 0, utils.JST)
+
+	tests := []struct {
+		name    string
+		due     time.Time
+		offsets []int
+		fired   map[int]bool
+		pending []int // 未送信になるべき offset (送信順)
+	}{
+		{
+			name:    "全て未来なら全て未送信",
+			due:     now.Add(48 * time.Hour),
+			offsets: []int{0, 60, 1440},
+			pending: []int{1440, 60, 0},
+		},
+		{
+			name:    "過ぎたものは最も新しい1件だけすぐに送る",
+			due:     now.Add(30 * time.Minute),
+			offsets: []int{1440, 60, 0},
+			pending: []int{60, 0},
+		},
+		{
+			name:    "送信済みのものは送信時刻が過去のままなら再送しない",
+			due:     now.Add(30 * time.Minute),
+			offsets: []int{1440, 60, 0},
+			fired:   map[int]bool{60: true},
+			pending: []int{0},
+		},
+		{
+			name:    "期限を先に延ばすと送信済みのものも再び未送信になる",
+			due:     now.Add(72 * time.Hour),
+			offsets: []int{1440, 60},
+			fired:   map[int]bool{1440: true, 60: true},
+			pending: []int{1440, 60},
+		},
+		{
+			name:    "期限を過ぎたタスクには送らない",
+			due:     now.Add(-time.Hour),
+			offsets: []int{60, 0},
+			pending: []int{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reminders := planReminders(tt.due, tt.offsets, tt.fired, now)
+
+			assert.Len(t, reminders, len(tt.offsets))
+			pending := []int{}
+			for _, r := range reminders {
+				assert.True(t, r.RemindAt.Equal(tt.due.Add(-time.Duration(r.OffsetMinutes)*time.Minute)))
+				if r.FiredAt == nil {
+					pending = append(pending, r.OffsetMinutes)
+				}
+			}
+			assert.Equal(t, tt.pending, pending)
+		})
+	}
+}
+
+func TestReminderPublishTaskEvent_DueDateChangeKeepsOffsets(t *testing.T) {
+	userID := uuid.New()
+	task := &models.Task{ID: uuid.New(), UserID: userID, Title: "資料作成", DueDate: utils.NowJST().Add(72 * time.Hour)}
+
+	repo := new(mock.MockTaskReminderRepository)
+	repo.On("FindByTaskID", mockPkg.Anything, task.ID).Return([]models.TaskReminder{
+		{TaskID: task.ID, OffsetMinutes: 1440, FiredAt: ptrTime(utils.NowJST())},
+		{TaskID: task.ID, OffsetMinutes: 15},
+	}, nil)
+	repo.On("ReplaceForTask", mockPkg.Anything, task.ID, mockPkg.MatchedBy(func(reminders []models.TaskReminder) bool {
+		return len(reminders) == 2 &&
+			reminders[0].OffsetMinutes == 1440 && reminders[0].FiredAt == nil &&
+			reminders[1].OffsetMinutes == 15 && reminders[1].UserID == userID
+	})).Return(nil).Once()
+
+	svc := NewReminderService(repo, nil, nil)
+	err := svc.PublishTaskEvent(context.Background(), &models.TaskEvent{
+		Type:          models.WSEventTaskUpdated,
+		TaskID:        task.ID,
+		UserID:        userID,
+		ChangedFields: []string{"due_date"},
+		Task:          task,
+	})
+
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestReminderMessage(t *testing.T) {
+	task := &models.Task{Title: "資料作成", DueDate: time.Date(2026, 5, 13, 18, 0, 0, 0, utils.JST)}
+
+	assert.Equal(t, "タスク「資料作成」の期限が近づいています（期限: 05/13 18:00、1日2時間前）", reminderMessage(task, 26*60))
+	assert.Equal(t, "タスク「資料作成」の期限になりました（期限: 05/13 18:00）", reminderMessage(task, 0))
+}
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"context"
+	"my-portfolio-2025/internal/app/models"
+)
+
+// ScheduledJob は cron 式で定期的に実行するジョブです
+type ScheduledJob struct {
+	// Name はジョブの名前 (管理 API・実行履歴・リースで使う)
+	Name string
+	// Schedule は cron 式 (分 時 日 月 曜日、JST)。例: "0 * * * *" (毎時0分)
+	Schedule    string
+	Description string
+	// MissedRuns は実行時刻を取り逃した場合の方針 (未指定は MissedRunSkip)
+	MissedRuns models.MissedRunPolicy
+	// Run はジョブの処理です。ctx は停止時・リースを失ったときにキャンセルされるため、その時点で処理を止めて戻ってください
+	Run func(ctx context.Context) error
+}
+
+// SchedulerService は定期ジョブを実行します
+// ワーカーの全レプリカで Run を実行し、ジョブごとのリースと実行履歴で各実行時刻を1回だけ実行します (docs/scheduled-jobs.md)
+type SchedulerService interface {
+	// Register はジョブを登録します。Run を呼ぶ前に登録してください
+	Register(job ScheduledJob) error
+
+	// Run は ctx がキャンセルされるまで、実行時刻を迎えたジョブと手動実行の依頼を実行します
+	// 停止時は実行中のジョブが戻るのを待ってから戻る
+	Run(ctx context.Context)
+
+	// Jobs は登録されているジョブと次の実行時刻・最後の実行を返します
+	Jobs(ctx context.Context) ([]models.ScheduledJobInfo, error)
+
+	// Runs はジョブの実行履歴を新しい順に最大 limit 件返します
+	Runs(ctx context.Context, name string, limit int) ([]models.ScheduledJobRun, error)
+
+	// Trigger はジョブの手動実行を依頼します。実行はワーカーの Run が行う
+	Trigger(ctx context.Context, name string) (*models.ScheduledJobRun, error)
+}
@@ -0,0 +1,427 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"my-portfolio-2025/internal/app/apperr"
+	"my-portfolio-2025/internal/app/models"
+	"my-portfolio-2025/internal/app/repository"
+	"my-portfolio-2025/pkg/cron"
+	"my-portfolio-2025/pkg/utils"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// 実行時刻を迎えたジョブと手動実行の依頼を確認する間隔
+	schedulerPollInterval = 5 * time.Second
+	// MissedRunSkip のジョブでも、実行時刻からこの時間以内なら遅れて実行する (確認の間隔やリースの待ちで遅れた場合)
+	missedRunGrace = time.Minute
+	// MissedRunAll のジョブで取り逃した実行時刻を実行する上限。これより古い実行時刻は実行しない
+	maxCatchUpRuns = 24
+	// 実行履歴を残す期間
+	scheduledJobRunRetention = 30 * 24 * time.Hour
+	// Runs の既定・最大の件数
+	defaultScheduledJobRunsLimit = 20
+	maxScheduledJobRunsLimit     = 100
+)
+
+// PurgeJobRunsJobName は古い実行履歴を削除する組み込みのジョブの名前です
+const PurgeJobRunsJobName = "purge_job_runs"
+
+// ScheduledJobLeaseName はジョブ name の実行中に保持するリースの名前です
+func ScheduledJobLeaseName(name string) string {
+	return "cron:" + name
+}
+
+type scheduledJob struct {
+	ScheduledJob
+	schedule *cron.Schedule
+	// election は実行中に保持するジョブごとのリース。同じジョブを複数のレプリカで同時に実行しない
+	election *LeaderElection
+	// since は実行履歴が無いジョブの取り逃しを数え始める時刻 (登録した時刻)
+	since time.Time
+	// running はこのプロセスでジョブを処理中かどうか (確認の間隔より長く実行しても重ねて処理しない)
+	running atomic.Bool
+}
+
+type schedulerService struct {
+	runs     repository.ScheduledJobRunRepository
+	newLease func(name string) LeaderLease
+	ttl      time.Duration
+	interval time.Duration
+	// now はテストで時刻を差し替えるためのもの
+	now func() time.Time
+
+	mu     sync.Mutex
+	jobs   []*scheduledJob
+	byName map[string]*scheduledJob
+}
+
+// NewSchedulerService は SchedulerService を作成します
+// newLease はリース名からジョブごとのリースを作成する関数、ttl はリースの期限 (0 以下は DefaultLeaderLeaseTTL)。
+// 古い実行履歴を削除するジョブ (PurgeJobRunsJobName) は組み込みで登録する
+func NewSchedulerService(runs repository.ScheduledJobRunRepository, newLease func(name string) LeaderLease, ttl time.Duration) SchedulerService {
+	s := &schedulerService{
+		runs:     runs,
+		newLease: newLease,
+		ttl:      ttl,
+		interval: schedulerPollInterval,
+		now:      utils.NowJST,
+		byName:   make(map[string]*scheduledJob),
+	}
+	s.Register(ScheduledJob{
+		Name:        PurgeJobRunsJobName,
+		Schedule:    "0 4 * * *",
+		Description: "30日より前に終了した定期ジョブの実行履歴を削除する",
+		MissedRuns:  models.MissedRunOnce,
+		Run:         s.purgeRuns,
+	})
+	return s
+}
+
+// Register はジョブを登録します
+func (s *schedulerService) Register(job ScheduledJob) error {
+	if job.Name == "" || job.Run == nil {
+		return fmt.Errorf("schedulerService.Register: %w: name and run are required", apperr.ErrValidation)
+	}
+	schedule, err := cron.Parse(job.Schedule)
+	if err != nil {
+		return fmt.Errorf("schedulerService.Register (%s): %w: %v", job.Name, apperr.ErrValidation, err)
+	}
+	switch job.MissedRuns {
+	case "":
+		job.MissedRuns = models.MissedRunSkip
+	case models.MissedRunSkip, models.MissedRunOnce, models.MissedRunAll:
+	default:
+		return fmt.Errorf("schedulerService.Register (%s): %w: unknown missed-run policy %q", job.Name, apperr.ErrValidation, job.MissedRuns)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.byName[job.Name]; ok {
+		return fmt.Errorf("schedulerService.Register: %w: job %q is already registered", apperr.ErrValidation, job.Name)
+	}
+	registered := &scheduledJob{
+		ScheduledJob: job,
+		schedule:     schedule,
+		election:     NewLeaderElection(s.newLease(ScheduledJobLeaseName(job.Name)), s.ttl),
+		since:        s.now(),
+	}
+	s.jobs = append(s.jobs, registered)
+	s.byName[job.Name] = registered
+	return nil
+}
+
+// registered は登録されているジョブを登録順に返します
+func (s *schedulerService) registered() []*scheduledJob {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.jobs)
+}
+
+// lookup は名前のジョブを返します。登録されていない場合は apperr.ErrNotFound
+func (s *schedulerService) lookup(name string) (*scheduledJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.byName[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: scheduled job %q", apperr.ErrNotFound, name)
+	}
+	return job, nil
+}
+
+// Run は ctx がキャンセルされるまで、interval ごとに各ジョブを確認して実行します
+// ジョブは別々の goroutine で実行するため、長く実行するジョブが他のジョブを遅らせることはありません
+func (s *schedulerService) Run(ctx context.Context) {
+	jobs := s.registered()
+	slog.Info("Scheduler started", "jobs", len(jobs))
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	var wg sync.WaitGroup
+	for {
+		for _, job := range jobs {
+			if !job.running.CompareAndSwap(false, true) {
+				continue
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer job.running.Store(false)
+				s.process(ctx, job)
+			}()
+		}
+
+		select {
+		case <-ctx.Done():
+			slog.Info("Scheduler shutting down; waiting for running jobs")
+			wg.Wait()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runPlan は1回の確認で実行する内容です
+type runPlan struct {
+	// slots は実行する cron 式の実行時刻 (古い順)
+	slots []time.Time
+	// skip は実行せずにスキップとして記録する実行時刻 (取り逃しの判定の起点を進めるため)
+	skip *time.Time
+	// missed は取り逃して実行しない実行時刻の数
+	missed int
+}
+
+// planRuns は from より後で now までに迎えた実行時刻から、取り逃し時の方針に従って実行する内容を決めます
+func planRuns(policy models.MissedRunPolicy, schedule *cron.Schedule, from, now time.Time) runPlan {
+	var due []time.Time
+	dropped := 0
+	for t := schedule.Next(from); !t.IsZero() && !t.After(now); t = schedule.Next(t) {
+		due = append(due, t)
+		if len(due) > maxCatchUpRuns {
+			due = due[1:]
+			dropped++
+		}
+	}
+	if len(due) == 0 {
+		return runPlan{}
+	}
+
+	latest := due[len(due)-1]
+	switch policy {
+	case models.MissedRunAll:
+		return runPlan{slots: due, missed: dropped}
+	case models.MissedRunOnce:
+		return runPlan{slots: []time.Time{latest}, missed: dropped + len(due) - 1}
+	default:
+		if now.Sub(latest) <= missedRunGrace {
+			return runPlan{slots: []time.Time{latest}, missed: dropped + len(due) - 1}
+		}
+		return runPlan{skip: &latest, missed: dropped + len(due)}
+	}
+}
+
+// process は実行時刻を迎えていれば、または手動実行の依頼があれば、ジョブのリースを取得して実行します
+// 他のレプリカがリースを保持している (実行中の) 場合は何もせず、次の確認で改めて判定する
+func (s *schedulerService) process(ctx context.Context, job *scheduledJob) {
+	now := s.now()
+	last, err := s.runs.LastScheduledAt(ctx, job.Name)
+	if err != nil {
+		if ctx.Err() == nil {
+			slog.Error("Failed to read last scheduled run", "job", job.Name, "error", err)
+		}
+		return
+	}
+	from := job.since
+	if last != nil {
+		// cron 式は now のタイムゾーン (JST) で解釈する
+		from = last.In(now.Location())
+	}
+	plan := planRuns(job.MissedRuns, job.schedule, from, now)
+
+	pending, err := s.runs.HasPending(ctx, job.Name)
+	if err != nil {
+		if ctx.Err() == nil {
+			slog.Error("Failed to check manual runs", "job", job.Name, "error", err)
+		}
+		return
+	}
+	if len(plan.slots) == 0 && plan.skip == nil && !pending {
+		return
+	}
+
+	acquired, err := job.election.RunOnce(ctx, func(ctx context.Context) {
+		s.execute(ctx, job, plan)
+	})
+	if err != nil && ctx.Err() == nil {
+		slog.Error("Failed to acquire scheduled job lease", "job", job.Name, "error", err)
+	} else if !acquired {
+		slog.Debug("Scheduled job is running on another replica", "job", job.Name)
+	}
+}
+
+// execute はジョブのリースを保持した状態で、plan の実行時刻と手動実行の依頼を順に実行します
+func (s *schedulerService) execute(ctx context.Context, job *scheduledJob, plan runPlan) {
+	holder := job.election.Status().Identity
+	now := s.now()
+
+	// リースを取得できた時点で他に実行中のワーカーはいないため、実行中のまま残っている記録は中断したもの
+	if n, err := s.runs.FailRunning(ctx, job.Name, "interrupted: the worker stopped before the run finished", now); err != nil {
+		slog.Error("Failed to mark interrupted runs", "job", job.Name, "error", err)
+	} else if n > 0 {
+		slog.Warn("Marked interrupted scheduled job runs as failed", "job", job.Name, "count", n)
+	}
+
+	if plan.missed > 0 {
+		slog.Warn("Scheduled job missed runs", "job", job.Name, "missed", plan.missed, "policy", job.MissedRuns)
+	}
+	if plan.skip != nil {
+		skipped := &models.ScheduledJobRun{
+			ID:          uuid.New(),
+			Job:         job.Name,
+			ScheduledAt: plan.skip,
+			Trigger:     models.ScheduledJobTriggerSchedule,
+			Status:      models.ScheduledJobSkipped,
+			Holder:      holder,
+			Error:       fmt.Sprintf("missed %d run(s); policy %s", plan.missed, job.MissedRuns),
+			CreatedAt:   now,
+			FinishedAt:  &now,
+		}
+		if _, err := s.runs.Claim(ctx, skipped); err != nil {
+			slog.Error("Failed to record skipped run", "job", job.Name, "error", err)
+		}
+	}
+
+	for _, slot := range plan.slots {
+		if ctx.Err() != nil {
+			return
+		}
+		startedAt := s.now()
+		run := &models.ScheduledJobRun{
+			ID:          uuid.New(),
+			Job:         job.Name,
+			ScheduledAt: &slot,
+			Trigger:     models.ScheduledJobTriggerSchedule,
+			Status:      models.ScheduledJobRunning,
+			Holder:      holder,
+			CreatedAt:   startedAt,
+			StartedAt:   &startedAt,
+		}
+		claimed, err := s.runs.Claim(ctx, run)
+		if err != nil {
+			slog.Error("Failed to record scheduled run", "job", job.Name, "scheduledAt", slot, "error", err)
+			return
+		}
+		if !claimed {
+			// 他のレプリカが先にこの実行時刻を実行した
+			continue
+		}
+		s.executeRun(ctx, job, run)
+	}
+
+	for ctx.Err() == nil {
+		run, err := s.runs.StartPending(ctx, job.Name, holder, s.now())
+		if err != nil {
+			slog.Error("Failed to start manual run", "job", job.Name, "error", err)
+			return
+		}
+		if run == nil {
+			return
+		}
+		s.executeRun(ctx, job, run)
+	}
+}
+
+// executeRun はジョブを実行して結果を実行履歴に記録します
+func (s *schedulerService) executeRun(ctx context.Context, job *scheduledJob, run *models.ScheduledJobRun) {
+	slog.Info("Scheduled job started", "job", job.Name, "runID", run.ID, "trigger", run.Trigger, "scheduledAt", run.ScheduledAt)
+	started := time.Now()
+
+	status, reason := models.ScheduledJobSucceeded, ""
+	if err := callScheduledJob(ctx, job); err != nil {
+		status, reason = models.ScheduledJobFailed, err.Error()
+		slog.Error("Scheduled job failed", "job", job.Name, "runID", run.ID, "duration", time.Since(started), "error", err)
+	} else {
+		slog.Info("Scheduled job finished", "job", job.Name, "runID", run.ID, "duration", time.Since(started))
+	}
+
+	// 停止・リースの喪失で ctx がキャンセルされていても結果は記録する
+	if err := s.runs.Finish(context.WithoutCancel(ctx), run.ID, status, reason, s.now()); err != nil {
+		slog.Error("Failed to record scheduled job result", "job", job.Name, "runID", run.ID, "error", err)
+	}
+}
+
+// callScheduledJob はジョブを実行します。ジョブの panic はワーカーを止めないよう失敗として返す
+func callScheduledJob(ctx context.Context, job *scheduledJob) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return job.Run(ctx)
+}
+
+// Jobs は登録されているジョブと次の実行時刻・最後の実行を返します
+func (s *schedulerService) Jobs(ctx context.Context) ([]models.ScheduledJobInfo, error) {
+	latest, err := s.runs.LatestByJob(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("schedulerService.Jobs: %w", err)
+	}
+
+	now := s.now()
+	jobs := s.registered()
+	infos := make([]models.ScheduledJobInfo, 0, len(jobs))
+	for _, job := range jobs {
+		info := models.ScheduledJobInfo{
+			Name:        job.Name,
+			Schedule:    job.schedule.String(),
+			Description: job.Description,
+			MissedRuns:  job.MissedRuns,
+		}
+		if next := job.schedule.Next(now); !next.IsZero() {
+			info.NextRunAt = &next
+		}
+		if run, ok := latest[job.Name]; ok {
+			info.LastRun = &run
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+// Runs はジョブの実行履歴を新しい順に返します
+func (s *schedulerService) Runs(ctx context.Context, name string, limit int) ([]models.ScheduledJobRun, error) {
+	if _, err := s.lookup(name); err != nil {
+		return nil, fmt.Errorf("schedulerService.Runs: %w", err)
+	}
+	if limit <= 0 {
+		limit = defaultScheduledJobRunsLimit
+	}
+	limit = min(limit, maxScheduledJobRunsLimit)
+
+	runs, err := s.runs.List(ctx, name, limit)
+	if err != nil {
+		return nil, fmt.Errorf("schedulerService.Runs: %w", err)
+	}
+	return runs, nil
+}
+
+// Trigger はジョブの手動実行を依頼します
+// 依頼は実行履歴に pending として記録し、ワーカーの Run が次の確認で取り出して実行する
+func (s *schedulerService) Trigger(ctx context.Context, name string) (*models.ScheduledJobRun, error) {
+	if _, err := s.lookup(name); err != nil {
+		return nil, fmt.Errorf("schedulerService.Trigger: %w", err)
+	}
+
+	run := &models.ScheduledJobRun{
+		ID:        uuid.New(),
+		Job:       name,
+		Trigger:   models.ScheduledJobTriggerManual,
+		Status:    models.ScheduledJobPending,
+		CreatedAt: s.now(),
+	}
+	if err := s.runs.Create(ctx, run); err != nil {
+		return nil, fmt.Errorf("schedulerService.Trigger: %w", err)
+	}
+	slog.Info("Scheduled job triggered manually", "job", name, "runID", run.ID)
+	return run, nil
+}
+
+// purgeRuns は保持期間を過ぎた実行履歴を削除します
+func (s *schedulerService) purgeRuns(ctx context.Context) error {
+	deleted, err := s.runs.DeleteFinishedBefore(ctx, s.now().Add(-scheduledJobRunRetention))
+	if err != nil {
+		return err
+	}
+	if deleted > 0 {
+		slog.Info("Purged scheduled job runs", "count", deleted)
+	}
+	return nil
+}
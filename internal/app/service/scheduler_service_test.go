@@ -0,0 +1,356 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"my-portfolio-2025/internal/app/apperr"
+	"my-portfolio-2025/internal/app/models"
+	"my-portfolio-2025/pkg/cron"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeScheduledJobRunRepo はメモリ上の実行履歴です ((job, scheduled_at) の一意制約を再現する)
+type fakeScheduledJobRunRepo struct {
+	mu   sync.Mutex
+	runs []*models.ScheduledJobRun
+}
+
+func (f *fakeScheduledJobRunRepo) Claim(ctx context.Context, run *models.ScheduledJobRun) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, r := range f.runs {
+		if r.Job == run.Job && r.ScheduledAt != nil && run.ScheduledAt != nil && r.ScheduledAt.Equal(*run.ScheduledAt) {
+			return false, nil
+		}
+	}
+	copied := *run
+	f.runs = append(f.runs, &copied)
+	return true, nil
+}
+
+func (f *fakeScheduledJobRunRepo) Create(ctx context.Context, run *models.ScheduledJobRun) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	copied := *run
+	f.runs = append(f.runs, &copied)
+	return nil
+}
+
+func (f *fakeScheduledJobRunRepo) LastScheduledAt(ctx context.Context, job string) (*time.Time, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var last *time.Time
+	for _, r := range f.runs {
+		if r.Job == job && r.ScheduledAt != nil && (last == nil || r.ScheduledAt.After(*last)) {
+			last = r.ScheduledAt
+		}
+	}
+	return last, nil
+}
+
+func (f *fakeScheduledJobRunRepo) HasPending(ctx context.Context, job string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, r := range f.runs {
+		if r.Job == job && r.Status == models.ScheduledJobPending {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (f *fakeScheduledJobRunRepo) StartPending(ctx context.Context, job string, holder string, now time.Time) (*models.ScheduledJobRun, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, r := range f.runs {
+		if r.Job == job && r.Status == models.ScheduledJobPending {
+			r.Status, r.Holder, r.StartedAt = models.ScheduledJobRunning, holder, &now
+			copied := *r
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (f *fakeScheduledJobRunRepo) Finish(ctx context.Context, id uuid.UUID, status string, reason string, finishedAt time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, r := range f.runs {
+		if r.ID == id {
+			r.Status, r.Error, r.FinishedAt = status, reason, &finishedAt
+			return nil
+		}
+	}
+	return errors.New("run not found")
+}
+
+func (f *fakeScheduledJobRunRepo) FailRunning(ctx context.Context, job string, reason string, now time.Time) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var n int64
+	for _, r := range f.runs {
+		if r.Job == job && r.Status == models.ScheduledJobRunning {
+			r.Status, r.Error, r.FinishedAt = models.ScheduledJobFailed, reason, &now
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (f *fakeScheduledJobRunRepo) LatestByJob(ctx context.Context) (map[string]models.ScheduledJobRun, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	latest := make(map[string]models.ScheduledJobRun)
+	for _, r := range f.runs {
+		latest[r.Job] = *r
+	}
+	return latest, nil
+}
+
+func (f *fakeScheduledJobRunRepo) List(ctx context.Context, job string, limit int) ([]models.ScheduledJobRun, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var runs []models.ScheduledJobRun
+	for _, r := range slices.Backward(f.runs) {
+		if r.Job == job && len(runs) < limit {
+			runs = append(runs, *r)
+		}
+	}
+	return runs, nil
+}
+
+func (f *fakeScheduledJobRunRepo) DeleteFinishedBefore(ctx context.Context, before time.Time) (int64, error) {
+	return 0, nil
+}
+
+// byStatus は job の記録を状態ごとに数えます
+func (f *fakeScheduledJobRunRepo) byStatus(job string) map[string]int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	counts := make(map[string]int)
+	for _, r := range f.runs {
+		if r.Job == job {
+			counts[r.Status]++
+		}
+	}
+	return counts
+}
+
+// fakeLeases はリース名ごとの fakeLease です (複数のレプリカで共有する)
+type fakeLeases struct {
+	mu     sync.Mutex
+	leases map[string]*fakeLease
+}
+
+func (f *fakeLeases) get(name string) LeaderLease {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.leases == nil {
+		f.leases = make(map[string]*fakeLease)
+	}
+	if f.leases[name] == nil {
+		f.leases[name] = &fakeLease{}
+	}
+	return f.leases[name]
+}
+
+// newTestScheduler は時刻を now に固定した schedulerService を作成します
+func newTestScheduler(t *testing.T, repo *fakeScheduledJobRunRepo, leases *fakeLeases, now time.Time) *schedulerService {
+	t.Helper()
+	s := NewSchedulerService(repo, leases.get, time.Second).(*schedulerService)
+	s.now = func() time.Time { return now }
+	for _, job := range s.jobs {
+		job.since = now.Add(-time.Hour)
+	}
+	return s
+}
+
+func jst(t *testing.T, value string) time.Time {
+	t.Helper()
+	v, err := time.ParseInLocation("2006-01-02 15:04:05", value, time.FixedZone("JST", 9*60*60))
+	require.NoError(t, err)
+	return v
+}
+
+func TestPlanRuns(t *testing.T) {
+	hourly, err := cron.Parse("0 * * * *")
+	require.NoError(t, err)
+
+	from := jst(t, "2026-03-10 00:00:00")
+	tests := []struct {
+		name   string
+		policy models.MissedRunPolicy
+		now    string
+		want   []string
+		skip   string
+		missed int
+	}{
+		{"実行時刻前", models.MissedRunSkip, "2026-03-10 00:59:00", nil, "", 0},
+		{"skip: 猶予内なら実行する", models.MissedRunSkip, "2026-03-10 01:00:30", []string{"2026-03-10 01:00:00"}, "", 0},
+		{"skip: 猶予を過ぎたらスキップとして記録する", models.MissedRunSkip, "2026-03-10 03:30:00", nil, "2026-03-10 03:00:00", 3},
+		{"once: 最後の実行時刻を1回だけ実行する", models.MissedRunOnce, "2026-03-10 03:30:00", []string{"2026-03-10 03:00:00"}, "", 2},
+		{"all: 取り逃した実行時刻をすべて実行する", models.MissedRunAll, "2026-03-10 03:30:00", []string{"2026-03-10 01:00:00", "2026-03-10 02:00:00", "2026-03-10 03:00:00"}, "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := planRuns(tt.policy, hourly, from, jst(t, tt.now))
+
+			var want []time.Time
+			for _, v := range tt.want {
+				want = append(want, jst(t, v))
+			}
+			assert.Equal(t, want, plan.slots)
+			if tt.skip == "" {
+				assert.Nil(t, plan.skip)
+			} else if assert.NotNil(t, plan.skip) {
+				assert.Equal(t, jst(t, tt.skip), *plan.skip)
+			}
+			assert.Equal(t, tt.missed, plan.missed)
+		})
+	}
+}
+
+func TestPlanRuns_CapsCatchUp(t *testing.T) {
+	hourly, err := cron.Parse("0 * * * *")
+	require.NoError(t, err)
+
+	from := jst(t, "2026-03-10 00:00:00")
+	plan := planRuns(models.MissedRunAll, hourly, from, from.Add(30*24*time.Hour))
+	require.Len(t, plan.slots, maxCatchUpRuns)
+	assert.Equal(t, from.Add(30*24*time.Hour), plan.slots[maxCatchUpRuns-1], "新しい実行時刻を残す")
+	assert.Equal(t, 30*24-maxCatchUpRuns, plan.missed)
+}
+
+func TestScheduler_RunsEachSlotOnceAcrossReplicas(t *testing.T) {
+	repo := &fakeScheduledJobRunRepo{}
+	leases := &fakeLeases{}
+	now := jst(t, "2026-03-10 12:00:10")
+
+	var mu sync.Mutex
+	calls := 0
+	job := ScheduledJob{Name: "report", Schedule: "0 12 * * *", Run: func(ctx context.Context) error {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		return nil
+	}}
+
+	replicas := []*schedulerService{newTestScheduler(t, repo, leases, now), newTestScheduler(t, repo, leases, now)}
+	var wg sync.WaitGroup
+	for _, s := range replicas {
+		require.NoError(t, s.Register(job))
+		s.byName["report"].since = now.Add(-time.Hour)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.process(context.Background(), s.byName["report"])
+		}()
+	}
+	wg.Wait()
+
+	// 後から確認したレプリカも実行済みの実行時刻は実行しない
+	replicas[1].process(context.Background(), replicas[1].byName["report"])
+
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, map[string]int{models.ScheduledJobSucceeded: 1}, repo.byStatus("report"))
+}
+
+func TestScheduler_DoesNotRunWhileAnotherReplicaHoldsLease(t *testing.T) {
+	repo := &fakeScheduledJobRunRepo{}
+	leases := &fakeLeases{}
+	s := newTestScheduler(t, repo, leases, jst(t, "2026-03-10 12:00:10"))
+
+	calls := 0
+	require.NoError(t, s.Register(ScheduledJob{Name: "report", Schedule: "0 12 * * *", Run: func(ctx context.Context) error {
+		calls++
+		return nil
+	}}))
+	s.byName["report"].since = jst(t, "2026-03-10 11:00:00")
+
+	leases.get(ScheduledJobLeaseName("report")).(*fakeLease).set("other", nil)
+	s.process(context.Background(), s.byName["report"])
+	assert.Zero(t, calls)
+	assert.Empty(t, repo.byStatus("report"))
+
+	// リースが空いたら実行する
+	leases.get(ScheduledJobLeaseName("report")).(*fakeLease).set("", nil)
+	s.process(context.Background(), s.byName["report"])
+	assert.Equal(t, 1, calls)
+}
+
+func TestScheduler_RecordsFailuresAndInterruptedRuns(t *testing.T) {
+	repo := &fakeScheduledJobRunRepo{}
+	now := jst(t, "2026-03-10 12:00:10")
+	s := newTestScheduler(t, repo, &fakeLeases{}, now)
+
+	require.NoError(t, s.Register(ScheduledJob{Name: "report", Schedule: "0 12 * * *", Run: func(ctx context.Context) error {
+		panic("boom")
+	}}))
+	s.byName["report"].since = now.Add(-time.Hour)
+
+	// 前回の実行がクラッシュで実行中のまま残っている
+	stale := now.Add(-24 * time.Hour)
+	repo.runs = append(repo.runs, &models.ScheduledJobRun{ID: uuid.New(), Job: "report", ScheduledAt: &stale, Status: models.ScheduledJobRunning})
+
+	s.process(context.Background(), s.byName["report"])
+
+	assert.Equal(t, map[string]int{models.ScheduledJobFailed: 2}, repo.byStatus("report"))
+	runs, err := s.Runs(context.Background(), "report", 0)
+	require.NoError(t, err)
+	require.Len(t, runs, 2)
+	assert.Contains(t, runs[0].Error, "panic: boom")
+	assert.Contains(t, runs[1].Error, "interrupted")
+}
+
+func TestScheduler_TriggerRunsOnWorker(t *testing.T) {
+	repo := &fakeScheduledJobRunRepo{}
+	now := jst(t, "2026-03-10 12:30:00")
+	s := newTestScheduler(t, repo, &fakeLeases{}, now)
+
+	calls := 0
+	require.NoError(t, s.Register(ScheduledJob{Name: "report", Schedule: "0 12 * * *", Run: func(ctx context.Context) error {
+		calls++
+		return nil
+	}}))
+
+	run, err := s.Trigger(context.Background(), "report")
+	require.NoError(t, err)
+	assert.Equal(t, models.ScheduledJobPending, run.Status)
+	assert.Equal(t, models.ScheduledJobTriggerManual, run.Trigger)
+	assert.Zero(t, calls, "依頼だけを記録し、実行はワーカーが行う")
+
+	s.process(context.Background(), s.byName["report"])
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, map[string]int{models.ScheduledJobSucceeded: 1}, repo.byStatus("report"))
+
+	jobs, err := s.Jobs(context.Background())
+	require.NoError(t, err)
+	i := slices.IndexFunc(jobs, func(j models.ScheduledJobInfo) bool { return j.Name == "report" })
+	require.GreaterOrEqual(t, i, 0)
+	assert.Equal(t, models.MissedRunSkip, jobs[i].MissedRuns)
+	require.NotNil(t, jobs[i].NextRunAt)
+	assert.Equal(t, jst(t, "2026-03-11 12:00:00"), *jobs[i].NextRunAt)
+	require.NotNil(t, jobs[i].LastRun)
+	assert.Equal(t, run.ID, jobs[i].LastRun.ID)
+
+	_, err = s.Trigger(context.Background(), "unknown")
+	assert.ErrorIs(t, err, apperr.ErrNotFound)
+}
+
+func TestScheduler_RegisterValidates(t *testing.T) {
+	s := newTestScheduler(t, &fakeScheduledJobRunRepo{}, &fakeLeases{}, time.Now())
+	noop := func(ctx context.Context) error { return nil }
+
+	assert.Error(t, s.Register(ScheduledJob{Name: "bad", Schedule: "every hour", Run: noop}))
+	assert.Error(t, s.Register(ScheduledJob{Name: "bad", Schedule: "@hourly", MissedRuns: "later", Run: noop}))
+	assert.Error(t, s.Register(ScheduledJob{Name: PurgeJobRunsJobName, Schedule: "@hourly", Run: noop}), "同じ名前は登録できない")
+	assert.NoError(t, s.Register(ScheduledJob{Name: "ok", Schedule: "@hourly", Run: noop}))
+}
@@ -0,0 +1,18 @@
+package service
+
+import (
+	"context"
+	"my-portfolio-2025/internal/app/models"
+
+	"github.com/google/uuid"
+)
+
+// SyncService はオフラインファーストなクライアント向けの差分同期を定義します
+type SyncService interface {
+	// Pull は since トークン以降の変更（削除の墓標を含む）と次回用のトークンを返します
+	// トークンが空の場合は全件を返します
+	Pull(ctx context.Context, userID uuid.UUID, sinceToken string) (*models.SyncPullResponse, error)
+
+	// Push はオフライン中の変更をリクエスト順に適用し、1件ごとの結果を返します
+	Push(ctx context.Context, userID uuid.UUID, req *models.SyncPushRequest) (*models.SyncPushResponse, error)
+}
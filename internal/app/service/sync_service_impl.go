@@ -0,0 +1,307 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"my-portfolio-2025/internal/app/apperr"
+	"my-portfolio-2025/internal/app/models"
+	"my-portfolio-2025/internal/app/repository"
+	"my-portfolio-2025/pkg/utils"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// syncSafetyWindow は次回トークンを意図的に巻き戻す幅です
+// 取得中にコミットされた書き込みを取りこぼさないよう、少し重複して返します
+// (クライアントは version で重複を判別できます)
+const syncSafetyWindow = 5 * time.Second
+
+type syncServiceImpl struct {
+	taskRepo repository.TaskRepository
+	notiRepo repository.NotificationRepository
+	events   TaskEventPublisher
+	outbox   *Outbox
+}
+
+// NewSyncService は SyncService の新しいインスタンスを作成します
+func NewSyncService(taskRepo repository.TaskRepository, notiRepo repository.NotificationRepository, events TaskEventPublisher, outbox *Outbox) SyncService {
+	return &syncServiceImpl{taskRepo: taskRepo, notiRepo: notiRepo, events: events, outbox: outbox}
+}
+
+// encodeSyncToken は時刻を不透明なトークンに変換します
+// tasks.updated_at はタイムゾーンなしの timestamp 列に JST で保存されるため、トークンも JST で表します
+func encodeSyncToken(t time.Time) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(t.In(utils.JST).Format(time.RFC3339Nano)))
+}
+
+// decodeSyncToken はトークンを時刻に戻します。空文字はゼロ値（全件）として扱います
+func decodeSyncToken(token string) (time.Time, error) {
+	if token == "" {
+		return time.Time{}, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: invalid sync token", apperr.ErrValidation)
+	}
+	t, err := time.Parse(time.RFC3339Nano, string(raw))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: invalid sync token", apperr.ErrValidation)
+	}
+	// UTC で発行された旧形式のトークンも同じ時刻として扱う
+	return t.In(utils.JST), nil
+}
+
+// Pull は since 以降の差分を返します
+func (s *syncServiceImpl) Pull(ctx context.Context, userID uuid.UUID, sinceToken string) (*models.SyncPullResponse, error) {
+	since, err := decodeSyncToken(sinceToken)
+	if err != nil {
+		return nil, err
+	}
+
+	// 取得開始前の時刻を次回トークンの基準にする
+	startedAt := utils.NowJST()
+
+	tasks, err := s.taskRepo.FindChangedSince(ctx, userID, since)
+	if err != nil {
+		return nil, fmt.Errorf("syncService.Pull: %w", err)
+	}
+	notifications, err := s.notiRepo.FindChangedSince(ctx, userID, since)
+	if err != nil {
+		return nil, fmt.Errorf("syncService.Pull: %w", err)
+	}
+
+	resp := &models.SyncPullResponse{
+		Tasks:         []models.Task{},
+		Notifications: []models.Notification{},
+		Tombstones:    []models.SyncTombstone{},
+		NextToken:     encodeSyncToken(startedAt.Add(-syncSafetyWindow)),
+	}
+
+	for _, task := range tasks {
+		if task.DeletedAt.Valid {
+			resp.Tombstones = append(resp.Tombstones, models.SyncTombstone{
+				Entity:    models.SyncEntityTask,
+				ID:        task.ID,
+				Version:   task.Version,
+				DeletedAt: task.DeletedAt.Time,
+			})
+			continue
+		}
+		resp.Tasks = append(resp.Tasks, task)
+	}
+
+	for _, n := range notifications {
+		if n.DeletedAt.Valid {
+			resp.Tombstones = append(resp.Tombstones, models.SyncTombstone{
+				Entity:    models.SyncEntityNotification,
+				ID:        n.ID,
+				DeletedAt: n.DeletedAt.Time,
+			})
+			continue
+		}
+		resp.Notifications = append(resp.Notifications, n)
+	}
+
+	return resp, nil
+}
+
+// Push はオフライン変更を順番に適用します
+//
+// 競合解決ポリシー（決定的）:
+//   - base_version がサーバーの現在バージョンと一致する場合のみ適用する
+//   - 一致しない場合はサーバー側を正とし、変更は適用せず conflict とサーバーの最新状態を返す
+//   - 既に削除済みのタスクへの delete は適用済み(applied)として扱う（冪等）
+//   - 通知の mark_read は競合し得ないため常に適用する
+func (s *syncServiceImpl) Push(ctx context.Context, userID uuid.UUID, req *models.SyncPushRequest) (*models.SyncPushResponse, error) {
+	resp := &models.SyncPushResponse{Results: make([]models.SyncChangeResult, 0, len(req.Changes))}
+
+	for _, change := range req.Changes {
+		var result models.SyncChangeResult
+		var err error
+
+		switch change.Entity {
+		case models.SyncEntityTask:
+			result, err = s.applyTaskChange(ctx, userID, change)
+		case models.SyncEntityNotification:
+			result, err = s.applyNotificationChange(ctx, userID, change)
+		default:
+			result = rejected(change, "unknown entity")
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("syncService.Push: %w", err)
+		}
+		resp.Results = append(resp.Results, result)
+	}
+
+	return resp, nil
+}
+
+// applyTaskChange はタスクへの1件の変更を適用します
+func (s *syncServiceImpl) applyTaskChange(ctx context.Context, userID uuid.UUID, change models.SyncChange) (models.SyncChangeResult, error) {
+	current, err := s.findOwnedTask(userID, change.ID)
+	if err != nil && !errors.Is(err, apperr.ErrNotFound) {
+		if errors.Is(err, apperr.ErrForbidden) {
+			return rejected(change, "forbidden"), nil
+		}
+		return models.SyncChangeResult{}, err
+	}
+
+	switch change.Op {
+	case models.SyncOpCreate:
+		if current != nil {
+			return conflict(change, current), nil
+		}
+		if change.Task == nil || change.Task.Title == nil || *change.Task.Title == "" {
+			return rejected(change, "title is required"), nil
+		}
+		task := &models.Task{ID: change.ID, UserID: userID, Status: models.TaskStatusPending, Version: 1}
+		applyTaskData(task, change.Task)
+		event, err := writeTaskEvent(ctx, s.outbox, s.taskRepo, func(ctx context.Context, repo repository.TaskRepository) (bool, error) {
+			return repo.CreateIfAbsent(ctx, task)
+		}, func() *models.TaskEvent {
+			return newTaskEvent(models.WSEventTaskCreated, task, task.Version, nil)
+		})
+		if err != nil {
+			// 接続断などの一時的な失敗は Push 全体を失敗させ、クライアントに再送させる
+			return models.SyncChangeResult{}, err
+		}
+		if event == nil {
+			// 削除済みの同一IDが残っている場合 (同じIDでは二度と作成できない)
+			return rejected(change, "task id already exists"), nil
+		}
+		publishTaskEvent(s.events, event)
+		return applied(change, task.Version), nil
+
+	case models.SyncOpUpdate:
+		if current == nil {
+			return conflict(change, nil), nil
+		}
+		if change.Task == nil {
+			return rejected(change, "task data is required"), nil
+		}
+		if current.Version != change.BaseVersion {
+			return conflict(change, current), nil
+		}
+		changed := applyTaskData(current, change.Task)
+		if len(changed) == 0 {
+			// 変更の無い Push ではバージョンを進めない (他の端末で不要な競合やイベントを起こさないため)
+			return applied(change, current.Version), nil
+		}
+		current.Version = change.BaseVersion + 1
+		event, err := writeTaskEvent(ctx, s.outbox, s.taskRepo, func(ctx context.Context, repo repository.TaskRepository) (bool, error) {
+			return repo.UpdateIfVersion(ctx, current, change.BaseVersion)
+		}, func() *models.TaskEvent {
+			return newTaskEvent(models.WSEventTaskUpdated, current, current.Version, changed)
+		})
+		if err != nil {
+			return models.SyncChangeResult{}, err
+		}
+		if event == nil {
+			// 読み取り後に別端末が更新した場合
+			latest, _ := s.findOwnedTask(userID, change.ID)
+			return conflict(change, latest), nil
+		}
+		publishTaskEvent(s.events, event)
+		return applied(change, current.Version), nil
+
+	case models.SyncOpDelete:
+		if current == nil {
+			return applied(change, 0), nil
+		}
+		if current.Version != change.BaseVersion {
+			return conflict(change, current), nil
+		}
+		event, err := writeTaskEvent(ctx, s.outbox, s.taskRepo, func(ctx context.Context, repo repository.TaskRepository) (bool, error) {
+			return repo.DeleteIfVersion(ctx, change.ID, change.BaseVersion)
+		}, func() *models.TaskEvent {
+			return newTaskEvent(models.WSEventTaskDeleted, current, change.BaseVersion+1, nil)
+		})
+		if err != nil {
+			return models.SyncChangeResult{}, err
+		}
+		if event == nil {
+			latest, _ := s.findOwnedTask(userID, change.ID)
+			return conflict(change, latest), nil
+		}
+		publishTaskEvent(s.events, event)
+		return applied(change, change.BaseVersion+1), nil
+	}
+
+	return rejected(change, "unsupported op for task"), nil
+}
+
+// applyNotificationChange は通知への1件の変更を適用します
+func (s *syncServiceImpl) applyNotificationChange(ctx context.Context, userID uuid.UUID, change models.SyncChange) (models.SyncChangeResult, error) {
+	if change.Op != models.SyncOpMarkRead {
+		return rejected(change, "unsupported op for notification"), nil
+	}
+
+	if err := s.notiRepo.MarkAsRead(ctx, change.ID, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return rejected(change, "notification not found"), nil
+		}
+		return models.SyncChangeResult{}, err
+	}
+	return applied(change, 0), nil
+}
+
+// findOwnedTask はタスクを取得し、所有者チェックを行います
+// 存在しない場合は apperr.ErrNotFound、他人のタスクの場合は apperr.ErrForbidden を返します
+func (s *syncServiceImpl) findOwnedTask(userID uuid.UUID, taskID uuid.UUID) (*models.Task, error) {
+	task, err := s.taskRepo.FindByID(taskID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: taskID %s", apperr.ErrNotFound, taskID)
+		}
+		return nil, err
+	}
+	if task.UserID != userID {
+		return nil, fmt.Errorf("%w: user %s has no permission for task %s", apperr.ErrForbidden, userID, taskID)
+	}
+	return task, nil
+}
+
+// applyTaskData は編集内容をタスクに反映し、変更されたフィールド名を返します (UpdateTask と共用)
+func applyTaskData(task *models.Task, data *models.SyncTaskData) []string {
+	var changed []string
+	if data.Title != nil && *data.Title != task.Title {
+		task.Title = *data.Title
+		changed = append(changed, "title")
+	}
+	if data.Description != nil && *data.Description != task.Description {
+		task.Description = *data.Description
+		changed = append(changed, "description")
+	}
+	if data.DueDate != nil && !data.DueDate.Equal(task.DueDate) {
+		task.DueDate = *data.DueDate
+		changed = append(changed, "due_date")
+	}
+	if data.Status != nil && *data.Status != task.Status {
+		task.Status = *data.Status
+		changed = append(changed, "status")
+	}
+	return changed
+}
+
+func applied(change models.SyncChange, version int) models.SyncChangeResult {
+	return models.SyncChangeResult{Entity: change.Entity, ID: change.ID, Result: models.SyncResultApplied, Version: version}
+}
+
+func conflict(change models.SyncChange, server *models.Task) models.SyncChangeResult {
+	result := models.SyncChangeResult{Entity: change.Entity, ID: change.ID, Result: models.SyncResultConflict, Server: server}
+	if server != nil {
+		result.Version = server.Version
+	} else {
+		result.Reason = "deleted on server"
+	}
+	return result
+}
+
+func rejected(change models.SyncChange, reason string) models.SyncChangeResult {
+	return models.SyncChangeResult{Entity: change.Entity, ID: change.ID, Result: models.SyncResultRejected, Reason: reason}
+}
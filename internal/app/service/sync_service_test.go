@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"my-portfolio-2025/internal/app/models"
+	"my-portfolio-2025/internal/app/repository"
+	"my-portfolio-2025/internal/testutils/mock"
+	"my-portfolio-2025/pkg/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	mockPkg "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestSyncToken_RoundTrip(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 123456789, time.UTC)
+
+	got, err := decodeSyncToken(encodeSyncToken(now))
+	require.NoError(t, err)
+	assert.True(t, now.Equal(got))
+
+	assert.Equal(t, utils.JST, got.Location(), "保存されている時刻と同じ JST で比較する")
+
+	_, err = decodeSyncToken("not-a-token!")
+	assert.Error(t, err, "不正なトークンはバリデーションエラーになる")
+}
+
+// トークンの時刻と、タイムゾーンなしの timestamp 列に保存された updated_at を実際の DB で比較する
+// (トークンと保存値のタイムゾーンがずれていると、9時間分の変更を取りこぼす・返し続ける)
+func TestSyncToken_FindChangedSinceAgainstStoredTask(t *testing.T) {
+	db := SetupTestDB(t)
+	ctx := context.Background()
+	repo := repository.NewTaskRepository(db)
+
+	before := encodeSyncToken(utils.NowJST().Add(-time.Second))
+	task := &models.Task{UserID: uuid.New(), Title: "同期トークン", Status: models.TaskStatusPending, DueDate: utils.NowJST().Add(time.Hour)}
+	require.NoError(t, repo.Create(task))
+	after := encodeSyncToken(utils.NowJST().Add(time.Second))
+
+	since, err := decodeSyncToken(before)
+	require.NoError(t, err)
+	changed, err := repo.FindChangedSince(ctx, task.UserID, since)
+	require.NoError(t, err)
+	require.Len(t, changed, 1, "作成前に発行したトークンでは作成したタスクが返る")
+	assert.Equal(t, task.ID, changed[0].ID)
+
+	since, err = decodeSyncToken(after)
+	require.NoError(t, err)
+	changed, err = repo.FindChangedSince(ctx, task.UserID, since)
+	require.NoError(t, err)
+	assert.Empty(t, changed, "作成後に発行したトークンでは返らない")
+
+	// 削除 (墓標) も同じトークンで検知できる
+	deleted, err := repo.DeleteIfVersion(ctx, task.ID, task.Version)
+	require.NoError(t, err)
+	require.True(t, deleted)
+	changed, err = repo.FindChangedSince(ctx, task.UserID, since)
+	require.NoError(t, err)
+	require.Len(t, changed, 1)
+	assert.True(t, changed[0].DeletedAt.Valid)
+}
+
+func TestSyncPush_TaskConflictResolution(t *testing.T) {
+	userID := uuid.New()
+	taskID := uuid.New()
+	newTitle := "offline edit"
+
+	tests := []struct {
+		name           string
+		change         models.SyncChange
+		setupMock      func(m *mock.MockTaskRepository)
+		expectedResult string
+		expectedVer    int
+	}{
+		{
+			name:   "正常系：base_version が一致すれば適用される",
+			change: models.SyncChange{Entity: models.SyncEntityTask, Op: models.SyncOpUpdate, ID: taskID, BaseVersion: 2, Task: &models.SyncTaskData{Title: &newTitle}},
+			setupMock: func(m *mock.MockTaskRepository) {
+				m.On("FindByID", taskID).Return(&models.Task{ID: taskID, UserID: userID, Title: "old", Version: 2}, nil).Once()
+				m.On("UpdateIfVersion", mockPkg.Anything, mockPkg.AnythingOfType("*models.Task"), 2).Return(true, nil).Once()
+			},
+			expectedResult: models.SyncResultApplied,
+			expectedVer:    3,
+		},
+		{
+			name:   "競合：サーバー側が先に更新されていればサーバー優先で conflict",
+			change: models.SyncChange{Entity: models.SyncEntityTask, Op: models.SyncOpUpdate, ID: taskID, BaseVersion: 1, Task: &models.SyncTaskData{Title: &newTitle}},
+			setupMock: func(m *mock.MockTaskRepository) {
+				m.On("FindByID", taskID).Return(&models.Task{ID: taskID, UserID: userID, Title: "server", Version: 2}, nil).Once()
+			},
+			expectedResult: models.SyncResultConflict,
+			expectedVer:    2,
+		},
+		{
+			name:   "冪等：変更の無い update はバージョンを進めない",
+			change: models.SyncChange{Entity: models.SyncEntityTask, Op: models.SyncOpUpdate, ID: taskID, BaseVersion: 2, Task: &models.SyncTaskData{Title: &newTitle}},
+			setupMock: func(m *mock.MockTaskRepository) {
+				m.On("FindByID", taskID).Return(&models.Task{ID: taskID, UserID: userID, Title: newTitle, Version: 2}, nil).Once()
+			},
+			expectedResult: models.SyncResultApplied,
+			expectedVer:    2,
+		},
+		{
+			name:   "拒否：削除済みの同一IDが残っている create は rejected",
+			change: models.SyncChange{Entity: models.SyncEntityTask, Op: models.SyncOpCreate, ID: taskID, Task: &models.SyncTaskData{Title: &newTitle}},
+			setupMock: func(m *mock.MockTaskRepository) {
+				m.On("FindByID", taskID).Return(nil, gorm.ErrRecordNotFound).Once()
+				m.On("CreateIfAbsent", mockPkg.Anything, mockPkg.AnythingOfType("*models.Task")).Return(false, nil).Once()
+			},
+			expectedResult: models.SyncResultRejected,
+		},
+		{
+			name:   "冪等：削除済みタスクの delete は適用済みとして扱う",
+			change: models.SyncChange{Entity: models.SyncEntityTask, Op: models.SyncOpDelete, ID: taskID, BaseVersion: 2},
+			setupMock: func(m *mock.MockTaskRepository) {
+				m.On("FindByID", taskID).Return(nil, gorm.ErrRecordNotFound).Once()
+			},
+			expectedResult: models.SyncResultApplied,
+		},
+		{
+			name:   "認可：他人のタスクは rejected",
+			change: models.SyncChange{Entity: models.SyncEntityTask, Op: models.SyncOpDelete, ID: taskID, BaseVersion: 1},
+			setupMock: func(m *mock.MockTaskRepository) {
+				m.On("FindByID", taskID).Return(&models.Task{ID: taskID, UserID: uuid.New(), Version: 1}, nil).Once()
+			},
+			expectedResult: models.SyncResultRejected,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			taskRepo := new(mock.MockTaskRepository)
+			tt.setupMock(taskRepo)
+			svc := NewSyncService(taskRepo, nil, nil, nil)
+
+			resp, err := svc.Push(context.Background(), userID, &models.SyncPushRequest{Changes: []models.SyncChange{tt.change}})
+
+			require.NoError(t, err)
+			require.Len(t, resp.Results, 1)
+			assert.Equal(t, tt.expectedResult, resp.Results[0].Result)
+			assert.Equal(t, tt.expectedVer, resp.Results[0].Version)
+			taskRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// DB の一時的な障害で作成できなかった場合は rejected にせず、Push 全体を失敗させてクライアントに再送させる
+func TestSyncPush_CreateFailureIsRetryable(t *testing.T) {
+	userID, taskID := uuid.New(), uuid.New()
+	title := "offline task"
+	taskRepo := new(mock.MockTaskRepository)
+	taskRepo.On("FindByID", taskID).Return(nil, gorm.ErrRecordNotFound).Once()
+	taskRepo.On("CreateIfAbsent", mockPkg.Anything, mockPkg.AnythingOfType("*models.Task")).Return(false, errors.New("connection reset by peer")).Once()
+
+	change := models.SyncChange{Entity: models.SyncEntityTask, Op: models.SyncOpCreate, ID: taskID, Task: &models.SyncTaskData{Title: &title}}
+	_, err := NewSyncService(taskRepo, nil, nil, nil).Push(context.Background(), userID, &models.SyncPushRequest{Changes: []models.SyncChange{change}})
+
+	assert.Error(t, err)
+	taskRepo.AssertExpectations(t)
+}
@@ -3,6 +3,7 @@ package service
 
 import (
 	"context"
+	"errors"
 	"my-portfolio-2025/internal/app/models"
 
 	"github.com/google/uuid"
@@ -25,6 +26,29 @@ type TaskService interface {
 	// DeleteTask: タスクを削除。認可チェックのためにUserIDとTaskIDを受け取る。
 	DeleteTask(userID uuid.UUID, taskID uuid.UUID) error
 
+	// SnoozeTask: 指定時刻までタスクの期限の通知 (リマインダー・督促) を止める。認可チェックのためにUserIDとTaskIDを受け取る。
+	SnoozeTask(userID uuid.UUID, taskID uuid.UUID, req *models.SnoozeRequest) (*models.Task, error)
+
 	// CheckAndQueueDeadlines: 期限切れのタスクをチェックしてSQSにキューイングする
 	CheckAndQueueDeadlines(ctx context.Context) error
 }
+
+// TaskEventPublisher はタスクのドメインイベントの配信先を抽象化します
+// NotificationHub が Redis Pub/Sub 経由で各セッションへ配信する実装を持ちます
+type TaskEventPublisher interface {
+	PublishTaskEvent(ctx context.Context, event *models.TaskEvent) error
+}
+
+// TaskEventPublishers は複数の配信先 (WebSocket と Webhook など) へ同じイベントを配信します
+type TaskEventPublishers []TaskEventPublisher
+
+// PublishTaskEvent は全ての配信先へ配信します。1つの失敗で他の配信先を止めません
+func (p TaskEventPublishers) PublishTaskEvent(ctx context.Context, event *models.TaskEvent) error {
+	var errs []error
+	for _, publisher := range p {
+		if err := publisher.PublishTaskEvent(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -15,17 +15,122 @@ import (
 	"gorm.io/gorm"
 )
 
+// 同時に更新されていた場合に UpdateTask / SnoozeTask がタスクを読み込み直して適用し直す最大回数
+const taskUpdateAttempts = 3
+
 // TaskServiceImpl は TaskService インターフェースの具体的な実装です。
 type TaskServiceImpl struct {
 	taskRepo      repository.TaskRepository // Repositoryへの依存性注入 (DI)
 	workerService *WorkerService            // WorkerServiceへの依存性注入 (DI)
+	events        TaskEventPublisher        // ドメインイベントの配信先 (nil の場合は配信しない)
+	outbox        *Outbox                   // Webhook 向けのイベントの書き込み先 (nil の場合は書き込まない)
 }
 
 // NewTaskService は TaskService の新しいインスタンスを作成します。
-func NewTaskService(repo repository.TaskRepository, workerService *WorkerService) TaskService {
+func NewTaskService(repo repository.TaskRepository, workerService *WorkerService, events TaskEventPublisher, outbox *Outbox) TaskService {
 	return &TaskServiceImpl{
 		taskRepo:      repo,
 		workerService: workerService,
+		events:        events,
+		outbox:        outbox,
+	}
+}
+
+// saveWithEvent は write によるタスクの書き込みと変更イベントの記録を1つのトランザクションで行い、コミット後にイベントを配信します
+// write が false を返した場合 (バージョンが変わっていて書き込まなかった場合) はイベントを記録せず false を返す
+func (s *TaskServiceImpl) saveWithEvent(write func(ctx context.Context, repo repository.TaskRepository) (bool, error), eventType string, task *models.Task, version int, changed []string) (bool, error) {
+	event, err := writeTaskEvent(context.Background(), s.outbox, s.taskRepo, write,
+		func() *models.TaskEvent { return newTaskEvent(eventType, task, version, changed) },
+	)
+	if err != nil || event == nil {
+		return false, err
+	}
+	publishTaskEvent(s.events, event)
+	return true, nil
+}
+
+// updateWithRetry はタスクを読み込んで apply で変更し、読み込んだバージョンのままの場合だけ書き込みます (楽観的ロック)
+// 読み込んだ後に同期 API などで更新されていた場合は、読み込み直して最大 taskUpdateAttempts 回まで適用し直す
+// apply は変更したフィールド名 (JSON名) を返し、何も変更しなかった場合は書き込まずにそのまま返す
+func (s *TaskServiceImpl) updateWithRetry(userID uuid.UUID, taskID uuid.UUID, apply func(task *models.Task) []string) (*models.Task, error) {
+	for attempt := 1; attempt <= taskUpdateAttempts; attempt++ {
+		// GetTaskByIDを呼ぶことで、存在チェックと認可を一括で行う
+		task, err := s.GetTaskByID(userID, taskID)
+		if err != nil {
+			return nil, err // apperr.ErrNotFound か apperr.ErrForbidden が返る
+		}
+
+		changed := apply(task)
+		if len(changed) == 0 {
+			return task, nil
+		}
+
+		loadedVersion := task.Version
+		task.Version++
+		written, err := s.saveWithEvent(func(ctx context.Context, repo repository.TaskRepository) (bool, error) {
+			return repo.UpdateIfVersion(ctx, task, loadedVersion)
+		}, models.WSEventTaskUpdated, task, task.Version, changed)
+		if err != nil {
+			return nil, err
+		}
+		if written {
+			return task, nil
+		}
+		slog.Info("Task was modified concurrently, retrying", "taskID", taskID, "version", loadedVersion, "attempt", attempt)
+	}
+	return nil, fmt.Errorf("%w: task %s was modified concurrently", apperr.ErrConflict, taskID)
+}
+
+// writeTaskEvent は write によるタスクの書き込みと、変更イベントの outbox への記録を1つのトランザクションで行います (SyncService と共用)
+// write にはトランザクションで動くリポジトリが渡されます。write が false を返した場合 (何も書き込まなかった場合) はイベントを記録せず nil を返す
+func writeTaskEvent(ctx context.Context, outbox *Outbox, taskRepo repository.TaskRepository, write func(ctx context.Context, repo repository.TaskRepository) (bool, error), newEvent func() *models.TaskEvent) (*models.TaskEvent, error) {
+	var event *models.TaskEvent
+	err := outbox.Within(ctx, func(ctx context.Context) error {
+		written, err := write(ctx, repository.Bind(ctx, taskRepo))
+		if err != nil || !written {
+			return err
+		}
+		// Create で採番されたIDを載せるため、イベントは書き込みの後に組み立てる
+		event = newEvent()
+		return outbox.RecordTaskEvent(ctx, event)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// newTaskEvent はタスクのドメインイベントを組み立てます (SyncService と共用)
+func newTaskEvent(eventType string, task *models.Task, version int, changed []string) *models.TaskEvent {
+	event := &models.TaskEvent{
+		Type:          eventType,
+		TaskID:        task.ID,
+		UserID:        task.UserID,
+		Version:       version,
+		ChangedFields: changed,
+		OccurredAt:    utils.NowJST(),
+	}
+	if eventType != models.WSEventTaskDeleted {
+		event.Task = task
+	}
+	return event
+}
+
+// publishTaskEvent はコミット済みのタスクのドメインイベントを配信します (SyncService と共用)
+// DBへの変更は既に完了しているため、配信の失敗はログに留めてリクエストは成功させます
+// Webhook へはワーカーが outbox に記録したイベントから配信するため、ここでの配信先には含めない
+func publishTaskEvent(publisher TaskEventPublisher, event *models.TaskEvent) {
+	if publisher == nil || event == nil {
+		return
+	}
+
+	if err := publisher.PublishTaskEvent(context.Background(), event); err != nil {
+		slog.Error("Failed to publish task event",
+			"type", event.Type,
+			"taskID", event.TaskID,
+			"userID", event.UserID,
+			"error", err,
+		)
 	}
 }
 
@@ -43,11 +148,15 @@ func (s *TaskServiceImpl) CreateTask(userID uuid.UUID, req *models.TaskCreateReq
 		Description: req.Description,
 		DueDate:     req.DueDate,
 		Status:      models.TaskStatusPending,
+		Version:     1,
 	}
 
-	if err := s.taskRepo.Create(task); err != nil {
+	if _, err := s.saveWithEvent(func(ctx context.Context, repo repository.TaskRepository) (bool, error) {
+		return true, repo.Create(task)
+	}, models.WSEventTaskCreated, task, task.Version, nil); err != nil {
 		return nil, fmt.Errorf("TaskService.CreateTask: %w", err)
 	}
+
 	return task, nil
 }
 
@@ -89,44 +198,73 @@ func (s *TaskServiceImpl) GetTasks(userID uuid.UUID) ([]models.Task, error) {
 
 // UpdateTask: タスクの更新と認可チェック
 func (s *TaskServiceImpl) UpdateTask(userID uuid.UUID, taskID uuid.UUID, req *models.TaskUpdateRequest) (*models.Task, error) {
-	// GetTaskByIDを呼ぶことで、存在チェックと認可を一括で行う
-	task, err := s.GetTaskByID(userID, taskID)
+	// 変更されたフィールド名 (JSON名) を記録し、イベントに載せる
+	task, err := s.updateWithRetry(userID, taskID, func(task *models.Task) []string {
+		return applyTaskData(task, &models.SyncTaskData{
+			Title:       req.Title,
+			Description: req.Description,
+			DueDate:     req.DueDate,
+			Status:      req.Status,
+		})
+	})
 	if err != nil {
-		return nil, err // apperr.ErrNotFound か apperr.ErrForbidden が返る
-	}
-
-	if req.Title != nil {
-		task.Title = *req.Title
-	}
-	if req.Description != nil {
-		task.Description = *req.Description
-	}
-	if req.DueDate != nil {
-		task.DueDate = *req.DueDate
-	}
-	if req.Status != nil {
-		task.Status = *req.Status
-	}
-
-	if err := s.taskRepo.Update(task); err != nil {
 		return nil, fmt.Errorf("TaskService.UpdateTask: %w", err)
 	}
-
 	return task, nil
 }
 
 // DeleteTask: タスクを削除します。認可チェックが必須です。
 func (s *TaskServiceImpl) DeleteTask(userID uuid.UUID, taskID uuid.UUID) error {
-	if _, err := s.GetTaskByID(userID, taskID); err != nil {
+	task, err := s.GetTaskByID(userID, taskID)
+	if err != nil {
 		return err
 	}
 
-	if err := s.taskRepo.Delete(taskID); err != nil {
+	// 削除も1つの変更としてバージョンを進めて通知する
+	if _, err := s.saveWithEvent(func(ctx context.Context, repo repository.TaskRepository) (bool, error) {
+		return true, repo.Delete(taskID)
+	}, models.WSEventTaskDeleted, task, task.Version+1, nil); err != nil {
 		return fmt.Errorf("TaskService.DeleteTask: %w", err)
 	}
 	return nil
 }
 
+// SnoozeTask: タスクをスヌーズします。認可チェックが必須です。
+// スヌーズ中のタスクにはリマインダー・督促を送らず、解除後に送信時刻を過ぎていたものをまとめて送ります
+func (s *TaskServiceImpl) SnoozeTask(userID uuid.UUID, taskID uuid.UUID, req *models.SnoozeRequest) (*models.Task, error) {
+	until, err := resolveSnooze(req, utils.NowJST())
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := s.updateWithRetry(userID, taskID, func(task *models.Task) []string {
+		task.SnoozedUntil = &until
+		return []string{"snoozed_until"}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("TaskService.SnoozeTask: %w", err)
+	}
+	return task, nil
+}
+
+// resolveSnooze はスヌーズのリクエストから再開時刻を求めます (タスク・通知で共用)
+func resolveSnooze(req *models.SnoozeRequest, now time.Time) (time.Time, error) {
+	latest := now.Add(models.MaxSnoozeMinutes * time.Minute)
+	switch {
+	case req.Until != nil && req.DurationMinutes != 0:
+		return time.Time{}, fmt.Errorf("%w: specify either duration_minutes or until", apperr.ErrValidation)
+	case req.Until != nil:
+		if !req.Until.After(now) || req.Until.After(latest) {
+			return time.Time{}, fmt.Errorf("%w: until must be in the future and within %d minutes", apperr.ErrValidation, models.MaxSnoozeMinutes)
+		}
+		return *req.Until, nil
+	case req.DurationMinutes > 0 && req.DurationMinutes <= models.MaxSnoozeMinutes:
+		return now.Add(time.Duration(req.DurationMinutes) * time.Minute), nil
+	default:
+		return time.Time{}, fmt.Errorf("%w: duration_minutes must be between 1 and %d", apperr.ErrValidation, models.MaxSnoozeMinutes)
+	}
+}
+
 // CheckAndQueueDeadlines: 期限切れのタスクをチェックしてSQSにキューイングする
 func (s *TaskServiceImpl) CheckAndQueueDeadlines(ctx context.Context) error {
 	tasks, err := s.taskRepo.FindUpcomingTasks(ctx, utils.NowJST().Add(1*time.Hour))
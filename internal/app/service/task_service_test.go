@@ -27,7 +27,7 @@ func (s *TaskTestSuite) SetupTest() {
 	// 1. モックの初期化
 	s.mockTaskRepo = new(mock.MockTaskRepository)
 	// 2. サービスの実装にモックと設定を注入
-	s.taskService = NewTaskService(s.mockTaskRepo, nil)
+	s.taskService = NewTaskService(s.mockTaskRepo, nil, nil, nil)
 }
 
 // TestTaskServiceSuite はテストスイートを実行します
@@ -182,7 +182,7 @@ func (s *TaskTestSuite) TestUpdateTask_Success() {
 
 	// 2. モックの期待値設定
 	s.mockTaskRepo.On("FindByID", task.ID).Return(task, nil).Once()
-	s.mockTaskRepo.On("Update", mockPkg.AnythingOfType("*models.Task")).Return(nil).Once()
+	s.mockTaskRepo.On("UpdateIfVersion", mockPkg.Anything, mockPkg.AnythingOfType("*models.Task"), 0).Return(true, nil).Once()
 
 	// 3. 実行と検証
 	task, err := s.taskService.UpdateTask(task.UserID, task.ID, req)
@@ -196,6 +196,62 @@ func (s *TaskTestSuite) TestUpdateTask_Success() {
 	s.mockTaskRepo.AssertExpectations(t)
 }
 
+// 読み込んだ後に同期 API などで更新されていた場合は、読み込み直して適用し直す
+func (s *TaskTestSuite) TestUpdateTask_RetriesOnConcurrentUpdate() {
+	t := s.T()
+
+	stale := &models.Task{ID: uuid.New(), UserID: uuid.New(), Title: "Test Task", Status: models.TaskStatusPending, Version: 1}
+	latest := &models.Task{ID: stale.ID, UserID: stale.UserID, Title: "Synced Title", Status: models.TaskStatusPending, Version: 2}
+	status := models.TaskStatusCompleted
+	req := &models.TaskUpdateRequest{Status: &status}
+
+	s.mockTaskRepo.On("FindByID", stale.ID).Return(stale, nil).Once()
+	s.mockTaskRepo.On("UpdateIfVersion", mockPkg.Anything, stale, 1).Return(false, nil).Once()
+	s.mockTaskRepo.On("FindByID", stale.ID).Return(latest, nil).Once()
+	s.mockTaskRepo.On("UpdateIfVersion", mockPkg.Anything, latest, 2).Return(true, nil).Once()
+
+	updated, err := s.taskService.UpdateTask(stale.UserID, stale.ID, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, updated.Version)
+	assert.Equal(t, "Synced Title", updated.Title, "同時に行われた更新を上書きしない")
+	assert.Equal(t, models.TaskStatusCompleted, updated.Status)
+	s.mockTaskRepo.AssertExpectations(t)
+}
+
+// 更新が競合し続けた場合は ErrConflict を返す
+func (s *TaskTestSuite) TestSnoozeTask_ConflictAfterRetries() {
+	t := s.T()
+
+	userID, taskID := uuid.New(), uuid.New()
+	for range taskUpdateAttempts {
+		s.mockTaskRepo.On("FindByID", taskID).Return(&models.Task{ID: taskID, UserID: userID, Version: 5}, nil).Once()
+	}
+	s.mockTaskRepo.On("UpdateIfVersion", mockPkg.Anything, mockPkg.AnythingOfType("*models.Task"), 5).Return(false, nil).Times(taskUpdateAttempts)
+
+	_, err := s.taskService.SnoozeTask(userID, taskID, &models.SnoozeRequest{DurationMinutes: 60})
+
+	assert.ErrorIs(t, err, apperr.ErrConflict)
+	s.mockTaskRepo.AssertExpectations(t)
+}
+
+// 何も変更しない更新は書き込まない
+func (s *TaskTestSuite) TestUpdateTask_NoChangesDoesNotWrite() {
+	t := s.T()
+
+	task := &models.Task{ID: uuid.New(), UserID: uuid.New(), Title: "Test Task", Version: 4}
+	sameTitle := "Test Task"
+
+	s.mockTaskRepo.On("FindByID", task.ID).Return(task, nil).Once()
+
+	updated, err := s.taskService.UpdateTask(task.UserID, task.ID, &models.TaskUpdateRequest{Title: &sameTitle})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 4, updated.Version)
+	s.mockTaskRepo.AssertNotCalled(t, "UpdateIfVersion", mockPkg.Anything, mockPkg.Anything, mockPkg.Anything)
+	s.mockTaskRepo.AssertNotCalled(t, "Update", mockPkg.Anything)
+}
+
 // (6)DeleteTaskテスト
 func (s *TaskTestSuite) TestDeleteTask_Success() {
 	t := s.T()
@@ -224,6 +280,60 @@ func (s *TaskTestSuite) TestDeleteTask_Success() {
 	s.mockTaskRepo.AssertExpectations(t)
 }
 
+// (7)タスク変更イベントのテスト
+// 実際に変更されたフィールドとインクリメント後のバージョンがイベントに載ることを確認
+func (s *TaskTestSuite) TestUpdateTask_PublishesChangedFields() {
+	t := s.T()
+
+	publisher := new(mock.MockTaskEventPublisher)
+	taskService := NewTaskService(s.mockTaskRepo, nil, publisher, nil)
+
+	task := &models.Task{
+		ID:      uuid.New(),
+		UserID:  uuid.New(),
+		Title:   "Test Task",
+		Status:  models.TaskStatusPending,
+		Version: 3,
+	}
+	sameTitle := "Test Task"
+	status := models.TaskStatusCompleted
+	req := &models.TaskUpdateRequest{Title: &sameTitle, Status: &status}
+
+	s.mockTaskRepo.On("FindByID", task.ID).Return(task, nil).Once()
+	s.mockTaskRepo.On("UpdateIfVersion", mockPkg.Anything, mockPkg.AnythingOfType("*models.Task"), 3).Return(true, nil).Once()
+	publisher.On("PublishTaskEvent", mockPkg.Anything, mockPkg.MatchedBy(func(e *models.TaskEvent) bool {
+		return e.Type == models.WSEventTaskUpdated &&
+			e.Version == 4 &&
+			assert.ObjectsAreEqual([]string{"status"}, e.ChangedFields) &&
+			e.UserID == task.UserID
+	})).Return(nil).Once()
+
+	updated, err := taskService.UpdateTask(task.UserID, task.ID, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 4, updated.Version)
+	publisher.AssertExpectations(t)
+}
+
+// (8)削除イベントのテスト
+func (s *TaskTestSuite) TestDeleteTask_PublishesTombstone() {
+	t := s.T()
+
+	publisher := new(mock.MockTaskEventPublisher)
+	taskService := NewTaskService(s.mockTaskRepo, nil, publisher, nil)
+
+	task := &models.Task{ID: uuid.New(), UserID: uuid.New(), Title: "Test Task", Version: 2}
+
+	s.mockTaskRepo.On("FindByID", task.ID).Return(task, nil).Once()
+	s.mockTaskRepo.On("Delete", task.ID).Return(nil).Once()
+	publisher.On("PublishTaskEvent", mockPkg.Anything, mockPkg.MatchedBy(func(e *models.TaskEvent) bool {
+		return e.Type == models.WSEventTaskDeleted && e.Version == 3 && e.Task == nil
+	})).Return(nil).Once()
+
+	assert.NoError(t, taskService.DeleteTask(task.UserID, task.ID))
+	publisher.AssertExpectations(t)
+}
+
 // 2.認可テスト(異常系)
 // リクエストを行ったユーザーIDが、タスクのuser_idの不一致でエラーを返すことを確認
 
@@ -277,7 +387,7 @@ func (s *TaskTestSuite) TestUpdateTask_Authorization() {
 	assert.Nil(t, updatedTask, "Task should be nil on auth failure")
 
 	s.mockTaskRepo.AssertExpectations(t)
-	s.mockTaskRepo.AssertNotCalled(t, "Update", mockPkg.Anything)
+	s.mockTaskRepo.AssertNotCalled(t, "UpdateIfVersion", mockPkg.Anything, mockPkg.Anything, mockPkg.Anything)
 }
 
 // (3)DeleteTaskテスト
@@ -314,3 +424,91 @@ func (s *TaskTestSuite) TestDeleteTask_Authorization() {
 	s.mockTaskRepo.AssertExpectations(t)                          // FindByIDの呼び出しを検証
 	s.mockTaskRepo.AssertNotCalled(t, "Delete", mockPkg.Anything) // DB削除が実行されていないことを保証
 }
+
+// スヌーズのテスト
+func (s *TaskTestSuite) TestSnoozeTask_PublishesEvent() {
+	t := s.T()
+
+	publisher := new(mock.MockTaskEventPublisher)
+	taskService := NewTaskService(s.mockTaskRepo, nil, publisher, nil)
+
+	task := &models.Task{ID: uuid.New(), UserID: uuid.New(), Title: "Test Task", Version: 2}
+
+	s.mockTaskRepo.On("FindByID", task.ID).Return(task, nil).Once()
+	s.mockTaskRepo.On("UpdateIfVersion", mockPkg.Anything, mockPkg.AnythingOfType("*models.Task"), 2).Return(true, nil).Once()
+	publisher.On("PublishTaskEvent", mockPkg.Anything, mockPkg.MatchedBy(func(e *models.TaskEvent) bool {
+		return e.Type == models.WSEventTaskUpdated &&
+			e.Version == 3 &&
+			assert.ObjectsAreEqual([]string{"snoozed_until"}, e.ChangedFields)
+	})).Return(nil).Once()
+
+	before := utils.NowJST()
+	snoozed, err := taskService.SnoozeTask(task.UserID, task.ID, &models.SnoozeRequest{DurationMinutes: 60})
+
+	assert.NoError(t, err)
+	if assert.NotNil(t, snoozed.SnoozedUntil) {
+		assert.WithinDuration(t, before.Add(time.Hour), *snoozed.SnoozedUntil, 5*time.Second)
+	}
+	publisher.AssertExpectations(t)
+}
+
+func (s *TaskTestSuite) TestSnoozeTask_Authorization() {
+	t := s.T()
+
+	task := &models.Task{ID: uuid.New(), UserID: uuid.New()}
+	s.mockTaskRepo.On("FindByID", task.ID).Return(task, nil).Once()
+
+	_, err := s.taskService.SnoozeTask(uuid.New(), task.ID, &models.SnoozeRequest{DurationMinutes: 60})
+
+	assert.ErrorIs(t, err, apperr.ErrForbidden)
+	s.mockTaskRepo.AssertNotCalled(t, "UpdateIfVersion", mockPkg.Anything, mockPkg.Anything, mockPkg.Anything)
+}
+
+// (outbox) タスクの書き込みと Webhook 向けのイベントの記録が1つのトランザクションで行われることを確認
+func (s *TaskTestSuite) TestCreateTask_RecordsTaskEventInOutbox() {
+	t := s.T()
+
+	outboxRepo := new(mock.MockOutboxRepository)
+	tx := &fakeTransactor{}
+	taskService := NewTaskService(s.mockTaskRepo, nil, nil, NewOutbox(tx, outboxRepo, nil))
+
+	userID := uuid.New()
+	taskID := uuid.New()
+	s.mockTaskRepo.On("Create", mockPkg.AnythingOfType("*models.Task")).
+		Run(func(args mockPkg.Arguments) { args.Get(0).(*models.Task).ID = taskID }).
+		Return(nil).Once()
+	outboxRepo.On("Add", mockPkg.Anything, mockPkg.MatchedBy(func(m *models.OutboxMessage) bool {
+		var event models.TaskEvent
+		env, err := decodeJobEnvelope(m.Body)
+		return err == nil &&
+			m.AggregateType == models.JobTypeTaskEvent &&
+			m.AggregateID == taskID.String() &&
+			(&Job{Envelope: env}).Decode(&event) == nil &&
+			event.Type == models.WSEventTaskCreated &&
+			event.TaskID == taskID &&
+			event.UserID == userID
+	})).Return(nil).Once()
+
+	_, err := taskService.CreateTask(userID, &models.TaskCreateRequest{Title: "Test Task"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, tx.calls)
+	outboxRepo.AssertExpectations(t)
+}
+
+// (outbox) イベントを記録できなかった場合はタスクの作成も失敗させる
+func (s *TaskTestSuite) TestCreateTask_FailsWhenOutboxFails() {
+	t := s.T()
+
+	outboxRepo := new(mock.MockOutboxRepository)
+	publisher := new(mock.MockTaskEventPublisher)
+	taskService := NewTaskService(s.mockTaskRepo, nil, publisher, NewOutbox(&fakeTransactor{}, outboxRepo, nil))
+
+	s.mockTaskRepo.On("Create", mockPkg.AnythingOfType("*models.Task")).Return(nil).Once()
+	outboxRepo.On("Add", mockPkg.Anything, mockPkg.Anything).Return(assert.AnError).Once()
+
+	_, err := taskService.CreateTask(uuid.New(), &models.TaskCreateRequest{Title: "Test Task"})
+
+	assert.ErrorIs(t, err, assert.AnError)
+	publisher.AssertNotCalled(t, "PublishTaskEvent", mockPkg.Anything, mockPkg.Anything)
+}
@@ -0,0 +1,23 @@
+package service
+
+import (
+	"context"
+	"my-portfolio-2025/internal/app/models"
+)
+
+// webhookNotifier は通知を notification.created イベントとして Webhook へ配信します
+type webhookNotifier struct {
+	webhooks WebhookService
+}
+
+// NewWebhookNotifier は Webhook チャネルの Notifier を作成します
+func NewWebhookNotifier(webhooks WebhookService) Notifier {
+	return &webhookNotifier{webhooks: webhooks}
+}
+
+func (w *webhookNotifier) Channel() string { return models.NotificationChannelWebhook }
+
+// Notify は notification.created を購読している送信先へ配信ジョブを投入します
+func (w *webhookNotifier) Notify(ctx context.Context, n *models.Notification) error {
+	return w.webhooks.Dispatch(ctx, n.UserID, models.WebhookEventNotificationCreated, n)
+}
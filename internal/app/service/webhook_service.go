@@ -0,0 +1,47 @@
+package service
+
+import (
+	"context"
+	"my-portfolio-2025/internal/app/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// JobQueue は非同期ジョブの投入先を抽象化します (aws.SQSClient と queue.Queue が実装)
+type JobQueue interface {
+	Enqueue(ctx context.Context, body []byte, delay time.Duration) error
+}
+
+// WebhookService は Webhook の購読管理と配信を扱います
+type WebhookService interface {
+	// Create は購読を作成し、署名用シークレットを含めて返します
+	Create(ctx context.Context, userID uuid.UUID, req *models.WebhookSubscriptionRequest) (*models.WebhookSubscriptionCreated, error)
+
+	// List はユーザーの購読一覧を返します
+	List(ctx context.Context, userID uuid.UUID) ([]models.WebhookSubscription, error)
+
+	// Get は購読を1件返します
+	Get(ctx context.Context, userID uuid.UUID, id uuid.UUID) (*models.WebhookSubscription, error)
+
+	// Update は購読を更新します。active=true を指定すると自動停止を解除します
+	Update(ctx context.Context, userID uuid.UUID, id uuid.UUID, req *models.WebhookSubscriptionRequest) (*models.WebhookSubscription, error)
+
+	// Delete は購読を削除します
+	Delete(ctx context.Context, userID uuid.UUID, id uuid.UUID) error
+
+	// ListDeliveries は配信ログを新しい順に返します
+	ListDeliveries(ctx context.Context, userID uuid.UUID, id uuid.UUID, limit int) ([]models.WebhookDelivery, error)
+
+	// SendTest はテストイベントを同期的に1回だけ送信し、その結果を返します
+	SendTest(ctx context.Context, userID uuid.UUID, id uuid.UUID) (*models.WebhookDelivery, error)
+
+	// Dispatch はイベントを購読している全ての送信先へ配信ジョブを投入します
+	Dispatch(ctx context.Context, userID uuid.UUID, eventType string, data interface{}) error
+
+	// Deliver は配信ジョブを1回実行し、失敗時は再試行ジョブを投入します (WorkerServiceから呼ばれます)
+	Deliver(ctx context.Context, job *models.WebhookJob) error
+
+	// PublishTaskEvent はタスクのドメインイベントを Webhook に変換して配信します (TaskEventPublisher)
+	PublishTaskEvent(ctx context.Context, event *models.TaskEvent) error
+}
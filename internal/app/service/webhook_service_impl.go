@@ -0,0 +1,407 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"my-portfolio-2025/internal/app/apperr"
+	"my-portfolio-2025/internal/app/models"
+	"my-portfolio-2025/internal/app/repository"
+	"my-portfolio-2025/internal/infrastructure/egress"
+	"my-portfolio-2025/pkg/utils"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Webhook 配信のヘッダー
+const (
+	WebhookSignatureHeader = "X-Kota-Signature" // "t=<unix秒>,v1=<hex(HMAC-SHA256(secret, "<t>.<body>"))>"
+	WebhookEventHeader     = "X-Kota-Event"
+	WebhookDeliveryHeader  = "X-Kota-Delivery" // イベントID (再送時も同じ値)
+)
+
+const (
+	// 1イベントあたりの最大試行回数
+	webhookMaxAttempts = 5
+	// この回数だけ連続で失敗した購読は自動停止する
+	webhookDisableThreshold = 10
+	// 再試行の初回待ち時間。以降は試行ごとに2倍にする
+	webhookRetryBaseDelay = 30 * time.Second
+	// 送信先の応答を待つ時間
+	webhookRequestTimeout = 10 * time.Second
+	// 配信ログ一覧の既定件数
+	defaultWebhookDeliveryLimit = 50
+)
+
+type webhookServiceImpl struct {
+	repo       repository.WebhookRepository
+	queue      JobQueue
+	guard      *egress.Guard // 送信先の検査 (テストではローカルサーバーを許可する)
+	httpClient *http.Client
+}
+
+// NewWebhookService は WebhookService の新しいインスタンスを作成します
+// queue が nil の場合、配信ジョブは投入できません (テスト送信のみ可能)
+func NewWebhookService(repo repository.WebhookRepository, queue JobQueue) WebhookService {
+	return &webhookServiceImpl{
+		repo:       repo,
+		queue:      queue,
+		guard:      egress.Default,
+		httpClient: egress.Default.NewHTTPClient(webhookRequestTimeout),
+	}
+}
+
+// Create は購読を作成します
+func (s *webhookServiceImpl) Create(ctx context.Context, userID uuid.UUID, req *models.WebhookSubscriptionRequest) (*models.WebhookSubscriptionCreated, error) {
+	if err := s.validateRequest(ctx, req); err != nil {
+		return nil, err
+	}
+
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return nil, fmt.Errorf("webhookService.Create (secret): %w", err)
+	}
+
+	sub := &models.WebhookSubscription{
+		UserID:     userID,
+		URL:        req.URL,
+		Secret:     secret,
+		EventTypes: req.EventTypes,
+		Active:     true,
+	}
+	if err := s.repo.Create(ctx, sub); err != nil {
+		return nil, fmt.Errorf("webhookService.Create: %w", err)
+	}
+	return &models.WebhookSubscriptionCreated{WebhookSubscription: *sub, Secret: secret}, nil
+}
+
+// List は購読一覧を返します
+func (s *webhookServiceImpl) List(ctx context.Context, userID uuid.UUID) ([]models.WebhookSubscription, error) {
+	subs, err := s.repo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("webhookService.List: %w", err)
+	}
+	if subs == nil {
+		subs = []models.WebhookSubscription{}
+	}
+	return subs, nil
+}
+
+// Get は購読を1件返します
+func (s *webhookServiceImpl) Get(ctx context.Context, userID uuid.UUID, id uuid.UUID) (*models.WebhookSubscription, error) {
+	return s.findOwned(ctx, userID, id)
+}
+
+// Update は購読を更新します
+func (s *webhookServiceImpl) Update(ctx context.Context, userID uuid.UUID, id uuid.UUID, req *models.WebhookSubscriptionRequest) (*models.WebhookSubscription, error) {
+	if err := s.validateRequest(ctx, req); err != nil {
+		return nil, err
+	}
+	sub, err := s.findOwned(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	sub.URL = req.URL
+	sub.EventTypes = req.EventTypes
+	if req.Active != nil {
+		if *req.Active && !sub.Active {
+			// 再開時は失敗回数をリセットする
+			sub.FailureCount = 0
+			sub.DisabledAt = nil
+		}
+		sub.Active = *req.Active
+	}
+
+	if err := s.repo.Update(ctx, sub); err != nil {
+		return nil, fmt.Errorf("webhookService.Update: %w", err)
+	}
+	return sub, nil
+}
+
+// Delete は購読を削除します
+func (s *webhookServiceImpl) Delete(ctx context.Context, userID uuid.UUID, id uuid.UUID) error {
+	if _, err := s.findOwned(ctx, userID, id); err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("webhookService.Delete: %w", err)
+	}
+	return nil
+}
+
+// ListDeliveries は配信ログを返します
+func (s *webhookServiceImpl) ListDeliveries(ctx context.Context, userID uuid.UUID, id uuid.UUID, limit int) ([]models.WebhookDelivery, error) {
+	if _, err := s.findOwned(ctx, userID, id); err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > defaultWebhookDeliveryLimit {
+		limit = defaultWebhookDeliveryLimit
+	}
+
+	deliveries, err := s.repo.FindDeliveries(ctx, id, limit)
+	if err != nil {
+		return nil, fmt.Errorf("webhookService.ListDeliveries: %w", err)
+	}
+	if deliveries == nil {
+		deliveries = []models.WebhookDelivery{}
+	}
+	return deliveries, nil
+}
+
+// SendTest はテストイベントを送信します
+// 疎通確認用のため、結果は配信ログに残すが連続失敗回数には数えない
+func (s *webhookServiceImpl) SendTest(ctx context.Context, userID uuid.UUID, id uuid.UUID) (*models.WebhookDelivery, error) {
+	sub, err := s.findOwned(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := newWebhookJob(sub.ID, models.WebhookEventTest, map[string]interface{}{
+		"subscription_id": sub.ID,
+		"message":         "これは Kota Todo からのテスト送信です",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("webhookService.SendTest: %w", err)
+	}
+	return s.attempt(ctx, sub, job), nil
+}
+
+// Dispatch はイベントを購読している送信先ごとに配信ジョブを投入します
+func (s *webhookServiceImpl) Dispatch(ctx context.Context, userID uuid.UUID, eventType string, data interface{}) error {
+	subs, err := s.repo.FindActiveByEvent(ctx, userID, eventType)
+	if err != nil {
+		return fmt.Errorf("webhookService.Dispatch: %w", err)
+	}
+	if len(subs) == 0 {
+		return nil
+	}
+	if s.queue == nil {
+		return fmt.Errorf("webhookService.Dispatch: job queue is not configured")
+	}
+
+	var errs []error
+	for _, sub := range subs {
+		job, err := newWebhookJob(sub.ID, eventType, data)
+		if err != nil {
+			return fmt.Errorf("webhookService.Dispatch: %w", err)
+		}
+		if err := s.enqueue(ctx, job, 0); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("webhookService.Dispatch: %w", err)
+	}
+	return nil
+}
+
+// Deliver は配信ジョブを実行します
+// 失敗した場合は指数バックオフで再試行ジョブを投入し、連続失敗が閾値に達した購読は自動停止します
+func (s *webhookServiceImpl) Deliver(ctx context.Context, job *models.WebhookJob) error {
+	sub, err := s.repo.FindByID(ctx, job.SubscriptionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// 配信待ちの間に削除された
+			return nil
+		}
+		return fmt.Errorf("webhookService.Deliver: %w", err)
+	}
+	if !sub.Active {
+		return nil
+	}
+
+	delivery := s.attempt(ctx, sub, job)
+	if delivery.Success {
+		if sub.FailureCount > 0 {
+			if err := s.repo.ResetFailures(ctx, sub.ID); err != nil {
+				return fmt.Errorf("webhookService.Deliver: %w", err)
+			}
+		}
+		return nil
+	}
+
+	failures, err := s.repo.RecordFailure(ctx, sub.ID)
+	if err != nil {
+		return fmt.Errorf("webhookService.Deliver: %w", err)
+	}
+	if failures >= webhookDisableThreshold {
+		slog.Warn("Webhook disabled after repeated failures", "subscriptionID", sub.ID, "failures", failures)
+		if err := s.repo.Disable(ctx, sub.ID, utils.NowJST()); err != nil {
+			return fmt.Errorf("webhookService.Deliver: %w", err)
+		}
+		return nil
+	}
+	if job.Attempt >= webhookMaxAttempts {
+		slog.Warn("Webhook delivery gave up", "subscriptionID", sub.ID, "eventID", job.EventID, "attempts", job.Attempt)
+		return nil
+	}
+
+	next := *job
+	next.Attempt++
+	if err := s.enqueue(ctx, &next, webhookRetryDelay(job.Attempt)); err != nil {
+		return fmt.Errorf("webhookService.Deliver (retry): %w", err)
+	}
+	return nil
+}
+
+// PublishTaskEvent はタスクのドメインイベントを Webhook イベントとして配信します
+func (s *webhookServiceImpl) PublishTaskEvent(ctx context.Context, event *models.TaskEvent) error {
+	if err := s.Dispatch(ctx, event.UserID, event.Type, event); err != nil {
+		return err
+	}
+
+	// 完了への変更は task.completed としても通知する
+	if event.Type == models.WSEventTaskUpdated && event.Task != nil &&
+		event.Task.Status == models.TaskStatusCompleted && slices.Contains(event.ChangedFields, "status") {
+		return s.Dispatch(ctx, event.UserID, models.WebhookEventTaskCompleted, event)
+	}
+	return nil
+}
+
+// attempt は送信先へ1回 POST し、結果を配信ログに記録します
+func (s *webhookServiceImpl) attempt(ctx context.Context, sub *models.WebhookSubscription, job *models.WebhookJob) *models.WebhookDelivery {
+	delivery := &models.WebhookDelivery{
+		SubscriptionID: sub.ID,
+		EventID:        job.EventID,
+		EventType:      job.EventType,
+		Attempt:        job.Attempt,
+	}
+
+	started := time.Now()
+	status, err := s.post(ctx, sub, job)
+	delivery.DurationMs = time.Since(started).Milliseconds()
+	delivery.StatusCode = status
+	delivery.Success = err == nil
+	if err != nil {
+		delivery.Error = err.Error()
+	}
+
+	if err := s.repo.CreateDelivery(ctx, delivery); err != nil {
+		// ログの記録失敗で配信結果を変えない
+		slog.Error("Failed to record webhook delivery", "subscriptionID", sub.ID, "error", err)
+	}
+	return delivery
+}
+
+// post は署名付きで本文を送信します。2xx 以外の応答はエラーとして扱います
+func (s *webhookServiceImpl) post(ctx context.Context, sub *models.WebhookSubscription, job *models.WebhookJob) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(job.Body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "KotaTodo-Webhook/1.0")
+	req.Header.Set(WebhookEventHeader, job.EventType)
+	req.Header.Set(WebhookDeliveryHeader, job.EventID.String())
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(sub.Secret, time.Now(), job.Body))
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	// コネクションを再利用できるよう本文を読み捨てる
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+func (s *webhookServiceImpl) enqueue(ctx context.Context, job *models.WebhookJob, delay time.Duration) error {
+	if s.queue == nil {
+		return fmt.Errorf("job queue is not configured")
+	}
+	// 再試行も同じイベントとして追跡できるよう、イベントIDを相関IDにする
+	return enqueueJob(ctx, s.queue, models.JobTypeWebhookDelivery, job.EventID.String(), job, delay)
+}
+
+// findOwned は購読を取得し、所有者を確認します
+// 他人の購読は存在を明かさないよう NotFound として扱う
+func (s *webhookServiceImpl) findOwned(ctx context.Context, userID uuid.UUID, id uuid.UUID) (*models.WebhookSubscription, error) {
+	sub, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: webhookID %s", apperr.ErrNotFound, id)
+		}
+		return nil, fmt.Errorf("webhookService.findOwned: %w", err)
+	}
+	if sub.UserID != userID {
+		return nil, fmt.Errorf("%w: webhookID %s for userID %s", apperr.ErrNotFound, id, userID)
+	}
+	return sub, nil
+}
+
+// SignWebhookPayload は X-Kota-Signature ヘッダーの値を作成します
+// 受信側は同じ計算で v1 を検証し、t が古すぎるリクエストを拒否することでリプレイを防げます
+func SignWebhookPayload(secret string, at time.Time, body []byte) string {
+	ts := strconv.FormatInt(at.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "t=" + ts + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookRetryDelay は attempt 回目の失敗後、次の試行までの待ち時間です (30秒, 1分, 2分, 4分...)
+func webhookRetryDelay(attempt int) time.Duration {
+	return webhookRetryBaseDelay << (attempt - 1)
+}
+
+// newWebhookJob はイベントの1回目の配信ジョブを作成します
+func newWebhookJob(subscriptionID uuid.UUID, eventType string, data interface{}) (*models.WebhookJob, error) {
+	payload := models.WebhookPayload{
+		ID:        uuid.New(),
+		Type:      eventType,
+		CreatedAt: utils.NowJST(),
+		Data:      data,
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("marshal webhook payload: %w", err)
+	}
+	return &models.WebhookJob{
+		SubscriptionID: subscriptionID,
+		EventID:        payload.ID,
+		EventType:      eventType,
+		Body:           body,
+		Attempt:        1,
+	}, nil
+}
+
+// validateRequest は購読の内容を検証します
+// 送信先は https のみとし、内部アドレス (ループバック・プライベート・メタデータエンドポイント) は拒否する
+func (s *webhookServiceImpl) validateRequest(ctx context.Context, req *models.WebhookSubscriptionRequest) error {
+	if err := s.guard.ValidateURL(ctx, req.URL); err != nil {
+		return fmt.Errorf("%w: %v", apperr.ErrValidation, err)
+	}
+	for _, t := range req.EventTypes {
+		if !slices.Contains(models.WebhookEventTypes, t) {
+			return fmt.Errorf("%w: unknown event type %q", apperr.ErrValidation, t)
+		}
+	}
+	return nil
+}
+
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
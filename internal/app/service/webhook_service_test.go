@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"my-portfolio-2025/internal/app/apperr"
+	"my-portfolio-2025/internal/app/models"
+	"my-portfolio-2025/internal/infrastructure/egress"
+	"my-portfolio-2025/internal/testutils/mock"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	mockPkg "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeJobQueue は投入されたジョブを記録するテスト用の JobQueue です
+type fakeJobQueue struct {
+	mu     sync.Mutex
+	bodies [][]byte
+	delays []time.Duration
+}
+
+// decodeQueuedJob はキューに送られた本文の封筒を確認し、Payload を v にデコードします
+func decodeQueuedJob(t *testing.T, body []byte, jobType string, v interface{}) {
+	t.Helper()
+	var env models.JobEnvelope
+	require.NoError(t, json.Unmarshal(body, &env))
+	require.Equal(t, jobType, env.Type)
+	require.Equal(t, models.JobEnvelopeVersion, env.Version)
+	require.NoError(t, json.Unmarshal(env.Payload, v))
+}
+
+func (q *fakeJobQueue) Enqueue(ctx context.Context, body []byte, delay time.Duration) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.bodies = append(q.bodies, body)
+	q.delays = append(q.delays, delay)
+	return nil
+}
+
+// localEgress は httptest のローカルサーバー (http://127.0.0.1) への送信を許可します
+var localEgress = &egress.Guard{AllowHTTP: true, AllowPrivate: true}
+
+// newTestWebhookService はローカルの httptest サーバーへ配信できる webhookServiceImpl を作成します
+func newTestWebhookService(repo *mock.MockWebhookRepository, queue JobQueue) *webhookServiceImpl {
+	s := NewWebhookService(repo, queue).(*webhookServiceImpl)
+	s.guard = localEgress
+	s.httpClient = localEgress.NewHTTPClient(webhookRequestTimeout)
+	return s
+}
+
+func TestWebhookCreate_RejectsInternalURL(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+	}{
+		{name: "http", url: "http://93.184.216.34/hook"},
+		{name: "ループバック", url: "https://127.0.0.1/hook"},
+		{name: "プライベートアドレス", url: "https://10.0.0.8/hook"},
+		{name: "メタデータエンドポイント", url: "https://169.254.169.254/latest/meta-data/iam/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mock.MockWebhookRepository)
+			req := &models.WebhookSubscriptionRequest{URL: tt.url, EventTypes: []string{models.WebhookEventTaskCreated}}
+
+			_, err := NewWebhookService(repo, nil).Create(context.Background(), uuid.New(), req)
+
+			assert.ErrorIs(t, err, apperr.ErrValidation)
+			repo.AssertNotCalled(t, "Create", mockPkg.Anything, mockPkg.Anything)
+		})
+	}
+}
+
+func TestWebhookDeliver_RejectsInternalAddress(t *testing.T) {
+	var called bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	// 登録後に内部アドレスへ向けられた購読 (DNS rebinding など) にも送信しない
+	sub := &models.WebhookSubscription{ID: uuid.New(), URL: server.URL, Secret: "s", Active: true}
+	repo := new(mock.MockWebhookRepository)
+	repo.On("FindByID", mockPkg.Anything, sub.ID).Return(sub, nil)
+	repo.On("CreateDelivery", mockPkg.Anything, mockPkg.MatchedBy(func(d *models.WebhookDelivery) bool {
+		return !d.Success
+	})).Return(nil)
+	repo.On("RecordFailure", mockPkg.Anything, sub.ID).Return(1, nil)
+
+	job, err := newWebhookJob(sub.ID, models.WebhookEventTaskCreated, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, NewWebhookService(repo, &fakeJobQueue{}).Deliver(context.Background(), job))
+	assert.False(t, called)
+	repo.AssertExpectations(t)
+}
+
+func TestWebhookDeliver_SignsPayload(t *testing.T) {
+	const secret = "whsec_test"
+	var gotSignature, gotEvent string
+	var gotBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotSignature = r.Header.Get(WebhookSignatureHeader)
+		gotEvent = r.Header.Get(WebhookEventHeader)
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	sub := &models.WebhookSubscription{ID: uuid.New(), URL: server.URL, Secret: secret, Active: true}
+	repo := new(mock.MockWebhookRepository)
+	repo.On("FindByID", mockPkg.Anything, sub.ID).Return(sub, nil)
+	repo.On("CreateDelivery", mockPkg.Anything, mockPkg.MatchedBy(func(d *models.WebhookDelivery) bool {
+		return d.Success && d.StatusCode == http.StatusNoContent
+	})).Return(nil)
+
+	job, err := newWebhookJob(sub.ID, models.WebhookEventTaskCreated, map[string]string{"title": "資料作成"})
+	require.NoError(t, err)
+
+	require.NoError(t, newTestWebhookService(repo, &fakeJobQueue{}).Deliver(context.Background(), job))
+
+	// 受信側と同じ手順で署名を検証できる
+	assert.Equal(t, models.WebhookEventTaskCreated, gotEvent)
+	parts := strings.Split(gotSignature, ",")
+	require.Len(t, parts, 2)
+	ts, err := strconv.ParseInt(strings.TrimPrefix(parts[0], "t="), 10, 64)
+	require.NoError(t, err)
+	assert.Equal(t, SignWebhookPayload(secret, time.Unix(ts, 0), gotBody), gotSignature)
+	repo.AssertExpectations(t)
+}
+
+func TestWebhookDeliver_RetryAndDisable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name          string
+		attempt       int
+		failures      int
+		expectRetry   bool
+		expectDelay   time.Duration
+		expectDisable bool
+	}{
+		{name: "1回目の失敗は30秒後に再試行", attempt: 1, failures: 1, expectRetry: true, expectDelay: 30 * time.Second},
+		{name: "3回目の失敗は2分後に再試行", attempt: 3, failures: 3, expectRetry: true, expectDelay: 2 * time.Minute},
+		{name: "最大試行回数に達したら諦める", attempt: webhookMaxAttempts, failures: 5},
+		{name: "連続失敗が閾値に達したら自動停止", attempt: 2, failures: webhookDisableThreshold, expectDisable: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub := &models.WebhookSubscription{ID: uuid.New(), URL: server.URL, Secret: "s", Active: true}
+			repo := new(mock.MockWebhookRepository)
+			repo.On("FindByID", mockPkg.Anything, sub.ID).Return(sub, nil)
+			repo.On("CreateDelivery", mockPkg.Anything, mockPkg.Anything).Return(nil)
+			repo.On("RecordFailure", mockPkg.Anything, sub.ID).Return(tt.failures, nil)
+			if tt.expectDisable {
+				repo.On("Disable", mockPkg.Anything, sub.ID, mockPkg.Anything).Return(nil).Once()
+			}
+			queue := &fakeJobQueue{}
+
+			job, err := newWebhookJob(sub.ID, models.WebhookEventTaskUpdated, nil)
+			require.NoError(t, err)
+			job.Attempt = tt.attempt
+
+			require.NoError(t, newTestWebhookService(repo, queue).Deliver(context.Background(), job))
+
+			if !tt.expectRetry {
+				assert.Empty(t, queue.bodies)
+			} else {
+				require.Len(t, queue.bodies, 1)
+				assert.Equal(t, tt.expectDelay, queue.delays[0])
+				var next models.WebhookJob
+				decodeQueuedJob(t, queue.bodies[0], models.JobTypeWebhookDelivery, &next)
+				assert.Equal(t, tt.attempt+1, next.Attempt)
+				assert.Equal(t, job.EventID, next.EventID, "再試行でもイベントIDは変わらない")
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWebhookPublishTaskEvent_Completed(t *testing.T) {
+	userID := uuid.New()
+	repo := new(mock.MockWebhookRepository)
+	repo.On("FindActiveByEvent", mockPkg.Anything, userID, models.WebhookEventTaskUpdated).Return(nil, nil).Once()
+	repo.On("FindActiveByEvent", mockPkg.Anything, userID, models.WebhookEventTaskCompleted).
+		Return([]models.WebhookSubscription{{ID: uuid.New(), UserID: userID}}, nil).Once()
+	queue := &fakeJobQueue{}
+
+	event := &models.TaskEvent{
+		Type:          models.WSEventTaskUpdated,
+		UserID:        userID,
+		ChangedFields: []string{"status"},
+		Task:          &models.Task{Status: models.TaskStatusCompleted},
+	}
+	require.NoError(t, NewWebhookService(repo, queue).PublishTaskEvent(context.Background(), event))
+
+	require.Len(t, queue.bodies, 1)
+	var job models.WebhookJob
+	decodeQueuedJob(t, queue.bodies[0], models.JobTypeWebhookDelivery, &job)
+	assert.Equal(t, models.WebhookEventTaskCompleted, job.EventType)
+	repo.AssertExpectations(t)
+}
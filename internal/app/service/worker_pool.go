@@ -0,0 +1,241 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"my-portfolio-2025/internal/infrastructure/queue"
+	"sync"
+	"time"
+)
+
+const (
+	// 1回の Receive / Ack で扱うメッセージの上限 (SQS の ReceiveMessage / DeleteMessageBatch の上限に合わせる)
+	maxSQSBatchSize = 10
+	// 削除待ちのメッセージをまとめて削除するまでの最大待ち時間
+	deleteFlushInterval = time.Second
+)
+
+// WorkerPoolConfig は StartWorker の並列処理の設定です
+type WorkerPoolConfig struct {
+	// 同時に処理するメッセージの最大数
+	Concurrency int
+	// 1回の Receive で受信するメッセージの最大数 (1〜10)
+	BatchSize int
+	// 処理中のメッセージの可視性タイムアウト。処理が長引いた場合はこの半分ごとに延長する (0 の場合は延長しない)
+	VisibilityTimeout time.Duration
+	// 停止時に処理中のメッセージを待つ最大時間。過ぎたら処理を中断し、メッセージをすぐに再受信できるよう戻す (0 の場合は終わるまで待つ)
+	DrainTimeout time.Duration
+}
+
+// DefaultWorkerPoolConfig は既定の設定を返します
+// VisibilityTimeout は infra/sqs.tf のキューの visibility_timeout_seconds に合わせる
+func DefaultWorkerPoolConfig() WorkerPoolConfig {
+	return WorkerPoolConfig{
+		Concurrency:       4,
+		BatchSize:         maxSQSBatchSize,
+		VisibilityTimeout: 60 * time.Second,
+		DrainTimeout:      25 * time.Second,
+	}
+}
+
+// normalized は範囲外の値を補正した設定を返します
+func (c WorkerPoolConfig) normalized() WorkerPoolConfig {
+	c.Concurrency = max(c.Concurrency, 1)
+	c.BatchSize = min(max(c.BatchSize, 1), maxSQSBatchSize)
+	return c
+}
+
+// StartWorker はGoルーチンで実行されるポーリングループです
+// 空いている処理枠の数だけメッセージをまとめて受信し、cfg.Concurrency 件まで並列に処理します
+// ctx がキャンセルされると受信を止め、処理中のメッセージを最後まで処理して削除してから戻ります
+// cfg.DrainTimeout までに終わらない処理は中断し、メッセージを他のワーカーがすぐに受信できるよう戻します
+func (s *WorkerService) StartWorker(ctx context.Context, cfg WorkerPoolConfig) {
+	cfg = cfg.normalized()
+	slog.Info("Queue worker started", "concurrency", cfg.Concurrency, "batchSize", cfg.BatchSize)
+
+	// 処理中のメッセージは受信を止めた後も最後まで処理するため、ctx とは別の Context で処理する
+	// (DrainTimeout を過ぎた場合だけ abort でキャンセルする)
+	procCtx, abort := context.WithCancel(context.WithoutCancel(ctx))
+	defer abort()
+
+	slots := make(chan struct{}, cfg.Concurrency)
+	done := make(chan queue.Message, cfg.Concurrency)
+	var inFlight sync.WaitGroup
+
+	deleterStopped := make(chan struct{})
+	go func() {
+		defer close(deleterStopped)
+		// 処理を中断した後も処理済みのメッセージは削除するため、キャンセルされない Context を使う
+		s.runDeleter(context.WithoutCancel(ctx), done)
+	}()
+
+	defer func() {
+		// 処理中のメッセージを待ち、削除待ちのメッセージを削除してから戻る
+		drain(&inFlight, cfg.DrainTimeout, abort)
+		close(done)
+		<-deleterStopped
+		slog.Info("Worker shutting down")
+	}()
+
+	for {
+		n, ok := acquireSlots(ctx, slots, cfg.BatchSize)
+		if !ok {
+			return
+		}
+
+		msgs, err := s.queue.Receive(ctx, n, cfg.VisibilityTimeout)
+
+		if err != nil {
+			releaseSlots(slots, n)
+			// 【修正ポイント】ctx.Done()によるエラーなら、エラーログを出さずに終了する
+			if ctx.Err() != nil {
+				slog.Info("Worker loop stopped by context cancellation")
+				return
+			}
+			slog.Error("Failed to receive messages from queue", "error", err)
+
+			// エラー時の待機中もキャンセルを検知できるようにする
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(5 * time.Second):
+				continue
+			}
+		}
+
+		// 受信できなかった分の処理枠は返す
+		releaseSlots(slots, n-len(msgs))
+		for _, msg := range msgs {
+			inFlight.Add(1)
+			go func() {
+				defer inFlight.Done()
+				defer releaseSlots(slots, 1)
+				if s.handleMessage(procCtx, msg, cfg.VisibilityTimeout) {
+					done <- msg
+				}
+			}()
+		}
+	}
+}
+
+// drain は処理中のメッセージを待ちます
+// timeout を過ぎたら abort で処理を中断し、中断された処理がメッセージを戻し終えるのを待つ
+func drain(inFlight *sync.WaitGroup, timeout time.Duration, abort context.CancelFunc) {
+	finished := make(chan struct{})
+	go func() {
+		inFlight.Wait()
+		close(finished)
+	}()
+	if timeout <= 0 {
+		<-finished
+		return
+	}
+
+	select {
+	case <-finished:
+	case <-time.After(timeout):
+		slog.Warn("In-flight messages did not finish before the drain timeout, releasing them", "timeout", timeout)
+		abort()
+		<-finished
+	}
+}
+
+// acquireSlots は空いている処理枠を1つ以上 (最大 limit 個) 確保し、確保した数を返します
+// 処理枠が空くのを待つ間に ctx がキャンセルされた場合は false を返します
+func acquireSlots(ctx context.Context, slots chan struct{}, limit int) (int, bool) {
+	select {
+	case slots <- struct{}{}:
+	case <-ctx.Done():
+		return 0, false
+	}
+
+	n := 1
+	for n < limit {
+		select {
+		case slots <- struct{}{}:
+			n++
+		default:
+			return n, true
+		}
+	}
+	return n, true
+}
+
+// releaseSlots は処理枠を n 個返します
+func releaseSlots(slots chan struct{}, n int) {
+	for range n {
+		<-slots
+	}
+}
+
+// runDeleter は処理を終えたメッセージをまとめて Ack (削除) します
+// done が閉じられると、残りのメッセージを削除してから戻ります
+func (s *WorkerService) runDeleter(ctx context.Context, done <-chan queue.Message) {
+	ticker := time.NewTicker(deleteFlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]queue.Message, 0, maxSQSBatchSize)
+	flush := func() {
+		if len(batch) > 0 {
+			s.deleteMessages(ctx, batch)
+			batch = batch[:0]
+		}
+	}
+
+	for {
+		select {
+		case msg, ok := <-done:
+			if !ok {
+				flush()
+				return
+			}
+			batch = append(batch, msg)
+			if len(batch) == maxSQSBatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// deleteMessages は処理を終えたメッセージ (最大10件) をキューから削除します
+// 削除に失敗した場合は可視性タイムアウト後に再受信されるが、通知は重複排除されるためログに留める
+func (s *WorkerService) deleteMessages(ctx context.Context, msgs []queue.Message) {
+	if err := s.queue.Ack(ctx, msgs); err != nil {
+		slog.Error("Failed to delete messages", "count", len(msgs), "error", err)
+	}
+}
+
+// keepInvisible は処理中のメッセージが再受信されないよう、timeout の半分ごとに可視性タイムアウトを延長します
+// 返り値の関数で延長を止めます。timeout が 0 以下の場合は何もしない
+func (s *WorkerService) keepInvisible(ctx context.Context, msg queue.Message, timeout time.Duration) (stop func()) {
+	if timeout <= 0 {
+		return func() {}
+	}
+
+	quit := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(timeout / 2)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-quit:
+				return
+			case <-ticker.C:
+				slog.Debug("Extending message visibility", "messageID", msg.ID, "timeout", timeout)
+				if err := s.queue.Extend(ctx, msg, timeout); err != nil {
+					slog.Error("Failed to extend message visibility", "messageID", msg.ID, "error", err)
+				}
+			}
+		}
+	}()
+
+	// 延長を止めた後に呼び出し側が可視性タイムアウトを変更するため、延長の goroutine の終了を待つ
+	return func() {
+		close(quit)
+		<-stopped
+	}
+}
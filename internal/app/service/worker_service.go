@@ -3,58 +3,133 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"my-portfolio-2025/internal/app/models"
 	"my-portfolio-2025/internal/app/repository"
-	"my-portfolio-2025/internal/infrastructure/aws"
+	"my-portfolio-2025/internal/infrastructure/queue"
 	"my-portfolio-2025/pkg/utils"
 	"time"
 
-	awsgo "github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/service/sqs"
 	"github.com/google/uuid"
-	// "github.com/aws/aws-sdk-go-v2/aws" as awsgo
 )
 
 type WorkerService struct {
-	// SQSクライアント
-	sqsClient *aws.SQSClient
+	// ジョブキュー (SQS / Redis Streams / Postgres / メモリ)
+	queue queue.Queue
+	// キューへ送るジョブの書き込み先 (nil の場合はキューへ直接送る)
+	outbox *Outbox
+	// 処理済みジョブの台帳 (nil の場合は記録しない)
+	ledger repository.ProcessedJobRepository
 	// タスク管理リポジトリ
 	taskRepo repository.TaskRepository
 	// 通知管理機能
 	notiService NotificationService
-	// Hubを依存注入(DI)できるように追加
-	hub *NotificationHub
+	// 通知設定 (チャネルごとの有効/無効、おやすみ時間帯)
+	prefService NotificationPreferenceService
+	// メールのダイジェスト配信 (nil の場合は送らない)
+	digests DigestService
+	// Webhook の配信ジョブの実行 (nil の場合はジョブを破棄する)
+	webhooks WebhookService
+	// タスクの期限リマインダー (nil の場合は送らない)
+	reminders ReminderService
+	// 期限切れタスクの督促 (nil の場合は送らない)
+	escalations EscalationService
+	// 配信チャネル (WebSocket / メールなど)
+	notifiers []Notifier
+	// ジョブ種別ごとのハンドラー
+	jobs *JobRegistry
 }
 
-func NewWorkerService(sqsClient *aws.SQSClient, taskRepo repository.TaskRepository, notiService NotificationService, hub *NotificationHub) *WorkerService {
-	return &WorkerService{sqsClient: sqsClient, taskRepo: taskRepo, notiService: notiService, hub: hub}
+const (
+	// 1回の監視ループで保留解除する通知の最大件数
+	deferredReleaseBatchSize = 100
+	// 1回の監視ループで送信するリマインダーの最大件数
+	reminderBatchSize = 100
+	// 1回の監視ループで送信する督促の最大件数
+	escalationBatchSize = 100
+	// SQS メッセージの最大受信回数 (infra/sqs.tf の redrive policy の maxReceiveCount のうち大きい方)
+	// これを超えて受信したメッセージは、SQS 以外のバックエンドではデッドレターへ移し、
+	// redrive policy が設定されていない SQS キューでは削除する
+	maxReceiveCount = 5
+	// 処理に失敗したメッセージを再試行するまでの時間 (受信ごとに倍にする)
+	retryBaseDelay = 30 * time.Second
+	retryMaxDelay  = 15 * time.Minute
+	// 処理済みジョブの記録を残す期間 (SQS のメッセージ保持期間の上限。これより後に同じメッセージが配信されることはない)
+	processedJobRetention = 14 * 24 * time.Hour
+)
+
+// errPoisonMessage は何度処理しても成功しないメッセージ (JSON が壊れている、必須項目がないなど) を表します
+var errPoisonMessage = errors.New("poison message")
+
+// NewWorkerService は WorkerService を作成します
+// notifiers には有効な配信チャネルを渡します (例: WebSocket のみ、WebSocket + メール)
+func NewWorkerService(q queue.Queue, outbox *Outbox, ledger repository.ProcessedJobRepository, taskRepo repository.TaskRepository, notiService NotificationService, prefService NotificationPreferenceService, digests DigestService, webhooks WebhookService, reminders ReminderService, escalations EscalationService, notifiers ...Notifier) *WorkerService {
+	s := &WorkerService{
+		queue:       q,
+		outbox:      outbox,
+		ledger:      ledger,
+		taskRepo:    taskRepo,
+		notiService: notiService,
+		prefService: prefService,
+		digests:     digests,
+		webhooks:    webhooks,
+		reminders:   reminders,
+		escalations: escalations,
+		notifiers:   notifiers,
+		jobs:        NewJobRegistry(),
+	}
+	s.jobs.Register(models.JobTypeNotification, models.JobEnvelopeVersion, s.handleNotificationJob)
+	s.jobs.Register(models.JobTypeWebhookDelivery, models.JobEnvelopeVersion, s.handleWebhookJob)
+	s.jobs.Register(models.JobTypeTaskEvent, models.JobEnvelopeVersion, s.handleTaskEventJob)
+	return s
 }
 
 // SendTaskNotification はタスク情報をSQSに送信します
 func (s *WorkerService) SendTaskNotification(ctx context.Context, taskID uuid.UUID, userID uuid.UUID, message string) error {
-	body, err := json.Marshal(map[string]interface{}{
-		"task_id": taskID,
-		"user_id": userID,
-		"message": message,
+	return s.sendTaskNotification(ctx, &models.NotificationMessage{
+		TaskID:  &taskID,
+		UserID:  userID,
+		Type:    models.NotificationTypeDeadline,
+		Message: message,
 	})
+}
+
+// sendTaskNotification は通知種別などを指定してタスクの通知をSQSに送信します
+func (s *WorkerService) sendTaskNotification(ctx context.Context, msg *models.NotificationMessage) error {
+	taskID := *msg.TaskID
+	if msg.ScheduledAt == nil {
+		now := utils.NowJST()
+		msg.ScheduledAt = &now
+	}
+	body, err := encodeJob(models.JobTypeNotification, taskID.String(), msg)
 	if err != nil {
 		return fmt.Errorf("WorkerService.SendTaskNotification (marshal): %w", err)
 	}
 
-	// 1. SQSへメッセージを送信
-	_, err = s.sqsClient.Client.SendMessage(ctx, &sqs.SendMessageInput{
-		QueueUrl:    &s.sqsClient.QueueUrl,
-		MessageBody: awsgo.String(string(body)),
-	})
-	if err != nil {
-		return fmt.Errorf("WorkerService.SendTaskNotification (SQS): %w", err)
+	// outbox がある場合は、ジョブの書き込みと通知時刻の更新を同じトランザクションでコミットする
+	// 呼び出し側のトランザクション内 (リマインダー・督促の取得) で呼ばれた場合は、そのトランザクションに含まれる
+	if s.outbox != nil {
+		return s.outbox.Within(ctx, func(ctx context.Context) error {
+			if err := s.outbox.Enqueue(ctx, body, 0); err != nil {
+				return fmt.Errorf("WorkerService.SendTaskNotification (outbox): %w", err)
+			}
+			if err := s.taskRepo.UpdateLastNotifiedAt(ctx, taskID, utils.NowJST()); err != nil {
+				return fmt.Errorf("WorkerService.SendTaskNotification (DB): %w", err)
+			}
+			return nil
+		})
+	}
+
+	// 1. キューへメッセージを送信
+	if err := s.queue.Enqueue(ctx, body, 0); err != nil {
+		return fmt.Errorf("WorkerService.SendTaskNotification (queue): %w", err)
 	}
 
 	// 2. 送信成功後、DBの通知時刻を更新
 	if err := s.taskRepo.UpdateLastNotifiedAt(ctx, taskID, utils.NowJST()); err != nil {
-		// SQSには送信済みのため、エラーを返さずログに留める（構造化ログの活用）
+		// キューには送信済みのため、エラーを返さずログに留める（構造化ログの活用）
 		slog.Error("Failed to update last_notified_at in DB",
 			"taskID", taskID,
 			"error", err,
@@ -64,80 +139,293 @@ func (s *WorkerService) SendTaskNotification(ctx context.Context, taskID uuid.UU
 	return nil
 }
 
-// StartWorker はGoルーチンで実行されるポーリングループです
-func (s *WorkerService) StartWorker(ctx context.Context) {
-	slog.Info("SQS Worker started")
-	for {
-		select {
-		case <-ctx.Done():
-			slog.Info("Worker shutting down")
-			return
-		default:
-			output, err := s.sqsClient.Client.ReceiveMessage(ctx, &sqs.ReceiveMessageInput{
-				QueueUrl:            &s.sqsClient.QueueUrl,
-				MaxNumberOfMessages: 1,
-				WaitTimeSeconds:     20,
-			})
-
-			if err != nil {
-				// 【修正ポイント】ctx.Done()によるエラーなら、エラーログを出さずに終了する
-				if ctx.Err() != nil {
-					slog.Info("Worker loop stopped by context cancellation")
-					return
-				}
-				slog.Error("Failed to receive message from SQS", "error", err)
-
-				// エラー時の待機中もキャンセルを検知できるようにする
-				select {
-				case <-ctx.Done():
-					return
-				case <-time.After(5 * time.Second):
-					continue
-				}
-			}
+// handleMessage はキューのメッセージを1件処理し、削除してよい (処理を終えた) 場合に true を返します
+//   - 成功: true (呼び出し側がまとめて削除する)
+//   - poison メッセージ: 何度処理しても成功しないため、デッドレターへ移す
+//     (SQS はすぐに再受信させて redrive policy で DLQ へ送らせる)
+//   - 一時的な失敗: 受信回数に応じて可視性タイムアウトを延ばし、時間をおいて再試行させる
+//
+// 受信回数が maxReceiveCount を超えている場合は、無限に再試行しないようデッドレターへ移す
+// (SQS の場合は redrive policy が設定されていないため削除する)
+// visibilityTimeout が正の場合は、処理中にメッセージが再受信されないよう可視性タイムアウトを延長し続ける
+// 停止のために ctx がキャンセルされて処理が中断された場合は、すぐに再受信できるよう戻す
+func (s *WorkerService) handleMessage(ctx context.Context, msg queue.Message, visibilityTimeout time.Duration) bool {
+	messageID := msg.ID
+	body := msg.Body
+	receiveCount := msg.ReceiveCount
+	slog.Debug("Processing queue message", "messageID", messageID, "receiveCount", receiveCount, "body", body)
+
+	if receiveCount > maxReceiveCount {
+		if dlq, ok := s.queue.(queue.DeadLetterer); ok {
+			slog.Error("Queue message exceeded max receive count, moving it to the dead-letter queue",
+				"messageID", messageID,
+				"receiveCount", receiveCount,
+				"body", body,
+			)
+			s.deadLetter(ctx, dlq, msg)
+			return false
+		}
+		slog.Error("Queue message exceeded max receive count, dropping it (dead-letter queue is not configured)",
+			"messageID", messageID,
+			"receiveCount", receiveCount,
+			"body", body,
+		)
+		return true
+	}
+
+	stop := s.keepInvisible(ctx, msg, visibilityTimeout)
+	err := s.processMessage(ctx, messageID, body, receiveCount > 1)
+	stop()
+
+	switch {
+	case err == nil:
+		return true
+	case ctx.Err() != nil:
+		// 停止のために処理を中断した。他のワーカーがすぐに処理し直せるよう戻す
+		slog.Warn("Processing interrupted by shutdown, releasing message",
+			"messageID", messageID,
+			"receiveCount", receiveCount,
+			"error", err,
+		)
+		s.nack(context.WithoutCancel(ctx), msg, 0)
+	case errors.Is(err, errPoisonMessage):
+		slog.Error("Poison queue message, leaving it for the dead-letter queue",
+			"messageID", messageID,
+			"receiveCount", receiveCount,
+			"body", body,
+			"error", err,
+		)
+		if dlq, ok := s.queue.(queue.DeadLetterer); ok {
+			s.deadLetter(ctx, dlq, msg)
+		} else {
+			s.nack(ctx, msg, 0)
+		}
+	default:
+		delay := retryDelay(receiveCount)
+		// 次の受信で DLQ へ移るメッセージはエラーとして記録する
+		level := slog.LevelWarn
+		if receiveCount >= maxReceiveCount {
+			level = slog.LevelError
+		}
+		slog.Log(ctx, level, "Failed to process queue message, will retry",
+			"messageID", messageID,
+			"receiveCount", receiveCount,
+			"retryIn", delay,
+			"error", err,
+		)
+		s.nack(ctx, msg, delay)
+	}
+	return false
+}
+
+// processMessage は SQS メッセージの本文からジョブの封筒を取り出し、ジョブ種別ごとのハンドラーで処理します
+// エラーを返した場合、メッセージは削除されずに再試行されます (errPoisonMessage の場合は DLQ へ送られる)
+// redelivered は同じメッセージを以前にも受信したか (前回の処理が途中で失敗したか) を表します
+func (s *WorkerService) processMessage(ctx context.Context, messageID string, body string, redelivered bool) error {
+	env, err := decodeJobEnvelope(body)
+	if err != nil {
+		return err
+	}
+	return s.jobs.Dispatch(ctx, &Job{Envelope: env, MessageID: messageID, Redelivered: redelivered})
+}
+
+// RegisterJob はワーカーが処理するジョブ種別を追加します (メール送信・データの削除など)
+// 同じキューに流すため、送信側は encodeJob で封筒に入れてください
+func (s *WorkerService) RegisterJob(jobType string, version int, handle JobHandler) {
+	s.jobs.Register(jobType, version, handle)
+}
+
+// handleNotificationJob は通知の作成と配信のジョブを処理します
+func (s *WorkerService) handleNotificationJob(ctx context.Context, job *Job) error {
+	var notifyData models.NotificationMessage
+	if err := job.Decode(&notifyData); err != nil {
+		return err
+	}
+	if notifyData.UserID == uuid.Nil {
+		return fmt.Errorf("%w: user_id is required", errPoisonMessage)
+	}
+
+	if notifyData.Type == "" {
+		notifyData.Type = models.NotificationTypeDeadline
+	}
+	now := utils.NowJST()
+
+	// 通知設定を確認
+	decision := s.evaluate(ctx, notifyData.UserID, notifyData.Type, now).Without(notifyData.WithheldChannels...)
+
+	if len(decision.Channels) == 0 {
+		// 全チャネルが無効なため通知を作成しない
+		slog.Debug("Notification suppressed by preference", "userID", notifyData.UserID, "type", notifyData.Type)
+		return nil
+	}
+
+	// タスクに紐づかない通知は、同じジョブの再配信で二重に作成しないようジョブの冪等キーで重複排除する
+	key := dedupKey(&notifyData, now)
+	if k := idempotencyKey(job); key == nil && k != "" {
+		key = &k
+	}
+	newNoti := &models.Notification{
+		ID:            uuid.New(),
+		UserID:        notifyData.UserID,
+		TaskID:        notifyData.TaskID,
+		Message:       notifyData.Message,
+		Type:          notifyData.Type,
+		Actions:       notificationActions(notifyData.Type, notifyData.TaskID),
+		IsRead:        false,
+		DeferredUntil: decision.DeferUntil,
+		DedupKey:      key,
+		CreatedAt:     now,
+	}
 
-			for _, msg := range output.Messages {
-				slog.Debug("Processing SQS message", "body", *msg.Body)
-
-				var notifyData models.NotificationMessage
-				if err := json.Unmarshal([]byte(*msg.Body), &notifyData); err != nil {
-					slog.Error("Failed to unmarshal SQS message", "error", err)
-					continue
-				}
-
-				newNoti := &models.Notification{
-					ID:        uuid.New(),
-					UserID:    notifyData.UserID,
-					Message:   notifyData.Message,
-					Type:      "task_deadline",
-					IsRead:    false,
-					CreatedAt: utils.NowJST(),
-				}
-
-				// DBに保存
-				if err := s.notiService.Create(ctx, newNoti); err != nil {
-					slog.Error("Failed to save notification to DB", "error", err)
-				} else {
-					notifyData.ID = newNoti.ID
-				}
-
-				// WebSocket/Redis経由でブロードキャスト
-				if err := s.hub.PublishMessage(ctx, notifyData); err != nil {
-					slog.Error("Failed to publish to Redis", "error", err)
-				}
-
-				// 処理完了したメッセージを削除
-				s.sqsClient.Client.DeleteMessage(ctx, &sqs.DeleteMessageInput{
-					QueueUrl:      &s.sqsClient.QueueUrl,
-					ReceiptHandle: msg.ReceiptHandle,
-				})
+	// 処理済みの記録と通知の保存は同じトランザクションで行う (失敗した場合はメッセージを残して再試行する)
+	// 同じジョブが既に処理済みの場合は、通知を作成せずに前回作成した通知を読み込む
+	var created bool
+	err := s.outbox.Within(ctx, func(ctx context.Context) error {
+		prev, first, err := s.recordProcessed(ctx, job, newNoti.ID.String())
+		if err != nil {
+			return err
+		}
+		if !first {
+			*newNoti = models.Notification{}
+			if prevNoti := s.previousNotification(ctx, prev, notifyData.UserID); prevNoti != nil {
+				*newNoti = *prevNoti
 			}
+			return nil
 		}
+		created, err = s.notiService.Create(ctx, newNoti)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("WorkerService.processMessage (save): %w", err)
+	}
+
+	if newNoti.ID == uuid.Nil {
+		// 前回作成した通知が削除済み
+		slog.Info("Duplicate job skipped", "jobID", job.Envelope.ID, "messageID", job.MessageID)
+		return nil
+	}
+	if !created {
+		// 前回の受信で保存だけして配信に失敗した通知は配信し直す
+		// 別のメッセージによる重複 (複数のワーカーの監視ループなど) や、配信済み・保留中・削除済みの通知は配信しない
+		if !job.Redelivered || newNoti.DeliveredAt != nil || newNoti.DeferredUntil != nil || newNoti.DeletedAt.Valid {
+			slog.Info("Duplicate notification skipped", "userID", notifyData.UserID, "type", notifyData.Type, "taskID", notifyData.TaskID)
+			return nil
+		}
+		slog.Info("Redelivering notification saved by a previous attempt", "notificationID", newNoti.ID, "messageID", job.MessageID)
+	} else if decision.DeferUntil != nil {
+		// おやすみ時間帯なら保留し、監視ループが時間帯の終了後に配信する
+		slog.Info("Notification deferred by quiet hours", "notificationID", newNoti.ID, "deferUntil", decision.DeferUntil)
+		return nil
+	}
+
+	return s.deliver(ctx, newNoti, decision)
+}
+
+// idempotencyKey はジョブの冪等キーを返します。キーを決められない場合は空文字
+// 封筒のジョブIDを使い、ジョブIDを持たない封筒の導入前のメッセージは SQS のメッセージIDを使う
+func idempotencyKey(job *Job) string {
+	switch {
+	case job.Envelope.ID != uuid.Nil:
+		return "job:" + job.Envelope.ID.String()
+	case job.MessageID != "":
+		return "sqs:" + job.MessageID
+	}
+	return ""
+}
+
+// recordProcessed はジョブを処理済みとして台帳に記録し、初めての記録なら true を返します
+// 既に記録がある場合は false と前回の記録を返す。ジョブの結果を書き込むのと同じトランザクション内で呼んでください
+// 台帳がない場合や冪等キーを決められない場合は、常に初めての処理として扱う
+func (s *WorkerService) recordProcessed(ctx context.Context, job *Job, resultID string) (*models.ProcessedJob, bool, error) {
+	key := idempotencyKey(job)
+	if s.ledger == nil || key == "" {
+		return nil, true, nil
+	}
+
+	now := utils.NowJST()
+	entry := &models.ProcessedJob{
+		Key:         key,
+		JobType:     job.Envelope.Type,
+		ResultID:    resultID,
+		ProcessedAt: now,
+		ExpiresAt:   now.Add(processedJobRetention),
+	}
+	first, err := s.ledger.Record(ctx, entry)
+	if err != nil {
+		return nil, false, fmt.Errorf("WorkerService.recordProcessed (key=%s): %w", key, err)
+	}
+	return entry, first, nil
+}
+
+// previousNotification は処理済みのジョブが前回作成した通知を返します。削除済みなどで見つからない場合は nil
+func (s *WorkerService) previousNotification(ctx context.Context, prev *models.ProcessedJob, userID uuid.UUID) *models.Notification {
+	id, err := uuid.Parse(prev.ResultID)
+	if err != nil {
+		return nil
+	}
+	n, err := s.notiService.GetByID(ctx, id, userID)
+	if err != nil {
+		return nil
+	}
+	return n
+}
+
+// PurgeProcessedJobs は保持期間を過ぎた処理済みジョブの記録を削除します (定期ジョブとして実行する)
+func (s *WorkerService) PurgeProcessedJobs(ctx context.Context) error {
+	if s.ledger == nil {
+		return nil
+	}
+	deleted, err := s.ledger.DeleteExpired(ctx, utils.NowJST())
+	if err != nil {
+		return fmt.Errorf("WorkerService.PurgeProcessedJobs: %w", err)
+	}
+	if deleted > 0 {
+		slog.Info("Purged processed jobs", "count", deleted)
 	}
+	return nil
+}
+
+// SendDigests は送信時刻を過ぎたダイジェストメールを送ります (定期ジョブとして実行する)
+// メールが設定されていない場合は何もしない
+func (s *WorkerService) SendDigests(ctx context.Context) error {
+	if s.digests == nil {
+		return nil
+	}
+	if err := s.digests.SendDueDigests(ctx, utils.NowJST()); err != nil {
+		return fmt.Errorf("WorkerService.SendDigests: %w", err)
+	}
+	return nil
+}
+
+// nack はメッセージを delay 後に再受信できるようにします
+// 失敗した場合もキューの可視性タイムアウト後に再受信されるため、ログに留める
+func (s *WorkerService) nack(ctx context.Context, msg queue.Message, delay time.Duration) {
+	if err := s.queue.Nack(ctx, msg, delay); err != nil {
+		slog.Error("Failed to change message visibility", "messageID", msg.ID, "error", err)
+	}
+}
+
+// deadLetter はメッセージをデッドレターへ移します
+// 失敗した場合は可視性タイムアウト後に再受信され、次の受信で改めて移すため、ログに留める
+func (s *WorkerService) deadLetter(ctx context.Context, dlq queue.DeadLetterer, msg queue.Message) {
+	if err := dlq.DeadLetter(ctx, msg); err != nil {
+		slog.Error("Failed to move message to the dead-letter queue", "messageID", msg.ID, "error", err)
+	}
+}
+
+// retryDelay は receiveCount 回目の受信で失敗したメッセージを再試行するまでの時間です (指数バックオフ)
+func retryDelay(receiveCount int) time.Duration {
+	delay := retryBaseDelay
+	for i := 1; i < receiveCount && delay < retryMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, retryMaxDelay)
 }
 
 // StartTaskWatcher はGoルーチンで実行されるタスク監視ループです
-// 定期的にDBをチェックし、期限間近なタスクをSQSへ送ります
+// 定期的にDBをチェックし、送信時刻を迎えたリマインダーと期限切れタスクの督促をSQSへ送ります
+// 複数のレプリカで同時に実行すると同じ通知を重複して送るため、LeaderElection でリーダーのレプリカだけが実行します
+// (ctx はリースを失うとキャンセルされる)
 func (s *WorkerService) StartTaskWatcher(ctx context.Context) {
 	ticker := time.NewTicker(1 * time.Minute)
 	defer ticker.Stop()
@@ -146,23 +434,9 @@ func (s *WorkerService) StartTaskWatcher(ctx context.Context) {
 
 	// 共通の処理ロジック
 	runWatcher := func() {
-		threshold := utils.NowJST().Add(1 * time.Hour)
-		tasks, err := s.taskRepo.FindUpcomingTasks(ctx, threshold)
-		if err != nil {
-			slog.Error("Error finding upcoming tasks", "error", err)
-			return
-		}
-
-		for _, task := range tasks {
-			message := fmt.Sprintf("タスク「%s」の期限が近づいています（期限: %s）",
-				task.Title, task.DueDate.In(utils.JST).Format("15:04"))
-
-			if err := s.SendTaskNotification(ctx, task.ID, task.UserID, message); err != nil {
-				slog.Error("Failed to send notification", "taskID", task.ID, "error", err)
-			} else {
-				slog.Info("Successfully queued notification", "taskID", task.ID)
-			}
-		}
+		s.dispatchDeferred(ctx)
+		s.fireReminders(ctx)
+		s.fireEscalations(ctx)
 	}
 
 	runWatcher() // 初回実行
@@ -177,3 +451,263 @@ func (s *WorkerService) StartTaskWatcher(ctx context.Context) {
 		}
 	}
 }
+
+// fireReminders は送信時刻を迎えたリマインダーを通知として SQS へ送ります
+// リマインダーは取得時に送信済みになるため、送信に失敗した場合は未送信に戻して次回に再送する
+// outbox がある場合は、取得と通知ジョブの書き込みを1つのトランザクションで行い、失敗時はまとめてロールバックする
+func (s *WorkerService) fireReminders(ctx context.Context) {
+	if s.reminders == nil {
+		return
+	}
+
+	err := s.outbox.Within(ctx, func(ctx context.Context) error {
+		due, err := s.reminders.ClaimDue(ctx, utils.NowJST(), reminderBatchSize)
+		if err != nil {
+			return fmt.Errorf("claim due reminders: %w", err)
+		}
+		return s.sendReminders(ctx, due)
+	})
+	if err != nil {
+		slog.Error("Failed to fire reminders", "error", err)
+	}
+}
+
+// sendReminders は取得したリマインダーを通知として送ります
+func (s *WorkerService) sendReminders(ctx context.Context, due []models.DueReminder) error {
+	for _, d := range due {
+		// 重複排除の時間枠はリマインダーの送信時刻で決める (別の offset のリマインダーとは重ならない)
+		err := s.sendTaskNotification(ctx, &models.NotificationMessage{
+			TaskID:      &d.Task.ID,
+			UserID:      d.Task.UserID,
+			Type:        models.NotificationTypeDeadline,
+			Message:     reminderMessage(&d.Task, d.Reminder.OffsetMinutes),
+			ScheduledAt: &d.Reminder.RemindAt,
+		})
+		if err != nil {
+			slog.Error("Failed to send reminder", "taskID", d.Task.ID, "reminderID", d.Reminder.ID, "error", err)
+			if s.outbox != nil {
+				return err
+			}
+			if err := s.reminders.Release(ctx, d.Reminder.ID); err != nil {
+				slog.Error("Failed to release reminder", "reminderID", d.Reminder.ID, "error", err)
+			}
+			continue
+		}
+		slog.Info("Successfully queued reminder", "taskID", d.Task.ID, "offsetMinutes", d.Reminder.OffsetMinutes)
+	}
+	return nil
+}
+
+// fireEscalations は期限切れタスクの督促を overdue 通知として SQS へ送ります
+// エスカレーション前の督促では、エスカレーション先のチャネルを除いて配信させる
+// リマインダーと同様に、outbox がある場合は取得と通知ジョブの書き込みを1つのトランザクションで行う
+func (s *WorkerService) fireEscalations(ctx context.Context) {
+	if s.escalations == nil {
+		return
+	}
+
+	err := s.outbox.Within(ctx, func(ctx context.Context) error {
+		now := utils.NowJST()
+		due, err := s.escalations.ClaimDue(ctx, now, escalationBatchSize)
+		if err != nil {
+			return fmt.Errorf("claim due escalations: %w", err)
+		}
+		return s.sendEscalations(ctx, due, now)
+	})
+	if err != nil {
+		slog.Error("Failed to fire escalations", "error", err)
+	}
+}
+
+// sendEscalations は取得した督促を overdue 通知として送ります
+func (s *WorkerService) sendEscalations(ctx context.Context, due []models.DueEscalation, now time.Time) error {
+	for _, d := range due {
+		err := s.sendTaskNotification(ctx, &models.NotificationMessage{
+			TaskID:           &d.Task.ID,
+			UserID:           d.Task.UserID,
+			Type:             models.NotificationTypeOverdue,
+			Message:          overdueMessage(&d.Task, now),
+			WithheldChannels: s.escalations.WithheldChannels(d.Escalation.Level),
+		})
+		if err != nil {
+			slog.Error("Failed to send overdue notification", "taskID", d.Task.ID, "level", d.Escalation.Level, "error", err)
+			if s.outbox != nil {
+				return err
+			}
+			if err := s.escalations.Release(ctx, &d.Escalation); err != nil {
+				slog.Error("Failed to release escalation", "taskID", d.Task.ID, "error", err)
+			}
+			continue
+		}
+		slog.Info("Successfully queued overdue notification", "taskID", d.Task.ID, "level", d.Escalation.Level)
+	}
+	return nil
+}
+
+// dispatchDeferred はおやすみ時間帯が終わった保留中の通知と、スヌーズが明けた通知を配信します
+func (s *WorkerService) dispatchDeferred(ctx context.Context) {
+	now := utils.NowJST()
+	released, err := s.notiService.ReleaseDeferred(ctx, now, deferredReleaseBatchSize)
+	if err != nil {
+		slog.Error("Failed to release deferred notifications", "error", err)
+	}
+
+	for i := range released {
+		// 保留中に設定が変わっている可能性があるため、チャネルは配信時点の設定で判定する
+		n := &released[i]
+		if n.DeliveredAt != nil {
+			// 配信済みの通知はスヌーズが明けたもの。メール・チャット・Webhook へは送り直さず、WebSocket で再表示するだけにする
+			if err := s.redeliverRealtime(ctx, n); err != nil {
+				slog.Error("Failed to redeliver snoozed notification", "notificationID", n.ID, "error", err)
+			}
+			continue
+		}
+		if err := s.deliver(ctx, n, s.evaluate(ctx, n.UserID, n.Type, now)); err != nil {
+			slog.Error("Failed to deliver released notification", "notificationID", n.ID, "error", err)
+		}
+	}
+}
+
+// evaluate は通知設定を評価します
+// 取得に失敗した場合は通知を落とさないよう、全チャネルへの即時配信として扱う
+func (s *WorkerService) evaluate(ctx context.Context, userID uuid.UUID, notificationType string, now time.Time) *models.DeliveryDecision {
+	fallback := &models.DeliveryDecision{Channels: models.NotificationChannels}
+	if s.prefService == nil {
+		return fallback
+	}
+
+	decision, err := s.prefService.Evaluate(ctx, userID, notificationType, now)
+	if err != nil {
+		slog.Error("Failed to evaluate notification preference", "userID", userID, "error", err)
+		return fallback
+	}
+	return decision
+}
+
+// deliver は許可されたチャネルの Notifier へ通知を配信します
+// 1つのチャネルの失敗で他のチャネルへの配信を止めないよう、全チャネルへ配信してから結果を返す
+// WebSocket の失敗 (Redis の障害など) はエラーとして返し、SQS の再試行で配信し直させる
+// その他のチャネルは独自の再試行 (Webhook のジョブなど) を持つか、宛先の設定不備による失敗が多いためログに留める
+func (s *WorkerService) deliver(ctx context.Context, n *models.Notification, decision *models.DeliveryDecision) error {
+	var realtimeErr error
+	for _, notifier := range s.notifiers {
+		if !decision.Allows(notifier.Channel()) {
+			continue
+		}
+		if err := notifier.Notify(ctx, n); err != nil {
+			slog.Error("Failed to deliver notification",
+				"channel", notifier.Channel(),
+				"notificationID", n.ID,
+				"error", err,
+			)
+			if notifier.Channel() == models.NotificationChannelWebSocket {
+				realtimeErr = err
+			}
+		}
+	}
+	if realtimeErr != nil {
+		return fmt.Errorf("WorkerService.deliver (websocket): %w", realtimeErr)
+	}
+
+	if err := s.notiService.MarkDelivered(ctx, n.ID); err != nil {
+		// 記録できなくても配信は終えているため、再試行はしない
+		slog.Error("Failed to mark notification as delivered", "notificationID", n.ID, "error", err)
+	}
+	return nil
+}
+
+// redeliverRealtime は配信済みの通知を WebSocket の Notifier にだけ配信し直します
+func (s *WorkerService) redeliverRealtime(ctx context.Context, n *models.Notification) error {
+	for _, notifier := range s.notifiers {
+		if notifier.Channel() != models.NotificationChannelWebSocket {
+			continue
+		}
+		if err := notifier.Notify(ctx, n); err != nil {
+			return fmt.Errorf("WorkerService.redeliverRealtime: %w", err)
+		}
+	}
+	return nil
+}
+
+// dedupKey はタスクの通知の重複排除キーを返します。タスクに紐づかない通知は重複排除しない
+func dedupKey(msg *models.NotificationMessage, received time.Time) *string {
+	if msg.TaskID == nil {
+		return nil
+	}
+	at := received
+	if msg.ScheduledAt != nil {
+		at = *msg.ScheduledAt
+	}
+	key := models.NotificationDedupKey(msg.Type, *msg.TaskID, at)
+	return &key
+}
+
+// notificationActions は通知種別に応じて通知から実行できる操作を返します
+// タスクの期限・期限切れの通知にのみ、完了・スヌーズ・タスクを開く操作を付ける
+func notificationActions(notificationType string, taskID *uuid.UUID) []models.NotificationAction {
+	if taskID == nil {
+		return nil
+	}
+	switch notificationType {
+	case models.NotificationTypeDeadline, models.NotificationTypeOverdue:
+		return models.TaskNotificationActions()
+	default:
+		return nil
+	}
+}
+
+// isWebhookJob は封筒の導入前の SQS メッセージが Webhook の配信ジョブかを判定します
+func isWebhookJob(body string) bool {
+	var head struct {
+		Kind string `json:"kind"`
+	}
+	return json.Unmarshal([]byte(body), &head) == nil && head.Kind == models.JobKindWebhookDelivery
+}
+
+// handleWebhookJob は Webhook の配信ジョブを実行します
+// 配信先の失敗による再試行は WebhookService が遅延付きの新しいジョブとして投入する
+// DB やキューの障害で再試行を予約できなかった場合はエラーを返し、このメッセージで再試行させる
+func (s *WorkerService) handleWebhookJob(ctx context.Context, job *Job) error {
+	if s.webhooks == nil {
+		slog.Warn("Webhook job dropped: webhook service is not configured")
+		return nil
+	}
+
+	var delivery models.WebhookJob
+	if err := job.Decode(&delivery); err != nil {
+		return err
+	}
+	if err := s.webhooks.Deliver(ctx, &delivery); err != nil {
+		return fmt.Errorf("WorkerService.handleWebhookJob (subscription=%s, event=%s): %w", delivery.SubscriptionID, delivery.EventID, err)
+	}
+	return nil
+}
+
+// handleTaskEventJob は outbox に記録されたタスクの変更イベントから、購読ごとの Webhook の配信ジョブを作成します
+// 配信ジョブの書き込みは処理済みの記録と1つのトランザクションで行うため、再試行や重複配信で同じ購読に二重に配信されることはない
+func (s *WorkerService) handleTaskEventJob(ctx context.Context, job *Job) error {
+	if s.webhooks == nil {
+		slog.Warn("Task event job dropped: webhook service is not configured")
+		return nil
+	}
+
+	var event models.TaskEvent
+	if err := job.Decode(&event); err != nil {
+		return err
+	}
+	err := s.outbox.Within(ctx, func(ctx context.Context) error {
+		_, first, err := s.recordProcessed(ctx, job, "")
+		if err != nil {
+			return err
+		}
+		if !first {
+			slog.Info("Duplicate task event job skipped", "jobID", job.Envelope.ID, "taskID", event.TaskID)
+			return nil
+		}
+		return s.webhooks.PublishTaskEvent(ctx, &event)
+	})
+	if err != nil {
+		return fmt.Errorf("WorkerService.handleTaskEventJob (task=%s, type=%s): %w", event.TaskID, event.Type, err)
+	}
+	return nil
+}
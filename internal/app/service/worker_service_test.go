@@ -2,18 +2,28 @@ package service
 
 import (
 	"context"
+	"errors"
 	"log"
 	"os"
+	"strconv"
 	"sync"
 	"testing"
 	"time"
 
 	"my-portfolio-2025/internal/app/models"
 	"my-portfolio-2025/internal/app/repository"
+	"my-portfolio-2025/internal/infrastructure/aws"
+	"my-portfolio-2025/internal/infrastructure/queue"
+	"my-portfolio-2025/internal/testutils/mock"
 	"my-portfolio-2025/pkg/utils"
 
+	awsgo "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	mockPkg "github.com/stretchr/testify/mock"
 )
 
 func init() {
@@ -37,7 +47,7 @@ func TestIntegration_NotificationFlow(t *testing.T) {
 	// 依存関係をすべて実体で作成 (DI: Dependency Injection)
 	taskRepo := repository.NewTaskRepository(db)
 	notiRepo := repository.NewNotificationRepository(db)
-	notiService := NewNotificationService(notiRepo)
+	notiService := NewNotificationService(notiRepo, nil)
 	rdb := redis.NewClient(&redis.Options{
 		Addr: "localhost:6379", // init()で設定した環境変数を使ってもOK
 	})
@@ -57,7 +67,9 @@ func TestIntegration_NotificationFlow(t *testing.T) {
 	}()
 
 	// WorkerService の作成
-	workerService := NewWorkerService(sqsClient, taskRepo, notiService, hub)
+	prefService := NewNotificationPreferenceService(repository.NewNotificationPreferenceRepository(db))
+	reminderService := NewReminderService(repository.NewTaskReminderRepository(db), taskRepo, prefService)
+	workerService := NewWorkerService(queue.NewSQSQueue(sqsClient), nil, repository.NewProcessedJobRepository(db), taskRepo, notiService, nil, nil, nil, reminderService, nil, NewWebSocketNotifier(hub))
 
 	// テストデータの作成 (1分以内に期限が来るタスク)
 	userID := uuid.New()
@@ -71,11 +83,21 @@ func TestIntegration_NotificationFlow(t *testing.T) {
 	}
 	db.Create(&testTask)
 
+	// 既定のリマインダー (1時間前) を設定する。期限まで1時間を切っているため、すぐに1回送られる
+	if err := reminderService.PublishTaskEvent(ctx, &models.TaskEvent{
+		Type:   models.WSEventTaskCreated,
+		TaskID: testTask.ID,
+		UserID: userID,
+		Task:   &testTask,
+	}); err != nil {
+		t.Fatalf("リマインダーの設定に失敗しました: %v", err)
+	}
+
 	// WorkerとWatcherをバックグラウンドで開始
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		workerService.StartWorker(ctx)
+		workerService.StartWorker(ctx, DefaultWorkerPoolConfig())
 	}()
 
 	wg.Add(1)
@@ -111,3 +133,575 @@ func TestIntegration_NotificationFlow(t *testing.T) {
 	wg.Wait() // 2. すべてのゴルーチンが止まるのを「確実に」待つ
 	t.Log("All background workers stopped safely.")
 }
+
+// fakeSQS は削除・可視性タイムアウトの変更を記録するテスト用の SQS クライアントです
+// pending に入れたメッセージを ReceiveMessage で返します
+type fakeSQS struct {
+	mu           sync.Mutex
+	pending      []types.Message
+	sent         []string
+	deleted      []string
+	deleteCalls  int
+	visibility   map[string]int32
+	maxRequested int32
+}
+
+func (f *fakeSQS) SendMessage(ctx context.Context, params *sqs.SendMessageInput, optFns ...func(*sqs.Options)) (*sqs.SendMessageOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.sent = append(f.sent, awsgo.ToString(params.MessageBody))
+	return &sqs.SendMessageOutput{MessageId: awsgo.String(uuid.NewString())}, nil
+}
+
+func (f *fakeSQS) ReceiveMessage(ctx context.Context, params *sqs.ReceiveMessageInput, optFns ...func(*sqs.Options)) (*sqs.ReceiveMessageOutput, error) {
+	f.mu.Lock()
+	f.maxRequested = max(f.maxRequested, params.MaxNumberOfMessages)
+	n := min(int(params.MaxNumberOfMessages), len(f.pending))
+	msgs := f.pending[:n:n]
+	f.pending = f.pending[n:]
+	f.mu.Unlock()
+
+	if n == 0 {
+		// ロングポーリングの代わりに少し待つ
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	return &sqs.ReceiveMessageOutput{Messages: msgs}, nil
+}
+
+func (f *fakeSQS) deletedCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.deleted)
+}
+
+func (f *fakeSQS) DeleteMessageBatch(ctx context.Context, params *sqs.DeleteMessageBatchInput, optFns ...func(*sqs.Options)) (*sqs.DeleteMessageBatchOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.deleteCalls++
+	for _, e := range params.Entries {
+		f.deleted = append(f.deleted, awsgo.ToString(e.ReceiptHandle))
+	}
+	return &sqs.DeleteMessageBatchOutput{}, nil
+}
+
+func (f *fakeSQS) ChangeMessageVisibility(ctx context.Context, params *sqs.ChangeMessageVisibilityInput, optFns ...func(*sqs.Options)) (*sqs.ChangeMessageVisibilityOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.visibility == nil {
+		f.visibility = make(map[string]int32)
+	}
+	f.visibility[awsgo.ToString(params.ReceiptHandle)] = params.VisibilityTimeout
+	return &sqs.ChangeMessageVisibilityOutput{}, nil
+}
+
+func (f *fakeSQS) GetQueueAttributes(ctx context.Context, params *sqs.GetQueueAttributesInput, optFns ...func(*sqs.Options)) (*sqs.GetQueueAttributesOutput, error) {
+	return &sqs.GetQueueAttributesOutput{}, nil
+}
+
+func (f *fakeSQS) PurgeQueue(ctx context.Context, params *sqs.PurgeQueueInput, optFns ...func(*sqs.Options)) (*sqs.PurgeQueueOutput, error) {
+	return &sqs.PurgeQueueOutput{}, nil
+}
+
+// fakeNotifier は配信回数を数え、決めた結果を返すテスト用の Notifier です
+type fakeNotifier struct {
+	channel string
+	err     error
+	calls   int
+}
+
+func (f *fakeNotifier) Channel() string { return f.channel }
+
+func (f *fakeNotifier) Notify(ctx context.Context, n *models.Notification) error {
+	f.calls++
+	return f.err
+}
+
+// newSQSMessage は受信回数付きの SQS メッセージを作成します
+func newSQSMessage(body string, receiveCount int) types.Message {
+	return types.Message{
+		MessageId:     awsgo.String(uuid.NewString()),
+		ReceiptHandle: awsgo.String(uuid.NewString()),
+		Body:          awsgo.String(body),
+		Attributes: map[string]string{
+			string(types.MessageSystemAttributeNameApproximateReceiveCount): strconv.Itoa(receiveCount),
+		},
+	}
+}
+
+// newQueueMessage は受信回数付きの受信済みメッセージを作成します
+func newQueueMessage(body string, receiveCount int) queue.Message {
+	return queue.Message{
+		ID:           uuid.NewString(),
+		Handle:       uuid.NewString(),
+		Body:         body,
+		ReceiveCount: receiveCount,
+	}
+}
+
+func newTestWorker(client *fakeSQS, repo *mock.MockNotificationRepository, notifiers ...Notifier) *WorkerService {
+	sqsClient := &aws.SQSClient{Client: client, QueueUrl: "http://localhost:4566/000000000000/test-queue"}
+	return NewWorkerService(queue.NewSQSQueue(sqsClient), nil, nil, nil, NewNotificationService(repo, nil), nil, nil, nil, nil, nil, notifiers...)
+}
+
+func systemMessageBody(userID uuid.UUID) string {
+	body, err := encodeJob(models.JobTypeNotification, "", &models.NotificationMessage{
+		UserID:  userID,
+		Type:    models.NotificationTypeSystem,
+		Message: "メンテナンスのお知らせ",
+	})
+	if err != nil {
+		panic(err)
+	}
+	return string(body)
+}
+
+func TestHandleMessage_DeletesOnSuccess(t *testing.T) {
+	client := &fakeSQS{}
+	repo := new(mock.MockNotificationRepository)
+	ws := &fakeNotifier{channel: models.NotificationChannelWebSocket}
+	repo.On("Create", mockPkg.Anything, mockPkg.MatchedBy(func(n *models.Notification) bool {
+		// タスクに紐づかない通知はメッセージIDで重複排除する
+		return n.DedupKey != nil && *n.DedupKey != ""
+	})).Return(true, nil).Once()
+	repo.On("MarkDelivered", mockPkg.Anything, mockPkg.Anything, mockPkg.Anything).Return(nil).Once()
+
+	msg := newQueueMessage(systemMessageBody(uuid.New()), 1)
+	ack := newTestWorker(client, repo, ws).handleMessage(context.Background(), msg, 0)
+
+	assert.True(t, ack)
+	assert.Empty(t, client.visibility)
+	assert.Equal(t, 1, ws.calls)
+	repo.AssertExpectations(t)
+}
+
+func TestHandleMessage_RetriesWhenSaveFails(t *testing.T) {
+	client := &fakeSQS{}
+	repo := new(mock.MockNotificationRepository)
+	ws := &fakeNotifier{channel: models.NotificationChannelWebSocket}
+	repo.On("Create", mockPkg.Anything, mockPkg.Anything).Return(false, errors.New("connection refused")).Once()
+
+	msg := newQueueMessage(systemMessageBody(uuid.New()), 2)
+	ack := newTestWorker(client, repo, ws).handleMessage(context.Background(), msg, 0)
+
+	assert.False(t, ack, "保存に失敗したメッセージは削除しない")
+	assert.Equal(t, int32(retryDelay(2)/time.Second), client.visibility[msg.Handle])
+	assert.Zero(t, ws.calls)
+}
+
+func TestHandleMessage_RetriesAndRedeliversWhenWebSocketFails(t *testing.T) {
+	client := &fakeSQS{}
+	repo := new(mock.MockNotificationRepository)
+	ws := &fakeNotifier{channel: models.NotificationChannelWebSocket, err: errors.New("redis: connection pool timeout")}
+	email := &fakeNotifier{channel: models.NotificationChannelEmail, err: errors.New("smtp: 550")}
+	worker := newTestWorker(client, repo, ws, email)
+
+	// 1回目: 保存はできたが WebSocket への配信に失敗したため、メッセージを残す
+	var saved models.Notification
+	repo.On("Create", mockPkg.Anything, mockPkg.Anything).Run(func(args mockPkg.Arguments) {
+		saved = *args.Get(1).(*models.Notification)
+	}).Return(true, nil).Once()
+
+	first := newQueueMessage(systemMessageBody(uuid.New()), 1)
+	assert.False(t, worker.handleMessage(context.Background(), first, 0))
+	assert.Equal(t, int32(retryBaseDelay/time.Second), client.visibility[first.Handle])
+
+	// 2回目: 同じメッセージの再受信。保存済みで未配信の通知を配信し直す (メールの失敗は再試行の理由にしない)
+	ws.err = nil
+	repo.On("Create", mockPkg.Anything, mockPkg.Anything).Run(func(args mockPkg.Arguments) {
+		*args.Get(1).(*models.Notification) = saved
+	}).Return(false, nil).Once()
+	repo.On("MarkDelivered", mockPkg.Anything, saved.ID, mockPkg.Anything).Return(nil).Once()
+
+	second := first
+	second.Handle = uuid.NewString()
+	second.ReceiveCount = 2
+	assert.True(t, worker.handleMessage(context.Background(), second, 0))
+	assert.Equal(t, 2, ws.calls)
+	repo.AssertExpectations(t)
+}
+
+func TestHandleMessage_SkipsDeliveredDuplicate(t *testing.T) {
+	client := &fakeSQS{}
+	repo := new(mock.MockNotificationRepository)
+	ws := &fakeNotifier{channel: models.NotificationChannelWebSocket}
+	deliveredAt := utils.NowJST()
+	repo.On("Create", mockPkg.Anything, mockPkg.Anything).Run(func(args mockPkg.Arguments) {
+		args.Get(1).(*models.Notification).DeliveredAt = &deliveredAt
+	}).Return(false, nil).Once()
+
+	msg := newQueueMessage(systemMessageBody(uuid.New()), 2)
+	ack := newTestWorker(client, repo, ws).handleMessage(context.Background(), msg, 0)
+
+	assert.True(t, ack)
+	assert.Zero(t, ws.calls, "配信済みの通知は再配信しない")
+}
+
+// fakeLedger は処理済みジョブをメモリに記録するテスト用の台帳です
+type fakeLedger struct {
+	mu   sync.Mutex
+	jobs map[string]models.ProcessedJob
+	err  error
+}
+
+func (f *fakeLedger) Record(ctx context.Context, job *models.ProcessedJob) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return false, f.err
+	}
+	if prev, ok := f.jobs[job.Key]; ok {
+		*job = prev
+		return false, nil
+	}
+	if f.jobs == nil {
+		f.jobs = make(map[string]models.ProcessedJob)
+	}
+	f.jobs[job.Key] = *job
+	return true, nil
+}
+
+func (f *fakeLedger) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	return 0, nil
+}
+
+func TestHandleMessage_LedgerSkipsDuplicateDelivery(t *testing.T) {
+	client := &fakeSQS{}
+	repo := new(mock.MockNotificationRepository)
+	ws := &fakeNotifier{channel: models.NotificationChannelWebSocket}
+	ledger := &fakeLedger{}
+	worker := newTestWorker(client, repo, ws)
+	worker.ledger = ledger
+
+	userID := uuid.New()
+	var saved models.Notification
+	repo.On("Create", mockPkg.Anything, mockPkg.Anything).Run(func(args mockPkg.Arguments) {
+		saved = *args.Get(1).(*models.Notification)
+	}).Return(true, nil).Once()
+	repo.On("MarkDelivered", mockPkg.Anything, mockPkg.Anything, mockPkg.Anything).Return(nil).Once()
+
+	body := systemMessageBody(userID)
+	assert.True(t, worker.handleMessage(context.Background(), newQueueMessage(body, 1), 0))
+
+	// SQS の重複配信: 同じジョブが別のメッセージとして届いても通知を作成しない
+	deliveredAt := utils.NowJST()
+	saved.DeliveredAt = &deliveredAt
+	repo.On("FindByID", mockPkg.Anything, saved.ID, userID).Return(&saved, nil).Once()
+
+	assert.True(t, worker.handleMessage(context.Background(), newQueueMessage(body, 1), 0))
+	assert.Equal(t, 1, ws.calls)
+	assert.Len(t, ledger.jobs, 1)
+	repo.AssertExpectations(t)
+}
+
+func TestHandleMessage_LedgerRedeliversUndeliveredNotification(t *testing.T) {
+	client := &fakeSQS{}
+	repo := new(mock.MockNotificationRepository)
+	ws := &fakeNotifier{channel: models.NotificationChannelWebSocket, err: errors.New("redis: connection pool timeout")}
+	worker := newTestWorker(client, repo, ws)
+	worker.ledger = &fakeLedger{}
+
+	userID := uuid.New()
+	var saved models.Notification
+	repo.On("Create", mockPkg.Anything, mockPkg.Anything).Run(func(args mockPkg.Arguments) {
+		saved = *args.Get(1).(*models.Notification)
+	}).Return(true, nil).Once()
+
+	first := newQueueMessage(systemMessageBody(userID), 1)
+	assert.False(t, worker.handleMessage(context.Background(), first, 0))
+
+	// 再受信では台帳から前回作成した通知を読み込み、未配信なら配信し直す
+	ws.err = nil
+	repo.On("FindByID", mockPkg.Anything, saved.ID, userID).Return(&saved, nil).Once()
+	repo.On("MarkDelivered", mockPkg.Anything, saved.ID, mockPkg.Anything).Return(nil).Once()
+
+	second := first
+	second.ReceiveCount = 2
+	assert.True(t, worker.handleMessage(context.Background(), second, 0))
+	assert.Equal(t, 2, ws.calls)
+	repo.AssertExpectations(t)
+}
+
+func TestHandleMessage_RetriesWhenLedgerFails(t *testing.T) {
+	client := &fakeSQS{}
+	repo := new(mock.MockNotificationRepository)
+	worker := newTestWorker(client, repo)
+	worker.ledger = &fakeLedger{err: errors.New("connection refused")}
+
+	msg := newQueueMessage(systemMessageBody(uuid.New()), 1)
+
+	assert.False(t, worker.handleMessage(context.Background(), msg, 0))
+	repo.AssertNotCalled(t, "Create", mockPkg.Anything, mockPkg.Anything)
+}
+
+func TestHandleMessage_PoisonMessage(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "壊れたJSON", body: `{"user_id":`},
+		{name: "user_id なし", body: `{"type":"system","message":"hello"}`},
+		{name: "壊れた Webhook ジョブ", body: `{"kind":"webhook_delivery","subscription_id":1}`},
+		{name: "未知のジョブ種別", body: `{"type":"unknown","version":1,"payload":{}}`},
+		{name: "Payload なし", body: `{"type":"notification","version":1}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &fakeSQS{}
+			repo := new(mock.MockNotificationRepository)
+			worker := newTestWorker(client, repo)
+			worker.webhooks = NewWebhookService(new(mock.MockWebhookRepository), nil)
+
+			msg := newQueueMessage(tt.body, 1)
+			ack := worker.handleMessage(context.Background(), msg, 0)
+
+			// 削除せず、すぐに再受信させて redrive policy で DLQ へ送らせる
+			assert.False(t, ack)
+			visibility, ok := client.visibility[msg.Handle]
+			assert.True(t, ok)
+			assert.Zero(t, visibility)
+			repo.AssertNotCalled(t, "Create", mockPkg.Anything, mockPkg.Anything)
+		})
+	}
+}
+
+func TestDispatchDeferred_SnoozedNotificationOnlyToWebSocket(t *testing.T) {
+	repo := new(mock.MockNotificationRepository)
+	ws := &fakeNotifier{channel: models.NotificationChannelWebSocket}
+	email := &fakeNotifier{channel: models.NotificationChannelEmail}
+	worker := newTestWorker(&fakeSQS{}, repo, ws, email)
+
+	deliveredAt := utils.NowJST().Add(-time.Hour)
+	snoozed := models.Notification{ID: uuid.New(), UserID: uuid.New(), Type: models.NotificationTypeDeadline, DeliveredAt: &deliveredAt}
+	quiet := models.Notification{ID: uuid.New(), UserID: uuid.New(), Type: models.NotificationTypeDeadline}
+	repo.On("FindDueDeferred", mockPkg.Anything, mockPkg.Anything, deferredReleaseBatchSize).Return([]models.Notification{snoozed, quiet}, nil)
+	repo.On("ClearDeferred", mockPkg.Anything, mockPkg.Anything).Return(true, nil)
+	repo.On("MarkDelivered", mockPkg.Anything, quiet.ID, mockPkg.Anything).Return(nil).Once()
+
+	worker.dispatchDeferred(context.Background())
+
+	// スヌーズが明けた通知は WebSocket だけ、おやすみ時間帯で保留していた通知は全チャネルへ配信する
+	assert.Equal(t, 2, ws.calls)
+	assert.Equal(t, 1, email.calls)
+	repo.AssertNotCalled(t, "MarkDelivered", mockPkg.Anything, snoozed.ID, mockPkg.Anything)
+	repo.AssertExpectations(t)
+}
+
+func TestHandleMessage_DropsAfterMaxReceiveCount(t *testing.T) {
+	client := &fakeSQS{}
+	repo := new(mock.MockNotificationRepository)
+
+	msg := newQueueMessage(systemMessageBody(uuid.New()), maxReceiveCount+1)
+	ack := newTestWorker(client, repo).handleMessage(context.Background(), msg, 0)
+
+	assert.True(t, ack)
+	repo.AssertNotCalled(t, "Create", mockPkg.Anything, mockPkg.Anything)
+}
+
+// deadLetterQueue はデッドレターへ移したメッセージを記録するテスト用のキューです (SQS 以外のバックエンドの代わり)
+type deadLetterQueue struct {
+	queue.Queue
+	dead []queue.Message
+}
+
+func (q *deadLetterQueue) DeadLetter(ctx context.Context, msg queue.Message) error {
+	q.dead = append(q.dead, msg)
+	return nil
+}
+
+func TestHandleMessage_MovesToDeadLetterQueue(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		receiveCount int
+	}{
+		{name: "poison メッセージ", body: `{"type":"unknown","version":1,"payload":{}}`, receiveCount: 1},
+		{name: "最大受信回数を超えた", body: systemMessageBody(uuid.New()), receiveCount: maxReceiveCount + 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mock.MockNotificationRepository)
+			dlq := &deadLetterQueue{Queue: queue.NewMemoryQueue()}
+			worker := NewWorkerService(dlq, nil, nil, nil, NewNotificationService(repo, nil), nil, nil, nil, nil, nil)
+
+			msg := newQueueMessage(tt.body, tt.receiveCount)
+			ack := worker.handleMessage(context.Background(), msg, 0)
+
+			// 削除 (Ack) せずにデッドレターへ移す
+			assert.False(t, ack)
+			assert.Equal(t, []queue.Message{msg}, dlq.dead)
+			repo.AssertNotCalled(t, "Create", mockPkg.Anything, mockPkg.Anything)
+		})
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	assert.Equal(t, 30*time.Second, retryDelay(1))
+	assert.Equal(t, time.Minute, retryDelay(2))
+	assert.Equal(t, 2*time.Minute, retryDelay(3))
+	assert.Equal(t, retryMaxDelay, retryDelay(20))
+}
+
+// blockingNotifier は release が閉じられるまで配信を止め、同時に配信中だった数の最大値を記録する Notifier です
+type blockingNotifier struct {
+	mu        sync.Mutex
+	active    int
+	maxActive int
+	started   chan struct{}
+	release   chan struct{}
+	delivered int
+}
+
+func (b *blockingNotifier) Channel() string { return models.NotificationChannelWebSocket }
+
+func (b *blockingNotifier) Notify(ctx context.Context, n *models.Notification) error {
+	b.mu.Lock()
+	b.active++
+	b.maxActive = max(b.maxActive, b.active)
+	b.mu.Unlock()
+
+	b.started <- struct{}{}
+	select {
+	case <-b.release:
+	case <-ctx.Done():
+		b.mu.Lock()
+		b.active--
+		b.mu.Unlock()
+		return ctx.Err()
+	}
+
+	b.mu.Lock()
+	b.active--
+	b.delivered++
+	b.mu.Unlock()
+	return nil
+}
+
+func newPoolTestRepo() *mock.MockNotificationRepository {
+	repo := new(mock.MockNotificationRepository)
+	repo.On("Create", mockPkg.Anything, mockPkg.Anything).Return(true, nil)
+	repo.On("MarkDelivered", mockPkg.Anything, mockPkg.Anything, mockPkg.Anything).Return(nil)
+	return repo
+}
+
+func TestStartWorker_ProcessesConcurrentlyAndDeletesInBatch(t *testing.T) {
+	client := &fakeSQS{}
+	for range 6 {
+		client.pending = append(client.pending, newSQSMessage(systemMessageBody(uuid.New()), 1))
+	}
+	notifier := &blockingNotifier{started: make(chan struct{}, 6), release: make(chan struct{})}
+	worker := newTestWorker(client, newPoolTestRepo(), notifier)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		worker.StartWorker(ctx, WorkerPoolConfig{Concurrency: 3, BatchSize: 10})
+	}()
+
+	// 処理枠は3つのため、3件が同時に配信中になる
+	for range 3 {
+		<-notifier.started
+	}
+	close(notifier.release)
+	assert.Eventually(t, func() bool { return client.deletedCount() == 6 }, 3*time.Second, 10*time.Millisecond)
+
+	cancel()
+	<-stopped
+
+	assert.Equal(t, 3, notifier.maxActive, "同時に処理するのは Concurrency 件まで")
+	assert.Equal(t, int32(3), client.maxRequested, "空いている処理枠の数だけ受信する")
+	assert.Less(t, client.deleteCalls, 6, "削除はまとめて行う")
+}
+
+func TestStartWorker_DrainsInFlightOnShutdown(t *testing.T) {
+	client := &fakeSQS{pending: []types.Message{newSQSMessage(systemMessageBody(uuid.New()), 1)}}
+	notifier := &blockingNotifier{started: make(chan struct{}, 1), release: make(chan struct{})}
+	worker := newTestWorker(client, newPoolTestRepo(), notifier)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		worker.StartWorker(ctx, DefaultWorkerPoolConfig())
+	}()
+
+	<-notifier.started
+	cancel()
+
+	select {
+	case <-stopped:
+		t.Fatal("処理中のメッセージを待たずに終了しました")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(notifier.release)
+	<-stopped
+	assert.Equal(t, 1, notifier.delivered)
+	assert.Equal(t, 1, client.deletedCount(), "終了前に処理済みのメッセージを削除する")
+}
+
+func TestStartWorker_ReleasesMessagesAfterDrainTimeout(t *testing.T) {
+	sqsMsg := newSQSMessage(systemMessageBody(uuid.New()), 1)
+	client := &fakeSQS{pending: []types.Message{sqsMsg}}
+	notifier := &blockingNotifier{started: make(chan struct{}, 1), release: make(chan struct{})}
+	worker := newTestWorker(client, newPoolTestRepo(), notifier)
+
+	cfg := DefaultWorkerPoolConfig()
+	cfg.DrainTimeout = 50 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		worker.StartWorker(ctx, cfg)
+	}()
+
+	<-notifier.started
+	cancel()
+
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("DrainTimeout を過ぎても終了しませんでした")
+	}
+
+	assert.Zero(t, notifier.delivered)
+	assert.Zero(t, client.deletedCount(), "中断したメッセージは削除しない")
+	visibility, ok := client.visibility[awsgo.ToString(sqsMsg.ReceiptHandle)]
+	assert.True(t, ok, "中断したメッセージはすぐに再受信できるよう戻す")
+	assert.Zero(t, visibility)
+}
+
+func TestKeepInvisible(t *testing.T) {
+	client := &fakeSQS{}
+	worker := newTestWorker(client, new(mock.MockNotificationRepository))
+	msg := newQueueMessage("{}", 1)
+
+	stop := worker.keepInvisible(context.Background(), msg, 40*time.Millisecond)
+	assert.Eventually(t, func() bool {
+		client.mu.Lock()
+		defer client.mu.Unlock()
+		_, ok := client.visibility[msg.Handle]
+		return ok
+	}, time.Second, 5*time.Millisecond, "処理が長引いたら可視性タイムアウトを延長する")
+	stop()
+
+	// 停止後は延長しない
+	client.mu.Lock()
+	delete(client.visibility, msg.Handle)
+	client.mu.Unlock()
+	time.Sleep(60 * time.Millisecond)
+	assert.Empty(t, client.visibility)
+}
+
+func TestWorkerPoolConfig_Normalized(t *testing.T) {
+	cfg := WorkerPoolConfig{Concurrency: 0, BatchSize: 50}.normalized()
+	assert.Equal(t, 1, cfg.Concurrency)
+	assert.Equal(t, 10, cfg.BatchSize)
+}
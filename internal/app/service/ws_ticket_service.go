@@ -0,0 +1,18 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WSTicketService は WebSocket 接続用の短命・使い捨てチケットを管理します
+// 長期間有効な JWT をクエリ文字列に載せずに済むようにするためのものです
+type WSTicketService interface {
+	// Issue はユーザーに紐づくチケットを発行し、チケットと有効期限を返します
+	Issue(ctx context.Context, userID uuid.UUID) (string, time.Time, error)
+
+	// Redeem はチケットを消費してユーザーIDを返します (2回目以降は失敗します)
+	Redeem(ctx context.Context, ticket string) (uuid.UUID, error)
+}
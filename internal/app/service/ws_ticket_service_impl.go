@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"my-portfolio-2025/internal/app/apperr"
+	"my-portfolio-2025/pkg/utils"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// チケットの有効期間。発行後すぐに接続する前提なので短くする
+const wsTicketTTL = 30 * time.Second
+
+type wsTicketServiceImpl struct {
+	rdb *redis.Client
+}
+
+// NewWSTicketService は Redis を保存先とする WSTicketService を作成します
+func NewWSTicketService(rdb *redis.Client) WSTicketService {
+	return &wsTicketServiceImpl{rdb: rdb}
+}
+
+func wsTicketKey(ticket string) string {
+	return "ws_ticket:" + ticket
+}
+
+// Issue は推測不可能なチケットを発行して Redis に保存します
+func (s *wsTicketServiceImpl) Issue(ctx context.Context, userID uuid.UUID) (string, time.Time, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", time.Time{}, fmt.Errorf("wsTicketService.Issue (rand): %w", err)
+	}
+	// Sec-WebSocket-Protocol ヘッダーにも載せられるよう URL セーフな文字だけを使う
+	ticket := base64.RawURLEncoding.EncodeToString(buf)
+
+	if err := s.rdb.Set(ctx, wsTicketKey(ticket), userID.String(), wsTicketTTL).Err(); err != nil {
+		return "", time.Time{}, fmt.Errorf("wsTicketService.Issue (redis): %w", err)
+	}
+	return ticket, utils.NowJST().Add(wsTicketTTL), nil
+}
+
+// Redeem はチケットを取得と同時に削除し、使い捨てにします
+func (s *wsTicketServiceImpl) Redeem(ctx context.Context, ticket string) (uuid.UUID, error) {
+	val, err := s.rdb.GetDel(ctx, wsTicketKey(ticket)).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return uuid.Nil, fmt.Errorf("%w: ws ticket is invalid or already used", apperr.ErrUnauthorized)
+		}
+		return uuid.Nil, fmt.Errorf("wsTicketService.Redeem (redis): %w", err)
+	}
+
+	userID, err := uuid.Parse(val)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("wsTicketService.Redeem (parse): %w", err)
+	}
+	return userID, nil
+}
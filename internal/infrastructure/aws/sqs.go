@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
@@ -13,20 +14,41 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
 )
 
+// SQSAPI は SQSClient が使う SQS の API です (*sqs.Client が実装。テストでは偽物に差し替える)
+type SQSAPI interface {
+	SendMessage(ctx context.Context, params *sqs.SendMessageInput, optFns ...func(*sqs.Options)) (*sqs.SendMessageOutput, error)
+	ReceiveMessage(ctx context.Context, params *sqs.ReceiveMessageInput, optFns ...func(*sqs.Options)) (*sqs.ReceiveMessageOutput, error)
+	DeleteMessageBatch(ctx context.Context, params *sqs.DeleteMessageBatchInput, optFns ...func(*sqs.Options)) (*sqs.DeleteMessageBatchOutput, error)
+	ChangeMessageVisibility(ctx context.Context, params *sqs.ChangeMessageVisibilityInput, optFns ...func(*sqs.Options)) (*sqs.ChangeMessageVisibilityOutput, error)
+	GetQueueAttributes(ctx context.Context, params *sqs.GetQueueAttributesInput, optFns ...func(*sqs.Options)) (*sqs.GetQueueAttributesOutput, error)
+	PurgeQueue(ctx context.Context, params *sqs.PurgeQueueInput, optFns ...func(*sqs.Options)) (*sqs.PurgeQueueOutput, error)
+}
+
 type SQSClient struct {
-	Client   *sqs.Client
+	Client   SQSAPI
 	QueueUrl string
 }
 
-func NewSQSClient(ctx context.Context, queueName string) (*SQSClient, error) {
+// NewSQSClient はメインキュー (環境変数 SQS_QUEUE_URL) の SQSClient を作成します
+func NewSQSClient(ctx context.Context) (*SQSClient, error) {
+	return newSQSClient(ctx, "SQS_QUEUE_URL")
+}
+
+// NewSQSDeadLetterClient はメインキューの DLQ (環境変数 SQS_DLQ_URL) の SQSClient を作成します
+func NewSQSDeadLetterClient(ctx context.Context) (*SQSClient, error) {
+	return newSQSClient(ctx, "SQS_DLQ_URL")
+}
+
+// newSQSClient は環境変数 urlEnv のキューの SQSClient を作成します
+func newSQSClient(ctx context.Context, urlEnv string) (*SQSClient, error) {
 	// 環境変数の取得
-	endpoint := os.Getenv("AWS_ENDPOINT")  // LocalStack用
-	region := os.Getenv("AWS_REGION")      // 基本必須
-	queueUrl := os.Getenv("SQS_QUEUE_URL") // Terraform/環境変数から渡される
+	endpoint := os.Getenv("AWS_ENDPOINT") // LocalStack用
+	region := os.Getenv("AWS_REGION")     // 基本必須
+	queueUrl := os.Getenv(urlEnv)         // Terraform/環境変数から渡される
 
 	// 必須パラメータのチェック
 	if queueUrl == "" {
-		return nil, fmt.Errorf("SQS_QUEUE_URL is not set in environment variables")
+		return nil, fmt.Errorf("%s is not set in environment variables", urlEnv)
 	}
 
 	// リージョンが空の場合のガード
@@ -65,3 +87,24 @@ func NewSQSClient(ctx context.Context, queueName string) (*SQSClient, error) {
 		QueueUrl: queueUrl,
 	}, nil
 }
+
+// SQS の DelaySeconds の上限 (15分)
+const maxDelay = 15 * time.Minute
+
+// Enqueue はメッセージをキューに送信します
+// delay を指定すると、その時間が経過するまでメッセージは受信されません (上限15分)
+func (c *SQSClient) Enqueue(ctx context.Context, body []byte, delay time.Duration) error {
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+
+	_, err := c.Client.SendMessage(ctx, &sqs.SendMessageInput{
+		QueueUrl:     aws.String(c.QueueUrl),
+		MessageBody:  aws.String(string(body)),
+		DelaySeconds: int32(delay / time.Second),
+	})
+	if err != nil {
+		return fmt.Errorf("SQSClient.Enqueue: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,216 @@
+// Package chat はチャットツール (Slack / Microsoft Teams) の Incoming Webhook への投稿を扱います
+package chat
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"my-portfolio-2025/internal/infrastructure/egress"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// 対応しているチャットツール
+const (
+	ProviderSlack = "slack"
+	ProviderTeams = "teams"
+)
+
+// Providers は対応しているチャットツールの一覧です
+var Providers = []string{ProviderSlack, ProviderTeams}
+
+// Message はチャットに投稿する内容です。プロバイダーごとの形式に変換して送ります
+type Message struct {
+	Title  string
+	Text   string
+	Fields []Field
+	Color  string // アクセントカラー ("#RRGGBB")。Teams の themeColor に使用
+}
+
+// Field は「ラベル: 値」形式の補足情報です
+type Field struct {
+	Name  string
+	Value string
+}
+
+// ErrRateLimited は再試行しても 429 が解消しなかったことを表します
+var ErrRateLimited = errors.New("chat: rate limited")
+
+const (
+	// 1回の投稿での最大試行回数 (429 / 5xx の場合に再試行)
+	maxAttempts = 3
+	// Retry-After が無い場合の待ち時間
+	defaultRetryAfter = time.Second
+	// これより長い Retry-After は待たずに諦める (ワーカーを長時間止めないため)
+	maxRetryAfter = 30 * time.Second
+)
+
+// Client は Incoming Webhook へ投稿する HTTP クライアントです
+type Client struct {
+	httpClient *http.Client
+	// sleep は再試行までの待機です (テストで差し替え可能)
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// NewClient は Client を作成します
+// guard は投稿先アドレスの検査です。本番では egress.Default を渡します
+func NewClient(guard *egress.Guard) *Client {
+	return &Client{
+		httpClient: guard.NewHTTPClient(10 * time.Second),
+		sleep:      sleepContext,
+	}
+}
+
+// Post はメッセージをプロバイダーの形式に変換して webhookURL へ投稿します
+// 429 (レート制限) の場合は Retry-After に従って待ってから再試行します
+func (c *Client) Post(ctx context.Context, provider, webhookURL string, msg *Message) error {
+	body, err := Render(provider, msg)
+	if err != nil {
+		return err
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		wait, err := c.post(ctx, webhookURL, body)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if wait < 0 || attempt == maxAttempts {
+			break
+		}
+		if wait > maxRetryAfter {
+			return fmt.Errorf("chat.Client.Post: retry-after %s exceeds limit: %w", wait, err)
+		}
+		if err := c.sleep(ctx, wait); err != nil {
+			return fmt.Errorf("chat.Client.Post: %w", err)
+		}
+	}
+	return fmt.Errorf("chat.Client.Post: %w", lastErr)
+}
+
+// post は1回だけ投稿します
+// 再試行すべき失敗の場合は待ち時間 (>= 0)、再試行しても無駄な失敗の場合は -1 を返します
+func (c *Client) post(ctx context.Context, webhookURL string, body []byte) (time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return -1, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return defaultRetryAfter, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return 0, nil
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return retryAfter(resp.Header.Get("Retry-After")), ErrRateLimited
+	case resp.StatusCode >= 500:
+		return defaultRetryAfter, fmt.Errorf("chat: unexpected status %d", resp.StatusCode)
+	default:
+		// 4xx は URL の誤りや失効なので再試行しない
+		return -1, fmt.Errorf("chat: unexpected status %d", resp.StatusCode)
+	}
+}
+
+// Render はメッセージをプロバイダーの JSON 形式に変換します
+func Render(provider string, msg *Message) ([]byte, error) {
+	switch provider {
+	case ProviderSlack:
+		return json.Marshal(slackPayload(msg))
+	case ProviderTeams:
+		return json.Marshal(teamsPayload(msg))
+	default:
+		return nil, fmt.Errorf("chat: unknown provider %q", provider)
+	}
+}
+
+// slackPayload は Slack Block Kit 形式のペイロードを作成します
+// text は通知 (プッシュ・プレビュー) 用のフォールバックです
+func slackPayload(msg *Message) map[string]interface{} {
+	blocks := []map[string]interface{}{
+		{
+			"type": "header",
+			"text": map[string]interface{}{"type": "plain_text", "text": msg.Title, "emoji": true},
+		},
+		{
+			"type": "section",
+			"text": map[string]interface{}{"type": "mrkdwn", "text": msg.Text},
+		},
+	}
+	if len(msg.Fields) > 0 {
+		fields := make([]map[string]interface{}, 0, len(msg.Fields))
+		for _, f := range msg.Fields {
+			fields = append(fields, map[string]interface{}{"type": "mrkdwn", "text": "*" + f.Name + "*\n" + f.Value})
+		}
+		blocks = append(blocks, map[string]interface{}{"type": "section", "fields": fields})
+	}
+	return map[string]interface{}{"text": msg.Title + ": " + msg.Text, "blocks": blocks}
+}
+
+// teamsPayload は Microsoft Teams の MessageCard 形式のペイロードを作成します
+func teamsPayload(msg *Message) map[string]interface{} {
+	facts := make([]map[string]string, 0, len(msg.Fields))
+	for _, f := range msg.Fields {
+		facts = append(facts, map[string]string{"name": f.Name, "value": f.Value})
+	}
+	section := map[string]interface{}{"activityTitle": msg.Title, "text": msg.Text}
+	if len(facts) > 0 {
+		section["facts"] = facts
+	}
+
+	payload := map[string]interface{}{
+		"@type":    "MessageCard",
+		"@context": "https://schema.org/extensions",
+		"summary":  msg.Title,
+		"sections": []interface{}{section},
+	}
+	if msg.Color != "" {
+		payload["themeColor"] = trimHash(msg.Color)
+	}
+	return payload
+}
+
+// retryAfter は Retry-After ヘッダー (秒数または HTTP 日付) を解釈します
+func retryAfter(value string) time.Duration {
+	if value == "" {
+		return defaultRetryAfter
+	}
+	if secs, err := strconv.Atoi(value); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+		return 0
+	}
+	return defaultRetryAfter
+}
+
+func trimHash(color string) string {
+	if len(color) > 0 && color[0] == '#' {
+		return color[1:]
+	}
+	return color
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,140 @@
+package chat
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"my-portfolio-2025/internal/infrastructure/egress"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// localEgress は httptest のローカルサーバー (http://127.0.0.1) への投稿を許可します
+var localEgress = &egress.Guard{AllowHTTP: true, AllowPrivate: true}
+
+// newTestClient は待機時間を記録するだけで実際には待たない Client を作成します
+func newTestClient(waits *[]time.Duration) *Client {
+	c := NewClient(localEgress)
+	c.sleep = func(ctx context.Context, d time.Duration) error {
+		*waits = append(*waits, d)
+		return nil
+	}
+	return c
+}
+
+func TestClientPost_RetriesOnRateLimit(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "3")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var waits []time.Duration
+	err := newTestClient(&waits).Post(context.Background(), ProviderSlack, server.URL, &Message{Title: "t", Text: "x"})
+
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	assert.Equal(t, []time.Duration{3 * time.Second}, waits, "Retry-After に従って待つ")
+}
+
+func TestClientPost_GivesUp(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        int
+		retryAfter    string
+		expectedCalls int32
+	}{
+		{name: "429 が続く場合は最大回数で諦める", status: http.StatusTooManyRequests, retryAfter: "0", expectedCalls: maxAttempts},
+		{name: "Retry-After が長すぎる場合は待たない", status: http.StatusTooManyRequests, retryAfter: "3600", expectedCalls: 1},
+		{name: "4xx は再試行しない", status: http.StatusNotFound, expectedCalls: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&calls, 1)
+				if tt.retryAfter != "" {
+					w.Header().Set("Retry-After", tt.retryAfter)
+				}
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			var waits []time.Duration
+			err := newTestClient(&waits).Post(context.Background(), ProviderTeams, server.URL, &Message{Title: "t", Text: "x"})
+
+			assert.Error(t, err)
+			assert.Equal(t, tt.expectedCalls, atomic.LoadInt32(&calls))
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	msg := &Message{
+		Title:  "タスクの期限が近づいています",
+		Text:   "タスク「資料作成」の期限が近づいています",
+		Fields: []Field{{Name: "期限", Value: "2026/05/13 18:00"}},
+		Color:  "#E01E5A",
+	}
+
+	t.Run("Slack は Block Kit 形式", func(t *testing.T) {
+		raw, err := Render(ProviderSlack, msg)
+		require.NoError(t, err)
+
+		var payload struct {
+			Text   string `json:"text"`
+			Blocks []struct {
+				Type string `json:"type"`
+			} `json:"blocks"`
+		}
+		require.NoError(t, json.Unmarshal(raw, &payload))
+		assert.NotEmpty(t, payload.Text, "通知用のフォールバックテキストがある")
+		require.Len(t, payload.Blocks, 3)
+		assert.Equal(t, "header", payload.Blocks[0].Type)
+	})
+
+	t.Run("Teams は MessageCard 形式", func(t *testing.T) {
+		raw, err := Render(ProviderTeams, msg)
+		require.NoError(t, err)
+
+		var payload map[string]interface{}
+		require.NoError(t, json.Unmarshal(raw, &payload))
+		assert.Equal(t, "MessageCard", payload["@type"])
+		assert.Equal(t, "E01E5A", payload["themeColor"])
+	})
+
+	t.Run("未知のプロバイダーはエラー", func(t *testing.T) {
+		_, err := Render("discord", msg)
+		assert.Error(t, err)
+	})
+}
+
+// 投稿内容がそのまま受信側に届くことを確認する (ローカルの HTTP スタブ)
+func TestClientPost_SendsRenderedBody(t *testing.T) {
+	var got []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		got, _ = io.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	msg := &Message{Title: "t", Text: "x"}
+	require.NoError(t, NewClient(localEgress).Post(context.Background(), ProviderSlack, server.URL, msg))
+
+	expected, err := Render(ProviderSlack, msg)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(expected), string(got))
+}
@@ -0,0 +1,128 @@
+// Package egress はユーザーが指定した URL (Webhook・チャット連携) への送信が、
+// 内部ネットワークやメタデータエンドポイントに向かないよう検査します
+package egress
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+// ErrForbiddenAddress は送信先が内部アドレスであることを表します
+var ErrForbiddenAddress = errors.New("egress: destination address is not allowed")
+
+// Guard は送信先の URL とアドレスの検査ルールです
+// 本番では Default を使い、フィールドはテストでのみ変更します
+type Guard struct {
+	// AllowHTTP は http:// の URL を許可します (テストのローカルサーバー用)
+	AllowHTTP bool
+	// AllowPrivate はループバック・プライベートアドレスへの送信を許可します (テストのローカルサーバー用)
+	AllowPrivate bool
+	// LookupIPAddr はホスト名の解決です。nil の場合は net.DefaultResolver を使います
+	LookupIPAddr func(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+// Default は https のみを許可し、内部アドレスへの送信を禁止する既定のルールです
+var Default = &Guard{}
+
+// ValidateURL は URL が https の絶対 URL であり、ホストが内部アドレスに解決されないことを確認します
+// 登録時の検査です。DNS の応答が後から変わる (DNS rebinding) 場合に備え、
+// 送信時は NewHTTPClient のクライアントが接続先のアドレスを改めて検査します
+func (g *Guard) ValidateURL(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Hostname() == "" {
+		return errors.New("egress: url must be absolute")
+	}
+	if u.Scheme != "https" && !(g.AllowHTTP && u.Scheme == "http") {
+		return errors.New("egress: url must use https")
+	}
+
+	host := u.Hostname()
+	if ip, err := netip.ParseAddr(host); err == nil {
+		return g.checkAddr(ip)
+	}
+
+	lookup := g.LookupIPAddr
+	if lookup == nil {
+		lookup = net.DefaultResolver.LookupIPAddr
+	}
+	addrs, err := lookup(ctx, host)
+	if err != nil {
+		return fmt.Errorf("egress: resolve %s: %w", host, err)
+	}
+	if len(addrs) == 0 {
+		return fmt.Errorf("egress: resolve %s: no addresses", host)
+	}
+	// 1つでも内部アドレスが含まれていれば拒否する (どのアドレスに接続するかは選べないため)
+	for _, a := range addrs {
+		ip, ok := netip.AddrFromSlice(a.IP)
+		if !ok {
+			return fmt.Errorf("egress: resolve %s: invalid address %v", host, a.IP)
+		}
+		if err := g.checkAddr(ip); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NewHTTPClient は接続の直前に接続先アドレスを検査する HTTP クライアントを作成します
+// リダイレクトは追わず、3xx の応答をそのまま返します
+func (g *Guard) NewHTTPClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   timeout,
+		KeepAlive: 30 * time.Second,
+		Control:   g.control,
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// プロキシを経由すると検査の対象がプロキシのアドレスになるため使わない
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// control は名前解決後、実際に接続するアドレスを検査します (net.Dialer.Control)
+func (g *Guard) control(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("egress: parse dial address %q: %w", address, err)
+	}
+	return g.checkAddr(addrPort.Addr())
+}
+
+func (g *Guard) checkAddr(ip netip.Addr) error {
+	if g.AllowPrivate || IsPublicAddr(ip) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrForbiddenAddress, ip)
+}
+
+// IsPublicAddr はアドレスが外部への送信先として許可されるかを返します
+// ループバック・プライベート (RFC1918 / ULA)・リンクローカル (169.254.169.254 を含む)・未指定・マルチキャストは拒否します
+func IsPublicAddr(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	switch {
+	case !ip.IsValid(),
+		ip.IsLoopback(),
+		ip.IsPrivate(),
+		ip.IsLinkLocalUnicast(),
+		ip.IsLinkLocalMulticast(),
+		ip.IsUnspecified(),
+		ip.IsMulticast():
+		return false
+	}
+	return true
+}
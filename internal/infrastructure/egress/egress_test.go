@@ -0,0 +1,120 @@
+package egress
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// staticLookup は常に同じアドレスを返す名前解決です
+func staticLookup(ips ...string) func(ctx context.Context, host string) ([]net.IPAddr, error) {
+	return func(ctx context.Context, host string) ([]net.IPAddr, error) {
+		addrs := make([]net.IPAddr, 0, len(ips))
+		for _, ip := range ips {
+			addrs = append(addrs, net.IPAddr{IP: net.ParseIP(ip)})
+		}
+		return addrs, nil
+	}
+}
+
+func TestIsPublicAddr(t *testing.T) {
+	tests := []struct {
+		addr   string
+		public bool
+	}{
+		{addr: "93.184.216.34", public: true},
+		{addr: "2606:2800:220:1:248:1893:25c8:1946", public: true},
+		{addr: "127.0.0.1"},
+		{addr: "::1"},
+		{addr: "10.0.0.5"},
+		{addr: "172.16.0.1"},
+		{addr: "192.168.1.1"},
+		{addr: "169.254.169.254"},
+		{addr: "fd00:ec2::254"},
+		{addr: "fe80::1"},
+		{addr: "0.0.0.0"},
+		{addr: "::"},
+		{addr: "::ffff:127.0.0.1"},
+		{addr: "224.0.0.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			assert.Equal(t, tt.public, IsPublicAddr(netip.MustParseAddr(tt.addr)))
+		})
+	}
+}
+
+func TestValidateURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		lookup  []string
+		wantErr bool
+	}{
+		{name: "正常系：公開アドレスの https", url: "https://hooks.example.com/in", lookup: []string{"93.184.216.34"}},
+		{name: "異常系：http は不可", url: "http://hooks.example.com/in", lookup: []string{"93.184.216.34"}, wantErr: true},
+		{name: "異常系：相対 URL", url: "/hooks/in", wantErr: true},
+		{name: "異常系：メタデータエンドポイント", url: "https://169.254.169.254/latest/meta-data/", wantErr: true},
+		{name: "異常系：ループバック", url: "https://127.0.0.1:8080/", wantErr: true},
+		{name: "異常系：IPv6 ループバック", url: "https://[::1]/", wantErr: true},
+		{name: "異常系：プライベートアドレスに解決される", url: "https://internal.example.com/", lookup: []string{"10.0.1.20"}, wantErr: true},
+		{name: "異常系：一部が内部アドレスに解決される", url: "https://mixed.example.com/", lookup: []string{"93.184.216.34", "127.0.0.1"}, wantErr: true},
+		{name: "異常系：解決できない", url: "https://nowhere.example.com/", lookup: []string{}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &Guard{LookupIPAddr: staticLookup(tt.lookup...)}
+			err := g.ValidateURL(context.Background(), tt.url)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestNewHTTPClient_RejectsPrivateAddressAtDial(t *testing.T) {
+	var called bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	// 登録時の検査をすり抜けても (DNS rebinding など)、接続時に拒否される
+	_, err := Default.NewHTTPClient(time.Second).Get(server.URL)
+
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrForbiddenAddress))
+	assert.False(t, called)
+}
+
+func TestNewHTTPClient_DoesNotFollowRedirects(t *testing.T) {
+	var redirected bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/internal" {
+			redirected = true
+			return
+		}
+		http.Redirect(w, r, "/internal", http.StatusFound)
+	}))
+	defer server.Close()
+
+	g := &Guard{AllowHTTP: true, AllowPrivate: true}
+	resp, err := g.NewHTTPClient(time.Second).Get(server.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusFound, resp.StatusCode)
+	assert.False(t, redirected)
+}
@@ -0,0 +1,203 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"os"
+	"strings"
+	"time"
+)
+
+// Config は SMTP サーバーへの接続設定です
+type Config struct {
+	Host     string
+	Port     string
+	Username string // 空の場合は認証しない (ローカルの Mailpit など)
+	Password string
+	From     string // 送信元アドレス (例: "Kota Todo <noreply@example.com>")
+}
+
+// ConfigFromEnv は環境変数から SMTP 設定を読み込みます
+// SMTP_HOST が未設定の場合は ok=false を返し、メール送信は無効になります
+func ConfigFromEnv() (cfg Config, ok bool) {
+	cfg = Config{
+		Host:     os.Getenv("SMTP_HOST"),
+		Port:     os.Getenv("SMTP_PORT"),
+		Username: os.Getenv("SMTP_USERNAME"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+		From:     os.Getenv("SMTP_FROM"),
+	}
+	if cfg.Port == "" {
+		cfg.Port = "587"
+	}
+	return cfg, cfg.Host != "" && cfg.From != ""
+}
+
+// Message は送信するメールです。Text と HTML の両方を multipart/alternative で送ります
+type Message struct {
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Sender はメールを送信します
+type Sender interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+// SMTPSender は net/smtp を使った Sender の実装です
+type SMTPSender struct {
+	cfg Config
+}
+
+// NewSMTPSender は SMTPSender を作成します
+func NewSMTPSender(cfg Config) *SMTPSender {
+	return &SMTPSender{cfg: cfg}
+}
+
+// 接続から送信完了までのタイムアウト (context に期限が無い場合)
+const defaultSendTimeout = 30 * time.Second
+
+// Send はメールを1通送信します
+// サーバーが STARTTLS に対応していれば暗号化してから認証・送信します
+func (s *SMTPSender) Send(ctx context.Context, msg *Message) error {
+	from, err := mail.ParseAddress(s.cfg.From)
+	if err != nil {
+		return fmt.Errorf("SMTPSender.Send (from): %w", err)
+	}
+	to, err := mail.ParseAddress(msg.To)
+	if err != nil {
+		return fmt.Errorf("SMTPSender.Send (to): %w", err)
+	}
+
+	body, err := msg.build(from, to)
+	if err != nil {
+		return fmt.Errorf("SMTPSender.Send (build): %w", err)
+	}
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(defaultSendTimeout)
+	}
+	dialer := &net.Dialer{Deadline: deadline}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(s.cfg.Host, s.cfg.Port))
+	if err != nil {
+		return fmt.Errorf("SMTPSender.Send (dial): %w", err)
+	}
+	_ = conn.SetDeadline(deadline)
+
+	client, err := smtp.NewClient(conn, s.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("SMTPSender.Send (hello): %w", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: s.cfg.Host}); err != nil {
+			return fmt.Errorf("SMTPSender.Send (starttls): %w", err)
+		}
+	}
+	if s.cfg.Username != "" {
+		auth := smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, s.cfg.Host)
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("SMTPSender.Send (auth): %w", err)
+		}
+	}
+
+	if err := client.Mail(from.Address); err != nil {
+		return fmt.Errorf("SMTPSender.Send (MAIL FROM): %w", err)
+	}
+	if err := client.Rcpt(to.Address); err != nil {
+		return fmt.Errorf("SMTPSender.Send (RCPT TO): %w", err)
+	}
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("SMTPSender.Send (DATA): %w", err)
+	}
+	if _, err := w.Write(body); err != nil {
+		w.Close()
+		return fmt.Errorf("SMTPSender.Send (write): %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("SMTPSender.Send (end of data): %w", err)
+	}
+	return client.Quit()
+}
+
+// build は RFC 5322 形式のメール本文を組み立てます
+// 件名・表示名は UTF-8 の B エンコード、本文は UTF-8 の base64 で送ります (日本語の文字化け対策)
+func (m *Message) build(from, to *mail.Address) ([]byte, error) {
+	if m.Text == "" && m.HTML == "" {
+		return nil, errors.New("message has no body")
+	}
+
+	boundary, err := newBoundary()
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	writeHeader(&buf, "From", from.String())
+	writeHeader(&buf, "To", to.String())
+	writeHeader(&buf, "Subject", mime.BEncoding.Encode("UTF-8", m.Subject))
+	writeHeader(&buf, "Date", time.Now().Format(time.RFC1123Z))
+	writeHeader(&buf, "MIME-Version", "1.0")
+	writeHeader(&buf, "Content-Type", fmt.Sprintf("multipart/alternative; boundary=%q", boundary))
+	buf.WriteString("\r\n")
+
+	for _, part := range []struct{ contentType, body string }{
+		{"text/plain; charset=UTF-8", m.Text},
+		{"text/html; charset=UTF-8", m.HTML},
+	} {
+		if part.body == "" {
+			continue
+		}
+		fmt.Fprintf(&buf, "--%s\r\n", boundary)
+		writeHeader(&buf, "Content-Type", part.contentType)
+		writeHeader(&buf, "Content-Transfer-Encoding", "base64")
+		buf.WriteString("\r\n")
+		writeBase64Lines(&buf, []byte(part.body))
+	}
+	fmt.Fprintf(&buf, "--%s--\r\n", boundary)
+
+	return buf.Bytes(), nil
+}
+
+func writeHeader(buf *bytes.Buffer, key, value string) {
+	// ヘッダーインジェクション対策として改行を除去する
+	value = strings.NewReplacer("\r", "", "\n", "").Replace(value)
+	fmt.Fprintf(buf, "%s: %s\r\n", key, value)
+}
+
+// writeBase64Lines は base64 を RFC 2045 の行長 (76文字) で折り返して書き込みます
+func writeBase64Lines(buf *bytes.Buffer, data []byte) {
+	const lineLen = 76
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for len(encoded) > lineLen {
+		buf.WriteString(encoded[:lineLen])
+		buf.WriteString("\r\n")
+		encoded = encoded[lineLen:]
+	}
+	buf.WriteString(encoded)
+	buf.WriteString("\r\n")
+}
+
+func newBoundary() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "kota-" + hex.EncodeToString(b), nil
+}
@@ -0,0 +1,77 @@
+package mail
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"strings"
+	"testing"
+
+	"my-portfolio-2025/internal/testutils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSMTPSender_Send(t *testing.T) {
+	server := testutils.NewFakeSMTPServer(t)
+	host, port, err := net.SplitHostPort(server.Addr)
+	require.NoError(t, err)
+
+	sender := NewSMTPSender(Config{Host: host, Port: port, From: "Kota Todo <noreply@example.com>"})
+	msg := &Message{
+		To:      "user@example.com",
+		Subject: "【期限間近】タスクのお知らせ",
+		Text:    "タスク「資料作成」の期限が近づいています",
+		HTML:    "<p>タスク「資料作成」の期限が近づいています</p>",
+	}
+
+	require.NoError(t, sender.Send(context.Background(), msg))
+
+	mails := server.Mails()
+	require.Len(t, mails, 1)
+	assert.Equal(t, "noreply@example.com", mails[0].From)
+	assert.Equal(t, []string{"user@example.com"}, mails[0].To)
+
+	parsed, err := mail.ReadMessage(strings.NewReader(mails[0].Data))
+	require.NoError(t, err)
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, msg.Subject, subject, "件名は UTF-8 でデコードできる")
+
+	mediaType, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	assert.Equal(t, "multipart/alternative", mediaType)
+
+	bodies := map[string]string{}
+	mr := multipart.NewReader(parsed.Body, params["boundary"])
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		raw, err := io.ReadAll(base64.NewDecoder(base64.StdEncoding, part))
+		require.NoError(t, err)
+		partType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		bodies[partType] = string(raw)
+	}
+	assert.Equal(t, msg.Text, bodies["text/plain"])
+	assert.Equal(t, msg.HTML, bodies["text/html"])
+}
+
+func TestMessageBuild_RejectsHeaderInjection(t *testing.T) {
+	msg := &Message{Subject: "hello\r\nBcc: evil@example.com", Text: "body"}
+	from := &mail.Address{Address: "noreply@example.com"}
+	to := &mail.Address{Address: "user@example.com"}
+
+	raw, err := msg.build(from, to)
+
+	require.NoError(t, err)
+	assert.NotContains(t, string(raw), "\r\nBcc:")
+}
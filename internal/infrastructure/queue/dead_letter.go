@@ -0,0 +1,66 @@
+// internal/infrastructure/queue/dead_letter.go
+package queue
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	appaws "my-portfolio-2025/internal/infrastructure/aws"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// DLQ の受信で待つ時間。短いポーリング (0秒) は一部のサーバーしか見ないため、メッセージがあっても空で返ることがある
+const deadLetterWaitTimeSeconds = 1
+
+// DeadLetterQueue はワーカーが処理できなかったメッセージが送られる DLQ です (管理用)
+// 受信・削除・可視性タイムアウトの変更は Queue と同じで、受信はメッセージが無ければ待たずに返ります
+type DeadLetterQueue interface {
+	Receive(ctx context.Context, max int, visibility time.Duration) ([]Message, error)
+	Ack(ctx context.Context, msgs []Message) error
+	Nack(ctx context.Context, msg Message, delay time.Duration) error
+
+	// Count は DLQ にあるメッセージのおおよその数を返します
+	Count(ctx context.Context) (int, error)
+	// Purge は DLQ のメッセージをすべて削除します
+	Purge(ctx context.Context) error
+}
+
+// sqsDeadLetterQueue は SQS の DLQ です (redrive policy の deadLetterTargetArn のキュー)
+type sqsDeadLetterQueue struct {
+	sqsQueue
+}
+
+// NewSQSDeadLetterQueue は SQS の DLQ を操作する DeadLetterQueue を作成します
+func NewSQSDeadLetterQueue(client *appaws.SQSClient) DeadLetterQueue {
+	return &sqsDeadLetterQueue{sqsQueue{client: client}}
+}
+
+func (q *sqsDeadLetterQueue) Receive(ctx context.Context, max int, visibility time.Duration) ([]Message, error) {
+	return q.receive(ctx, max, visibility, deadLetterWaitTimeSeconds)
+}
+
+func (q *sqsDeadLetterQueue) Count(ctx context.Context) (int, error) {
+	output, err := q.client.Client.GetQueueAttributes(ctx, &sqs.GetQueueAttributesInput{
+		QueueUrl:       aws.String(q.client.QueueUrl),
+		AttributeNames: []types.QueueAttributeName{types.QueueAttributeNameApproximateNumberOfMessages},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("sqsDeadLetterQueue.Count: %w", err)
+	}
+	n, _ := strconv.Atoi(output.Attributes[string(types.QueueAttributeNameApproximateNumberOfMessages)])
+	return n, nil
+}
+
+// Purge は PurgeQueue でメッセージを削除します (SQS の制限により 60 秒に1回まで)
+func (q *sqsDeadLetterQueue) Purge(ctx context.Context) error {
+	_, err := q.client.Client.PurgeQueue(ctx, &sqs.PurgeQueueInput{QueueUrl: aws.String(q.client.QueueUrl)})
+	if err != nil {
+		return fmt.Errorf("sqsDeadLetterQueue.Purge: %w", err)
+	}
+	return nil
+}
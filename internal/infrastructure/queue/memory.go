@@ -0,0 +1,174 @@
+// internal/infrastructure/queue/memory.go
+package queue
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// memoryQueue はプロセス内のメモリに保持する Queue です
+// プロセスが終了するとメッセージは失われるため、テストと1プロセスでの開発用に使います
+type memoryQueue struct {
+	mu     sync.Mutex
+	nextID int
+	items  []*memoryItem
+	// dead は DeadLetter で移したメッセージ。受信されない
+	dead []*memoryItem
+	// notify は Enqueue・Nack で受信を待っている Receive を起こす
+	notify chan struct{}
+	// now はテストで時刻を差し替えるためのもの
+	now func() time.Time
+}
+
+type memoryItem struct {
+	id           string
+	body         string
+	visibleAt    time.Time
+	receiveCount int
+	// receipt は最後に受信したときのハンドル。再受信されると前回のハンドルでは操作できない
+	receipt string
+}
+
+// NewMemoryQueue はメモリ上の Queue を作成します
+func NewMemoryQueue() Queue {
+	return &memoryQueue{notify: make(chan struct{}, 1), now: time.Now}
+}
+
+func (q *memoryQueue) Enqueue(ctx context.Context, body []byte, delay time.Duration) error {
+	q.mu.Lock()
+	q.nextID++
+	q.items = append(q.items, &memoryItem{
+		id:        strconv.Itoa(q.nextID),
+		body:      string(body),
+		visibleAt: q.now().Add(delay),
+	})
+	q.mu.Unlock()
+	q.wake()
+	return nil
+}
+
+// wake は受信を待っている Receive を起こします
+func (q *memoryQueue) wake() {
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (q *memoryQueue) Receive(ctx context.Context, max int, visibility time.Duration) ([]Message, error) {
+	visibility = visibilityOrDefault(visibility)
+	timeout := time.NewTimer(receiveWaitTime)
+	defer timeout.Stop()
+
+	for {
+		msgs, next := q.take(max, visibility)
+		if len(msgs) > 0 {
+			return msgs, nil
+		}
+
+		// 遅延中のメッセージが見えるようになるか、新しいメッセージが届くまで待つ
+		var wait <-chan time.Time
+		if !next.IsZero() {
+			wait = time.After(next.Sub(q.now()))
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-timeout.C:
+			return nil, nil
+		case <-q.notify:
+		case <-wait:
+		}
+	}
+}
+
+// take は受信できるメッセージを最大 max 件取り出し、visibility の間見えなくします
+// 受信できるメッセージが無い場合は、次に見えるようになる時刻を返す
+func (q *memoryQueue) take(max int, visibility time.Duration) ([]Message, time.Time) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := q.now()
+	var msgs []Message
+	var next time.Time
+	for _, item := range q.items {
+		if item.visibleAt.After(now) {
+			if next.IsZero() || item.visibleAt.Before(next) {
+				next = item.visibleAt
+			}
+			continue
+		}
+		if len(msgs) == max {
+			break
+		}
+		item.visibleAt = now.Add(visibility)
+		item.receiveCount++
+		item.receipt = item.id + ":" + uuid.NewString()
+		msgs = append(msgs, Message{ID: item.id, Handle: item.receipt, Body: item.body, ReceiveCount: item.receiveCount})
+	}
+	return msgs, next
+}
+
+// find は受信ハンドルに対応するメッセージの位置を返します。再受信などでハンドルが無効な場合は -1
+func (q *memoryQueue) find(handle string) int {
+	for i, item := range q.items {
+		if item.receipt == handle {
+			return i
+		}
+	}
+	return -1
+}
+
+func (q *memoryQueue) Ack(ctx context.Context, msgs []Message) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, msg := range msgs {
+		if i := q.find(msg.Handle); i >= 0 {
+			q.items = append(q.items[:i], q.items[i+1:]...)
+		}
+	}
+	return nil
+}
+
+func (q *memoryQueue) Nack(ctx context.Context, msg Message, delay time.Duration) error {
+	if err := q.setVisibleAt(msg, delay); err != nil {
+		return fmt.Errorf("memoryQueue.Nack: %w", err)
+	}
+	q.wake()
+	return nil
+}
+
+func (q *memoryQueue) Extend(ctx context.Context, msg Message, timeout time.Duration) error {
+	if err := q.setVisibleAt(msg, timeout); err != nil {
+		return fmt.Errorf("memoryQueue.Extend: %w", err)
+	}
+	return nil
+}
+
+func (q *memoryQueue) DeadLetter(ctx context.Context, msg Message) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	i := q.find(msg.Handle)
+	if i < 0 {
+		return fmt.Errorf("memoryQueue.DeadLetter: message %s: receipt handle is no longer valid", msg.ID)
+	}
+	q.dead = append(q.dead, q.items[i])
+	q.items = append(q.items[:i], q.items[i+1:]...)
+	return nil
+}
+
+func (q *memoryQueue) setVisibleAt(msg Message, after time.Duration) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	i := q.find(msg.Handle)
+	if i < 0 {
+		return fmt.Errorf("message %s: receipt handle is no longer valid", msg.ID)
+	}
+	q.items[i].visibleAt = q.now().Add(after)
+	return nil
+}
@@ -0,0 +1,135 @@
+package queue
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestMemoryQueue は時刻を進められる memoryQueue を作成します
+func newTestMemoryQueue() (*memoryQueue, *time.Time) {
+	q := NewMemoryQueue().(*memoryQueue)
+	now := time.Date(2026, 5, 12, 9, 0, 0, 0, time.UTC)
+	q.now = func() time.Time { return now }
+	return q, &now
+}
+
+func TestMemoryQueue_RedeliversAfterVisibilityTimeout(t *testing.T) {
+	q, now := newTestMemoryQueue()
+	ctx := context.Background()
+	require.NoError(t, q.Enqueue(ctx, []byte(`{"type":"notification"}`), 0))
+
+	first, err := q.Receive(ctx, 10, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, first, 1)
+	assert.Equal(t, `{"type":"notification"}`, first[0].Body)
+	assert.Equal(t, 1, first[0].ReceiveCount)
+
+	// 可視性タイムアウトの間は他の受信者に返さない
+	msgs, _ := q.take(10, time.Minute)
+	assert.Empty(t, msgs)
+
+	// Ack しないまま可視性タイムアウトが過ぎると再配信する
+	*now = now.Add(time.Minute)
+	second, err := q.Receive(ctx, 10, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, second, 1)
+	assert.Equal(t, first[0].ID, second[0].ID)
+	assert.Equal(t, 2, second[0].ReceiveCount)
+
+	// 前回の受信ハンドルでは操作できない
+	assert.Error(t, q.Extend(ctx, first[0], time.Minute))
+	require.NoError(t, q.Ack(ctx, first))
+	assert.Len(t, q.items, 1, "古いハンドルの Ack では削除しない")
+
+	require.NoError(t, q.Ack(ctx, second))
+	assert.Empty(t, q.items)
+}
+
+func TestMemoryQueue_DelayAndNack(t *testing.T) {
+	q, now := newTestMemoryQueue()
+	ctx := context.Background()
+	require.NoError(t, q.Enqueue(ctx, []byte("later"), 30*time.Second))
+
+	msgs, next := q.take(10, time.Minute)
+	assert.Empty(t, msgs, "delay が経過するまで受信しない")
+	assert.Equal(t, now.Add(30*time.Second), next)
+
+	*now = now.Add(30 * time.Second)
+	msgs, err := q.Receive(ctx, 10, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, msgs, 1)
+
+	// Nack すると delay 後に再受信できる
+	require.NoError(t, q.Nack(ctx, msgs[0], 10*time.Second))
+	empty, _ := q.take(10, time.Minute)
+	assert.Empty(t, empty)
+
+	*now = now.Add(10 * time.Second)
+	again, err := q.Receive(ctx, 10, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, again, 1)
+	assert.Equal(t, 2, again[0].ReceiveCount)
+}
+
+func TestMemoryQueue_ReceiveRespectsMax(t *testing.T) {
+	q, _ := newTestMemoryQueue()
+	ctx := context.Background()
+	for range 3 {
+		require.NoError(t, q.Enqueue(ctx, []byte("job"), 0))
+	}
+
+	msgs, err := q.Receive(ctx, 2, 0)
+	require.NoError(t, err)
+	assert.Len(t, msgs, 2)
+
+	rest, err := q.Receive(ctx, 2, 0)
+	require.NoError(t, err)
+	assert.Len(t, rest, 1)
+}
+
+func TestMemoryQueue_ReceiveWaitsForEnqueue(t *testing.T) {
+	q := NewMemoryQueue()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	received := make(chan []Message, 1)
+	go func() {
+		msgs, _ := q.Receive(ctx, 1, time.Minute)
+		received <- msgs
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	require.NoError(t, q.Enqueue(ctx, []byte("job"), 0))
+
+	select {
+	case msgs := <-received:
+		assert.Len(t, msgs, 1)
+	case <-ctx.Done():
+		t.Fatal("Enqueue しても待機中の Receive が起きない")
+	}
+}
+
+func TestMemoryQueue_DeadLetter(t *testing.T) {
+	q, now := newTestMemoryQueue()
+	ctx := context.Background()
+	require.NoError(t, q.Enqueue(ctx, []byte("poison"), 0))
+
+	msgs, err := q.Receive(ctx, 10, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, msgs, 1)
+	require.NoError(t, q.DeadLetter(ctx, msgs[0]))
+
+	// デッドレターへ移したメッセージは可視性タイムアウト後も受信しない
+	*now = now.Add(time.Hour)
+	rest, _ := q.take(10, time.Minute)
+	assert.Empty(t, rest)
+	require.Len(t, q.dead, 1)
+	assert.Equal(t, "poison", q.dead[0].body)
+
+	// 移した後のハンドルでは操作できない
+	assert.Error(t, q.DeadLetter(ctx, msgs[0]))
+}
@@ -0,0 +1,185 @@
+// internal/infrastructure/queue/postgres.go
+package queue
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// メッセージが無いときに Postgres のジョブテーブルを確認する間隔
+const postgresPollInterval = time.Second
+
+// PostgresMessage は Postgres のジョブテーブル (queue_messages) の行です
+// 1つのテーブルに複数のキューを name で分けて保存します
+type PostgresMessage struct {
+	ID           int64     `gorm:"primaryKey;autoIncrement"`
+	Queue        string    `gorm:"size:100;not null;index:idx_queue_messages_visible,priority:1"`
+	Body         string    `gorm:"type:text;not null"`
+	ReceiveCount int       `gorm:"not null;default:0"`
+	Receipt      string    `gorm:"size:36"` // 最後に受信したときの受信ハンドル
+	VisibleAt    time.Time `gorm:"not null;index:idx_queue_messages_visible,priority:2"`
+	CreatedAt    time.Time `gorm:"not null"`
+}
+
+func (PostgresMessage) TableName() string {
+	return "queue_messages"
+}
+
+// postgresQueue は Postgres のジョブテーブルを使う Queue です
+// 受信は FOR UPDATE SKIP LOCKED で行うため、複数のワーカーが同時に受信しても同じメッセージを受け取ることはありません
+type postgresQueue struct {
+	db   *gorm.DB
+	name string
+}
+
+// NewPostgresQueue は Postgres のジョブテーブルを使う Queue を作成します (テーブルは PostgresMessage のマイグレーションで作成する)
+func NewPostgresQueue(db *gorm.DB, name string) Queue {
+	return &postgresQueue{db: db, name: name}
+}
+
+func (q *postgresQueue) Enqueue(ctx context.Context, body []byte, delay time.Duration) error {
+	now := time.Now()
+	msg := &PostgresMessage{Queue: q.name, Body: string(body), VisibleAt: now.Add(delay), CreatedAt: now}
+	if err := q.db.WithContext(ctx).Create(msg).Error; err != nil {
+		return fmt.Errorf("postgresQueue.Enqueue: %w", err)
+	}
+	return nil
+}
+
+func (q *postgresQueue) Receive(ctx context.Context, max int, visibility time.Duration) ([]Message, error) {
+	visibility = visibilityOrDefault(visibility)
+	deadline := time.Now().Add(receiveWaitTime)
+
+	for {
+		msgs, err := q.take(ctx, max, visibility)
+		if err != nil || len(msgs) > 0 || time.Now().After(deadline) {
+			return msgs, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(postgresPollInterval):
+		}
+	}
+}
+
+// take は受信できるメッセージを最大 max 件ロックして、visibility の間見えなくします
+// 受信ハンドルは受信ごとに変わるため、可視性タイムアウト後に他のワーカーが受信したメッセージを前の受信者は操作できない
+func (q *postgresQueue) take(ctx context.Context, max int, visibility time.Duration) ([]Message, error) {
+	now := time.Now()
+	receipt := uuid.NewString()
+
+	var rows []PostgresMessage
+	err := q.db.WithContext(ctx).Raw(`
+		UPDATE queue_messages
+		SET visible_at = ?, receive_count = receive_count + 1, receipt = ?
+		WHERE id IN (
+			SELECT id FROM queue_messages
+			WHERE queue = ? AND visible_at <= ?
+			ORDER BY id
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`,
+		now.Add(visibility), receipt, q.name, now, max,
+	).Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("postgresQueue.Receive: %w", err)
+	}
+
+	msgs := make([]Message, len(rows))
+	for i, row := range rows {
+		id := strconv.FormatInt(row.ID, 10)
+		msgs[i] = Message{ID: id, Handle: id + ":" + row.Receipt, Body: row.Body, ReceiveCount: row.ReceiveCount}
+	}
+	return msgs, nil
+}
+
+// parseHandle は受信ハンドルをメッセージIDと受信ごとの receipt に分けます
+func parseHandle(handle string) (int64, string, error) {
+	idPart, receipt, ok := strings.Cut(handle, ":")
+	id, err := strconv.ParseInt(idPart, 10, 64)
+	if !ok || err != nil {
+		return 0, "", fmt.Errorf("invalid receipt handle %q", handle)
+	}
+	return id, receipt, nil
+}
+
+func (q *postgresQueue) Ack(ctx context.Context, msgs []Message) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	tx := q.db.WithContext(ctx)
+	cond := tx.Where("1 = 0")
+	for _, msg := range msgs {
+		id, receipt, err := parseHandle(msg.Handle)
+		if err != nil {
+			return fmt.Errorf("postgresQueue.Ack: %w", err)
+		}
+		cond = cond.Or("id = ? AND receipt = ?", id, receipt)
+	}
+	if err := tx.Where(cond).Delete(&PostgresMessage{}).Error; err != nil {
+		return fmt.Errorf("postgresQueue.Ack: %w", err)
+	}
+	return nil
+}
+
+func (q *postgresQueue) Nack(ctx context.Context, msg Message, delay time.Duration) error {
+	if err := q.setVisibleAt(ctx, msg, delay); err != nil {
+		return fmt.Errorf("postgresQueue.Nack: %w", err)
+	}
+	return nil
+}
+
+func (q *postgresQueue) Extend(ctx context.Context, msg Message, timeout time.Duration) error {
+	if err := q.setVisibleAt(ctx, msg, timeout); err != nil {
+		return fmt.Errorf("postgresQueue.Extend: %w", err)
+	}
+	return nil
+}
+
+// DeadLetter はメッセージの queue 列をデッドレターのキュー名 (name + ":dead") に変えます
+// 本文と受信回数はそのまま残るため、調査後に queue 列を戻せば再処理できる
+func (q *postgresQueue) DeadLetter(ctx context.Context, msg Message) error {
+	id, receipt, err := parseHandle(msg.Handle)
+	if err != nil {
+		return fmt.Errorf("postgresQueue.DeadLetter: %w", err)
+	}
+	result := q.db.WithContext(ctx).
+		Model(&PostgresMessage{}).
+		Where("id = ? AND receipt = ? AND queue = ?", id, receipt, q.name).
+		Update("queue", deadLetterName(q.name))
+	if result.Error != nil {
+		return fmt.Errorf("postgresQueue.DeadLetter: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("postgresQueue.DeadLetter: message %s: receipt handle is no longer valid", msg.ID)
+	}
+	return nil
+}
+
+func (q *postgresQueue) setVisibleAt(ctx context.Context, msg Message, after time.Duration) error {
+	id, receipt, err := parseHandle(msg.Handle)
+	if err != nil {
+		return err
+	}
+	result := q.db.WithContext(ctx).
+		Model(&PostgresMessage{}).
+		Where("id = ? AND receipt = ?", id, receipt).
+		Update("visible_at", time.Now().Add(after))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("message %s: receipt handle is no longer valid", msg.ID)
+	}
+	return nil
+}
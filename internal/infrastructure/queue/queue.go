@@ -0,0 +1,76 @@
+// internal/infrastructure/queue/queue.go
+package queue
+
+import (
+	"context"
+	"time"
+)
+
+// バックエンドの種類 (QUEUE_BACKEND)
+const (
+	BackendSQS      = "sqs"      // Amazon SQS (本番)
+	BackendRedis    = "redis"    // Redis Streams のコンシューマーグループ
+	BackendPostgres = "postgres" // Postgres のジョブテーブル (FOR UPDATE SKIP LOCKED)
+	BackendMemory   = "memory"   // プロセス内のメモリ (テスト・1プロセスでの開発用)
+)
+
+const (
+	// SQS 以外のバックエンドで、受信時に可視性タイムアウトを指定しなかった場合の既定値 (infra/sqs.tf のキューに合わせる)
+	defaultVisibilityTimeout = 60 * time.Second
+	// SQS 以外のバックエンドで、メッセージが無いときに Receive が待つ最大時間 (SQS のロングポーリングに合わせる)
+	receiveWaitTime = 20 * time.Second
+)
+
+// visibilityOrDefault は可視性タイムアウトが未指定なら既定値を返します
+func visibilityOrDefault(visibility time.Duration) time.Duration {
+	if visibility <= 0 {
+		return defaultVisibilityTimeout
+	}
+	return visibility
+}
+
+// Message は受信したメッセージです
+type Message struct {
+	// ID はメッセージID (ログ用)
+	ID string
+	// Handle は Ack / Nack / Extend に渡す受信ハンドル。再受信されると前回のハンドルは無効になる場合がある
+	Handle string
+	Body   string
+	// ReceiveCount はこのメッセージを受信した回数 (1 始まり)
+	ReceiveCount int
+}
+
+// Queue はワーカーが使うジョブキューです
+// 受信したメッセージは可視性タイムアウトの間、他の受信者に返されません。
+// Ack しないまま可視性タイムアウトが過ぎると再び受信できるようになるため、メッセージは少なくとも1回 (at-least-once) 処理されます
+type Queue interface {
+	// Enqueue はメッセージを送信します。delay を指定すると、その時間が経過するまで受信されません
+	Enqueue(ctx context.Context, body []byte, delay time.Duration) error
+
+	// Receive は最大 max 件のメッセージを受信し、visibility の間は他の受信者から見えなくします
+	// visibility が 0 の場合はバックエンドの既定値 (SQS はキューの設定、それ以外は defaultVisibilityTimeout) を使う
+	// メッセージが無い場合はしばらく待ち (ロングポーリング)、それでも無ければ空で返します
+	Receive(ctx context.Context, max int, visibility time.Duration) ([]Message, error)
+
+	// Ack は処理を終えたメッセージを削除します
+	Ack(ctx context.Context, msgs []Message) error
+
+	// Nack は処理に失敗したメッセージを delay 後に再び受信できるようにします (0 の場合はすぐ)
+	Nack(ctx context.Context, msg Message, delay time.Duration) error
+
+	// Extend は処理中のメッセージの可視性タイムアウトを、今から timeout 後まで延長します
+	Extend(ctx context.Context, msg Message, timeout time.Duration) error
+}
+
+// DeadLetterer は処理できないメッセージを削除せずにデッドレターへ移せる Queue です
+// SQS は redrive policy で DLQ へ移すため実装しません。SQS 以外のバックエンドは、
+// 同じ名前に ":dead" を付けたキュー (Postgres は queue 列、Redis はストリーム) へ移します
+type DeadLetterer interface {
+	// DeadLetter はメッセージをキューから取り除き、デッドレターへ移します。移したメッセージは Receive で受信されない
+	DeadLetter(ctx context.Context, msg Message) error
+}
+
+// deadLetterName はデッドレターのキュー名です
+func deadLetterName(name string) string {
+	return name + ":dead"
+}
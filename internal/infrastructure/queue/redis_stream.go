@@ -0,0 +1,285 @@
+// internal/infrastructure/queue/redis_stream.go
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// すべてのワーカーが参加するコンシューマーグループ
+	redisStreamGroup = "workers"
+	// XREADGROUP で新しいメッセージを待つ時間。遅延メッセージの移動と期限切れの回収はこの間隔で行う
+	redisStreamBlock = time.Second
+	// 1回の Receive で遅延 zset からストリームへ移すメッセージの上限
+	redisPromoteBatchSize = 100
+)
+
+// 期限が来た遅延メッセージを zset からストリームへ移す (複数のワーカーが同じメッセージを移さないよう Lua で実行する)
+var promoteDelayedScript = redis.NewScript(`
+local items = redis.call("ZRANGEBYSCORE", KEYS[1], "-inf", ARGV[1], "LIMIT", 0, ARGV[2])
+for _, item in ipairs(items) do
+	redis.call("ZREM", KEYS[1], item)
+	local m = cjson.decode(item)
+	redis.call("XADD", KEYS[2], "*", "body", m.body, "receives", m.receives)
+end
+return #items`)
+
+// 保留中のメッセージを自分が受信している場合だけアイドル時間を 0 に戻す (他のワーカーが回収したメッセージを取り返さないため)
+var extendPendingScript = redis.NewScript(`
+local p = redis.call("XPENDING", KEYS[1], ARGV[1], ARGV[3], ARGV[3], 1)
+if #p == 0 or p[1][2] ~= ARGV[2] then
+	return 0
+end
+redis.call("XCLAIM", KEYS[1], ARGV[1], ARGV[2], 0, ARGV[3], "JUSTID")
+return 1`)
+
+// delayedMessage は遅延 zset のメンバーです (同じ本文のメッセージが重複しないよう ID を持つ)
+type delayedMessage struct {
+	ID       string `json:"id"`
+	Body     string `json:"body"`
+	Receives int    `json:"receives"`
+}
+
+// redisStreamQueue は Redis Streams のコンシューマーグループを使う Queue です
+//
+// 受信したメッセージはグループの保留リスト (PEL) に残り、Ack で XACK・XDEL されます。
+// 可視性タイムアウトは保留中のアイドル時間で表し、visibility より長くアイドルなメッセージは次の Receive で XCLAIM して再配信します。
+// ストリームには遅延配信が無いため、delay 付きのメッセージは zset (score は配信時刻) に置き、期限が来たら Receive でストリームへ移します
+type redisStreamQueue struct {
+	rdb      *redis.Client
+	stream   string
+	delayed  string
+	dead     string
+	consumer string
+	// groupReady はコンシューマーグループを作成済みかどうか
+	groupReady atomic.Bool
+}
+
+// NewRedisStreamQueue は Redis Streams を使う Queue を作成します
+func NewRedisStreamQueue(rdb *redis.Client, name string) Queue {
+	host, _ := os.Hostname()
+	return &redisStreamQueue{
+		rdb:      rdb,
+		stream:   "queue:" + name,
+		delayed:  "queue:" + name + ":delayed",
+		dead:     "queue:" + deadLetterName(name),
+		consumer: host + "-" + uuid.NewString(),
+	}
+}
+
+func (q *redisStreamQueue) Enqueue(ctx context.Context, body []byte, delay time.Duration) error {
+	if err := q.add(ctx, q.rdb, string(body), 0, delay); err != nil {
+		return fmt.Errorf("redisStreamQueue.Enqueue: %w", err)
+	}
+	return nil
+}
+
+// add はメッセージをストリーム、delay がある場合は遅延 zset に追加します
+// receives はこれまでの受信回数で、Nack で追加し直したときも受信回数を引き継ぐために使う
+func (q *redisStreamQueue) add(ctx context.Context, c redis.Cmdable, body string, receives int, delay time.Duration) error {
+	if delay <= 0 {
+		return c.XAdd(ctx, &redis.XAddArgs{
+			Stream: q.stream,
+			Values: map[string]any{"body": body, "receives": receives},
+		}).Err()
+	}
+
+	member, err := json.Marshal(delayedMessage{ID: uuid.NewString(), Body: body, Receives: receives})
+	if err != nil {
+		return err
+	}
+	return c.ZAdd(ctx, q.delayed, redis.Z{
+		Score:  float64(time.Now().Add(delay).UnixMilli()),
+		Member: string(member),
+	}).Err()
+}
+
+// ensureGroup はコンシューマーグループが無ければストリームと一緒に作成します
+func (q *redisStreamQueue) ensureGroup(ctx context.Context) error {
+	if q.groupReady.Load() {
+		return nil
+	}
+	err := q.rdb.XGroupCreateMkStream(ctx, q.stream, redisStreamGroup, "0").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return err
+	}
+	q.groupReady.Store(true)
+	return nil
+}
+
+func (q *redisStreamQueue) Receive(ctx context.Context, max int, visibility time.Duration) ([]Message, error) {
+	visibility = visibilityOrDefault(visibility)
+	if err := q.ensureGroup(ctx); err != nil {
+		return nil, fmt.Errorf("redisStreamQueue.Receive: %w", err)
+	}
+
+	deadline := time.Now().Add(receiveWaitTime)
+	for {
+		msgs, err := q.receive(ctx, max, visibility)
+		if err != nil {
+			return nil, fmt.Errorf("redisStreamQueue.Receive: %w", err)
+		}
+		if len(msgs) > 0 || time.Now().After(deadline) {
+			return msgs, nil
+		}
+	}
+}
+
+// receive は期限の来た遅延メッセージを移し、可視性タイムアウトの過ぎたメッセージを回収してから新しいメッセージを待ちます
+func (q *redisStreamQueue) receive(ctx context.Context, max int, visibility time.Duration) ([]Message, error) {
+	err := promoteDelayedScript.Run(ctx, q.rdb, []string{q.delayed, q.stream},
+		time.Now().UnixMilli(), redisPromoteBatchSize).Err()
+	if err != nil {
+		return nil, fmt.Errorf("promote delayed messages: %w", err)
+	}
+
+	msgs, err := q.reclaim(ctx, max, visibility)
+	if err != nil || len(msgs) > 0 {
+		return msgs, err
+	}
+
+	streams, err := q.rdb.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    redisStreamGroup,
+		Consumer: q.consumer,
+		Streams:  []string{q.stream, ">"},
+		Count:    int64(max),
+		Block:    redisStreamBlock,
+	}).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, s := range streams {
+		for _, m := range s.Messages {
+			msgs = append(msgs, q.message(m, 1))
+		}
+	}
+	return msgs, nil
+}
+
+// reclaim は visibility より長く Ack されていないメッセージを、このコンシューマーに付け替えて返します
+// XCLAIM に MinIdle を指定するため、同時に他のワーカーが回収したメッセージは返らない
+func (q *redisStreamQueue) reclaim(ctx context.Context, max int, visibility time.Duration) ([]Message, error) {
+	pending, err := q.rdb.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream: q.stream,
+		Group:  redisStreamGroup,
+		Idle:   visibility,
+		Start:  "-",
+		End:    "+",
+		Count:  int64(max),
+	}).Result()
+	if err != nil || len(pending) == 0 {
+		return nil, err
+	}
+
+	ids := make([]string, len(pending))
+	deliveries := make(map[string]int, len(pending))
+	for i, p := range pending {
+		ids[i] = p.ID
+		deliveries[p.ID] = int(p.RetryCount) + 1
+	}
+
+	claimed, err := q.rdb.XClaim(ctx, &redis.XClaimArgs{
+		Stream:   q.stream,
+		Group:    redisStreamGroup,
+		Consumer: q.consumer,
+		MinIdle:  visibility,
+		Messages: ids,
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("claim idle messages: %w", err)
+	}
+
+	msgs := make([]Message, 0, len(claimed))
+	for _, m := range claimed {
+		msgs = append(msgs, q.message(m, deliveries[m.ID]))
+	}
+	return msgs, nil
+}
+
+// message はストリームのエントリーを Message にします
+// 受信回数は Nack で追加し直す前の受信回数 (receives) と、このエントリーの配信回数の合計
+func (q *redisStreamQueue) message(m redis.XMessage, deliveries int) Message {
+	body, _ := m.Values["body"].(string)
+	receives, _ := strconv.Atoi(fmt.Sprint(m.Values["receives"]))
+	return Message{ID: m.ID, Handle: m.ID, Body: body, ReceiveCount: receives + deliveries}
+}
+
+func (q *redisStreamQueue) Ack(ctx context.Context, msgs []Message) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+	ids := make([]string, len(msgs))
+	for i, m := range msgs {
+		ids[i] = m.Handle
+	}
+
+	_, err := q.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.XAck(ctx, q.stream, redisStreamGroup, ids...)
+		pipe.XDel(ctx, q.stream, ids...)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("redisStreamQueue.Ack: %w", err)
+	}
+	return nil
+}
+
+// Nack はメッセージを削除し、受信回数を引き継いで delay 後に配信されるよう追加し直します
+// ストリームのエントリーは配信時刻を変えられないため、Nack 後のメッセージは新しいIDになる
+func (q *redisStreamQueue) Nack(ctx context.Context, msg Message, delay time.Duration) error {
+	_, err := q.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.XAck(ctx, q.stream, redisStreamGroup, msg.Handle)
+		pipe.XDel(ctx, q.stream, msg.Handle)
+		return q.add(ctx, pipe, msg.Body, msg.ReceiveCount, delay)
+	})
+	if err != nil {
+		return fmt.Errorf("redisStreamQueue.Nack: %w", err)
+	}
+	return nil
+}
+
+// DeadLetter はメッセージを削除し、受信回数と一緒にデッドレターのストリーム (queue:<name>:dead) へ追加します
+// デッドレターのストリームにはコンシューマーグループを作らないため、ワーカーが受信することはない
+func (q *redisStreamQueue) DeadLetter(ctx context.Context, msg Message) error {
+	_, err := q.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.XAck(ctx, q.stream, redisStreamGroup, msg.Handle)
+		pipe.XDel(ctx, q.stream, msg.Handle)
+		pipe.XAdd(ctx, &redis.XAddArgs{
+			Stream: q.dead,
+			Values: map[string]any{"body": msg.Body, "receives": msg.ReceiveCount},
+		})
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("redisStreamQueue.DeadLetter: %w", err)
+	}
+	return nil
+}
+
+// Extend は保留中のアイドル時間を 0 に戻します
+// 再配信までの時間は timeout ではなく、次に回収するワーカーの visibility で決まる
+func (q *redisStreamQueue) Extend(ctx context.Context, msg Message, timeout time.Duration) error {
+	n, err := extendPendingScript.Run(ctx, q.rdb, []string{q.stream}, redisStreamGroup, q.consumer, msg.Handle).Int()
+	if err != nil {
+		return fmt.Errorf("redisStreamQueue.Extend: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("redisStreamQueue.Extend: message %s is no longer held by this consumer", msg.ID)
+	}
+	return nil
+}
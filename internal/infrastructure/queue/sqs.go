@@ -0,0 +1,136 @@
+// internal/infrastructure/queue/sqs.go
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	appaws "my-portfolio-2025/internal/infrastructure/aws"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+const (
+	// SQS の ReceiveMessage / DeleteMessageBatch で1回に扱えるメッセージの上限
+	sqsMaxBatchSize = 10
+	// ReceiveMessage のロングポーリングの待ち時間 (SQS の上限)
+	sqsWaitTimeSeconds = 20
+)
+
+// sqsQueue は Amazon SQS の Queue です
+// 処理に失敗し続けたメッセージは、キューの redrive policy により DLQ へ送られます
+type sqsQueue struct {
+	client *appaws.SQSClient
+}
+
+// NewSQSQueue は SQS を使う Queue を作成します
+func NewSQSQueue(client *appaws.SQSClient) Queue {
+	return &sqsQueue{client: client}
+}
+
+func (q *sqsQueue) Enqueue(ctx context.Context, body []byte, delay time.Duration) error {
+	return q.client.Enqueue(ctx, body, delay)
+}
+
+func (q *sqsQueue) Receive(ctx context.Context, max int, visibility time.Duration) ([]Message, error) {
+	return q.receive(ctx, max, visibility, sqsWaitTimeSeconds)
+}
+
+// receive は最大 waitSeconds 秒のロングポーリングでメッセージを受信します
+func (q *sqsQueue) receive(ctx context.Context, max int, visibility time.Duration, waitSeconds int32) ([]Message, error) {
+	output, err := q.client.Client.ReceiveMessage(ctx, &sqs.ReceiveMessageInput{
+		QueueUrl:            aws.String(q.client.QueueUrl),
+		MaxNumberOfMessages: int32(min(max, sqsMaxBatchSize)),
+		WaitTimeSeconds:     waitSeconds,
+		VisibilityTimeout:   int32(visibility / time.Second),
+		// 受信回数に応じて再試行の間隔を延ばすため、ApproximateReceiveCount を受け取る
+		MessageSystemAttributeNames: []types.MessageSystemAttributeName{
+			types.MessageSystemAttributeNameApproximateReceiveCount,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("sqsQueue.Receive: %w", err)
+	}
+
+	msgs := make([]Message, len(output.Messages))
+	for i, m := range output.Messages {
+		msgs[i] = Message{
+			ID:           aws.ToString(m.MessageId),
+			Handle:       aws.ToString(m.ReceiptHandle),
+			Body:         aws.ToString(m.Body),
+			ReceiveCount: approximateReceiveCount(m),
+		}
+	}
+	return msgs, nil
+}
+
+// approximateReceiveCount はメッセージの受信回数 (ApproximateReceiveCount) を返します。取得できない場合は 1
+func approximateReceiveCount(m types.Message) int {
+	n, err := strconv.Atoi(m.Attributes[string(types.MessageSystemAttributeNameApproximateReceiveCount)])
+	if err != nil || n < 1 {
+		return 1
+	}
+	return n
+}
+
+// Ack は DeleteMessageBatch で10件ずつ削除します。削除できなかったメッセージはまとめてエラーで返す
+func (q *sqsQueue) Ack(ctx context.Context, msgs []Message) error {
+	var errs []error
+	for start := 0; start < len(msgs); start += sqsMaxBatchSize {
+		batch := msgs[start:min(start+sqsMaxBatchSize, len(msgs))]
+		entries := make([]types.DeleteMessageBatchRequestEntry, len(batch))
+		for i, m := range batch {
+			entries[i] = types.DeleteMessageBatchRequestEntry{
+				Id:            aws.String(strconv.Itoa(i)),
+				ReceiptHandle: aws.String(m.Handle),
+			}
+		}
+
+		output, err := q.client.Client.DeleteMessageBatch(ctx, &sqs.DeleteMessageBatchInput{
+			QueueUrl: aws.String(q.client.QueueUrl),
+			Entries:  entries,
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("delete %d messages: %w", len(batch), err))
+			continue
+		}
+		for _, failed := range output.Failed {
+			i, _ := strconv.Atoi(aws.ToString(failed.Id))
+			errs = append(errs, fmt.Errorf("delete message %s: %s: %s", batch[i].ID, aws.ToString(failed.Code), aws.ToString(failed.Message)))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("sqsQueue.Ack: %w", err)
+	}
+	return nil
+}
+
+// Nack は可視性タイムアウトを delay に変更します
+// delay が 0 の場合はすぐに再受信され、受信回数が maxReceiveCount を超えると redrive policy で DLQ へ送られる
+func (q *sqsQueue) Nack(ctx context.Context, msg Message, delay time.Duration) error {
+	if err := q.changeVisibility(ctx, msg, delay); err != nil {
+		return fmt.Errorf("sqsQueue.Nack: %w", err)
+	}
+	return nil
+}
+
+func (q *sqsQueue) Extend(ctx context.Context, msg Message, timeout time.Duration) error {
+	if err := q.changeVisibility(ctx, msg, timeout); err != nil {
+		return fmt.Errorf("sqsQueue.Extend: %w", err)
+	}
+	return nil
+}
+
+func (q *sqsQueue) changeVisibility(ctx context.Context, msg Message, timeout time.Duration) error {
+	_, err := q.client.Client.ChangeMessageVisibility(ctx, &sqs.ChangeMessageVisibilityInput{
+		QueueUrl:          aws.String(q.client.QueueUrl),
+		ReceiptHandle:     aws.String(msg.Handle),
+		VisibilityTimeout: int32(timeout / time.Second),
+	})
+	return err
+}
@@ -0,0 +1,26 @@
+package queue
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApproximateReceiveCount(t *testing.T) {
+	msg := types.Message{Attributes: map[string]string{
+		string(types.MessageSystemAttributeNameApproximateReceiveCount): "3",
+	}}
+	assert.Equal(t, 3, approximateReceiveCount(msg))
+	assert.Equal(t, 1, approximateReceiveCount(types.Message{}), "属性がなければ初回の受信として扱う")
+}
+
+func TestParseHandle(t *testing.T) {
+	id, receipt, err := parseHandle("42:0b5e4c1a")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), id)
+	assert.Equal(t, "0b5e4c1a", receipt)
+
+	_, _, err = parseHandle("not-a-handle")
+	assert.Error(t, err)
+}
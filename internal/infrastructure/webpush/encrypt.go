@@ -0,0 +1,126 @@
+package webpush
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+const (
+	// 1レコードの大きさ。ペイロードは1レコードに収める
+	recordSize = 4096
+	// ヘッダー: salt(16) + rs(4) + idlen(1) + keyid(65)
+	headerSize = 16 + 4 + 1 + 65
+	// MaxPayloadSize は送信できる平文の最大長です
+	// プッシュサービスが受け付ける本文はヘッダーを含めて 4096 バイトまでのため (RFC 8030)、
+	// 4096 - ヘッダー - GCM タグ - 区切り文字 (3993 バイト) とする
+	MaxPayloadSize = 4096 - headerSize - 16 - 1
+)
+
+// ValidateKeys はブラウザから受け取った購読の鍵が正しい形式かを確認します
+func ValidateKeys(p256dh, auth string) error {
+	raw, err := decodeBase64(p256dh)
+	if err != nil {
+		return fmt.Errorf("p256dh: %w", err)
+	}
+	if _, err := ecdh.P256().NewPublicKey(raw); err != nil {
+		return fmt.Errorf("p256dh: %w", err)
+	}
+	secret, err := decodeBase64(auth)
+	if err != nil {
+		return fmt.Errorf("auth: %w", err)
+	}
+	if len(secret) != 16 {
+		return errors.New("auth: must be 16 bytes")
+	}
+	return nil
+}
+
+// Encrypt は RFC 8291 に従い、ブラウザの公開鍵 (p256dh) と認証シークレット (auth) で
+// ペイロードを aes128gcm 形式に暗号化します。送信ごとに一時鍵と salt を生成します
+func Encrypt(p256dh, auth string, plaintext []byte) ([]byte, error) {
+	if len(plaintext) > MaxPayloadSize {
+		return nil, fmt.Errorf("webpush.Encrypt: payload too large (%d bytes)", len(plaintext))
+	}
+	uaPublicRaw, err := decodeBase64(p256dh)
+	if err != nil {
+		return nil, fmt.Errorf("webpush.Encrypt (p256dh): %w", err)
+	}
+	authSecret, err := decodeBase64(auth)
+	if err != nil {
+		return nil, fmt.Errorf("webpush.Encrypt (auth): %w", err)
+	}
+	if len(authSecret) != 16 {
+		return nil, errors.New("webpush.Encrypt: auth secret must be 16 bytes")
+	}
+
+	curve := ecdh.P256()
+	uaPublic, err := curve.NewPublicKey(uaPublicRaw)
+	if err != nil {
+		return nil, fmt.Errorf("webpush.Encrypt (p256dh): %w", err)
+	}
+	asPrivate, err := curve.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("webpush.Encrypt: %w", err)
+	}
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("webpush.Encrypt: %w", err)
+	}
+
+	shared, err := asPrivate.ECDH(uaPublic)
+	if err != nil {
+		return nil, fmt.Errorf("webpush.Encrypt: %w", err)
+	}
+	asPublic := asPrivate.PublicKey().Bytes()
+	cek, nonce, err := deriveKeys(shared, uaPublicRaw, asPublic, authSecret, salt)
+	if err != nil {
+		return nil, fmt.Errorf("webpush.Encrypt: %w", err)
+	}
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, fmt.Errorf("webpush.Encrypt: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("webpush.Encrypt: %w", err)
+	}
+
+	// 最後のレコードであることを表す区切り文字 (0x02) を付けて暗号化する
+	record := append(append(make([]byte, 0, len(plaintext)+1), plaintext...), 0x02)
+
+	out := make([]byte, headerSize, headerSize+len(record)+gcm.Overhead())
+	copy(out, salt)
+	binary.BigEndian.PutUint32(out[16:20], recordSize)
+	out[20] = byte(len(asPublic))
+	copy(out[21:], asPublic)
+	return gcm.Seal(out, nonce, record, nil), nil
+}
+
+// deriveKeys は ECDH の共有鍵からコンテンツ暗号鍵 (CEK) とナンスを導出します
+// 受信側 (ブラウザ) は自分の秘密鍵と送信側の公開鍵から同じ値を導出します
+func deriveKeys(shared, uaPublic, asPublic, authSecret, salt []byte) (cek, nonce []byte, err error) {
+	// key_info = "WebPush: info" || 0x00 || ua_public || as_public
+	keyInfo := "WebPush: info\x00" + string(uaPublic) + string(asPublic)
+	ikm, err := hkdf.Key(sha256.New, shared, authSecret, keyInfo, 32)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cek, err = hkdf.Key(sha256.New, ikm, salt, "Content-Encoding: aes128gcm\x00", 16)
+	if err != nil {
+		return nil, nil, err
+	}
+	nonce, err = hkdf.Key(sha256.New, ikm, salt, "Content-Encoding: nonce\x00", 12)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cek, nonce, nil
+}
@@ -0,0 +1,201 @@
+// Package webpush は Web Push (RFC 8030) による通知の送信を扱います
+// ペイロードは RFC 8291 (aes128gcm) で暗号化し、送信元は VAPID (RFC 8292) で証明します
+package webpush
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"my-portfolio-2025/internal/infrastructure/egress"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ErrSubscriptionGone は購読が失効していることを表します (404 / 410)
+// 呼び出し側は購読を削除してください
+var ErrSubscriptionGone = errors.New("webpush: subscription is no longer valid")
+
+const (
+	// VAPID の JWT の有効期間 (仕様上の上限は24時間)
+	vapidTokenTTL = 12 * time.Hour
+	// 既定の TTL。ブラウザがオフラインの間、プッシュサービスが保持する時間
+	defaultTTL = 24 * time.Hour
+)
+
+// Config は VAPID の設定です
+type Config struct {
+	PublicKey  string // 非圧縮形式の P-256 公開鍵 (base64url)。ブラウザの applicationServerKey に渡す
+	PrivateKey string // P-256 秘密鍵のスカラー値 (base64url)
+	Subject    string // 連絡先 ("mailto:..." または "https://...")
+}
+
+// ConfigFromEnv は環境変数から VAPID 設定を読み込みます
+// 鍵と連絡先がそろっていない場合は ok=false を返し、Web Push は無効になります
+func ConfigFromEnv() (cfg Config, ok bool) {
+	cfg = Config{
+		PublicKey:  os.Getenv("VAPID_PUBLIC_KEY"),
+		PrivateKey: os.Getenv("VAPID_PRIVATE_KEY"),
+		Subject:    os.Getenv("VAPID_SUBJECT"),
+	}
+	return cfg, cfg.PublicKey != "" && cfg.PrivateKey != "" && cfg.Subject != ""
+}
+
+// GenerateKeys は新しい VAPID の鍵ペアを作成します
+func GenerateKeys() (publicKey, privateKey string, err error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return "", "", fmt.Errorf("webpush.GenerateKeys: %w", err)
+	}
+	pub, err := key.PublicKey.Bytes()
+	if err != nil {
+		return "", "", fmt.Errorf("webpush.GenerateKeys: %w", err)
+	}
+	priv, err := key.Bytes()
+	if err != nil {
+		return "", "", fmt.Errorf("webpush.GenerateKeys: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(pub), base64.RawURLEncoding.EncodeToString(priv), nil
+}
+
+// Subscription はブラウザの PushSubscription です
+type Subscription struct {
+	Endpoint string
+	P256dh   string // ブラウザの公開鍵 (base64url)
+	Auth     string // 認証シークレット (base64url)
+}
+
+// Options は1回の送信の設定です
+type Options struct {
+	TTL     time.Duration // 0 の場合は defaultTTL
+	Urgency string        // "very-low" / "low" / "normal" / "high"。空の場合は付けない
+	Topic   string        // 同じ Topic の未配信メッセージは新しいもので置き換えられる
+}
+
+// Client はプッシュサービスへ送信する HTTP クライアントです
+type Client struct {
+	cfg        Config
+	key        *ecdsa.PrivateKey
+	httpClient *http.Client
+}
+
+// NewClient は Client を作成します
+// 購読のエンドポイントはユーザーが登録する URL のため、送信時に egress.Default で接続先を検査します
+func NewClient(cfg Config) (*Client, error) {
+	raw, err := decodeBase64(cfg.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("webpush.NewClient (private key): %w", err)
+	}
+	key, err := ecdsa.ParseRawPrivateKey(elliptic.P256(), raw)
+	if err != nil {
+		return nil, fmt.Errorf("webpush.NewClient (private key): %w", err)
+	}
+	pub, err := key.PublicKey.Bytes()
+	if err != nil {
+		return nil, fmt.Errorf("webpush.NewClient (public key): %w", err)
+	}
+	if base64.RawURLEncoding.EncodeToString(pub) != trimPadding(cfg.PublicKey) {
+		return nil, errors.New("webpush.NewClient: VAPID public key does not match the private key")
+	}
+
+	return &Client{
+		cfg:        cfg,
+		key:        key,
+		httpClient: egress.Default.NewHTTPClient(10 * time.Second),
+	}, nil
+}
+
+// PublicKey はブラウザの pushManager.subscribe に渡す applicationServerKey です
+func (c *Client) PublicKey() string {
+	return c.cfg.PublicKey
+}
+
+// Send はペイロードを暗号化して購読先へ送信します
+// 購読が失効している場合は ErrSubscriptionGone を返します
+func (c *Client) Send(ctx context.Context, sub *Subscription, payload []byte, opts Options) error {
+	body, err := Encrypt(sub.P256dh, sub.Auth, payload)
+	if err != nil {
+		return fmt.Errorf("webpush.Client.Send: %w", err)
+	}
+	authorization, err := c.vapidAuthorization(sub.Endpoint)
+	if err != nil {
+		return fmt.Errorf("webpush.Client.Send: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("webpush.Client.Send: %w", err)
+	}
+	ttl := opts.TTL
+	if ttl <= 0 {
+		ttl = defaultTTL
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("Content-Encoding", "aes128gcm")
+	req.Header.Set("TTL", strconv.Itoa(int(ttl.Seconds())))
+	req.Header.Set("Authorization", authorization)
+	if opts.Urgency != "" {
+		req.Header.Set("Urgency", opts.Urgency)
+	}
+	if opts.Topic != "" {
+		req.Header.Set("Topic", opts.Topic)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("webpush.Client.Send: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return fmt.Errorf("webpush.Client.Send (status=%d): %w", resp.StatusCode, ErrSubscriptionGone)
+	default:
+		return fmt.Errorf("webpush.Client.Send: unexpected status %d", resp.StatusCode)
+	}
+}
+
+// vapidAuthorization は RFC 8292 の Authorization ヘッダーを作成します
+// aud はプッシュサービスのオリジン (スキーム + ホスト) です
+func (c *Client) vapidAuthorization(endpoint string) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return "", fmt.Errorf("invalid endpoint %q", endpoint)
+	}
+
+	claims := jwt.RegisteredClaims{
+		Audience:  jwt.ClaimStrings{u.Scheme + "://" + u.Host},
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(vapidTokenTTL)),
+		Subject:   c.cfg.Subject,
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodES256, claims).SignedString(c.key)
+	if err != nil {
+		return "", fmt.Errorf("vapid sign: %w", err)
+	}
+	return "vapid t=" + token + ", k=" + trimPadding(c.cfg.PublicKey), nil
+}
+
+// decodeBase64 はブラウザから受け取る鍵を復号します (URL セーフ・パディング有無の両方を受け付ける)
+func decodeBase64(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(trimPadding(s))
+}
+
+func trimPadding(s string) string {
+	for len(s) > 0 && s[len(s)-1] == '=' {
+		s = s[:len(s)-1]
+	}
+	return s
+}
@@ -0,0 +1,180 @@
+package webpush
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"my-portfolio-2025/internal/infrastructure/egress"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// browser はブラウザ側の鍵 (PushSubscription の keys) を模したものです
+type browser struct {
+	private *ecdh.PrivateKey
+	auth    []byte
+}
+
+func newBrowser(t *testing.T) *browser {
+	key, err := ecdh.P256().GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	auth := make([]byte, 16)
+	_, err = rand.Read(auth)
+	require.NoError(t, err)
+	return &browser{private: key, auth: auth}
+}
+
+func (b *browser) subscription(endpoint string) *Subscription {
+	return &Subscription{
+		Endpoint: endpoint,
+		P256dh:   base64.RawURLEncoding.EncodeToString(b.private.PublicKey().Bytes()),
+		Auth:     base64.RawURLEncoding.EncodeToString(b.auth),
+	}
+}
+
+// decrypt はブラウザと同じ手順で aes128gcm のメッセージを復号します
+func (b *browser) decrypt(t *testing.T, body []byte) []byte {
+	require.Greater(t, len(body), headerSize)
+	salt := body[:16]
+	assert.Equal(t, uint32(recordSize), binary.BigEndian.Uint32(body[16:20]))
+	keyLen := int(body[20])
+	asPublicRaw := body[21 : 21+keyLen]
+
+	asPublic, err := ecdh.P256().NewPublicKey(asPublicRaw)
+	require.NoError(t, err)
+	shared, err := b.private.ECDH(asPublic)
+	require.NoError(t, err)
+	cek, nonce, err := deriveKeys(shared, b.private.PublicKey().Bytes(), asPublicRaw, b.auth, salt)
+	require.NoError(t, err)
+
+	block, err := aes.NewCipher(cek)
+	require.NoError(t, err)
+	gcm, err := cipher.NewGCM(block)
+	require.NoError(t, err)
+	record, err := gcm.Open(nil, nonce, body[21+keyLen:], nil)
+	require.NoError(t, err)
+
+	require.Equal(t, byte(0x02), record[len(record)-1], "最後のレコードの区切り文字")
+	return record[:len(record)-1]
+}
+
+func newTestClient(t *testing.T) *Client {
+	pub, priv, err := GenerateKeys()
+	require.NoError(t, err)
+	c, err := NewClient(Config{PublicKey: pub, PrivateKey: priv, Subject: "mailto:ops@example.com"})
+	require.NoError(t, err)
+	// httptest のローカルサーバー (http://127.0.0.1) へ送信できるようにする
+	c.httpClient = (&egress.Guard{AllowHTTP: true, AllowPrivate: true}).NewHTTPClient(10 * time.Second)
+	return c
+}
+
+func TestEncrypt_RoundTrip(t *testing.T) {
+	b := newBrowser(t)
+	sub := b.subscription("https://push.example.com/x")
+	plaintext := []byte(`{"title":"タスクの期限が近づいています"}`)
+
+	body, err := Encrypt(sub.P256dh, sub.Auth, plaintext)
+	require.NoError(t, err)
+	assert.Equal(t, plaintext, b.decrypt(t, body))
+
+	// 上限ちょうどのペイロードは、ヘッダーを含めて 4096 バイトに収まる
+	maxPayload := make([]byte, MaxPayloadSize)
+	body, err = Encrypt(sub.P256dh, sub.Auth, maxPayload)
+	require.NoError(t, err)
+	assert.LessOrEqual(t, len(body), 4096)
+	assert.Equal(t, maxPayload, b.decrypt(t, body))
+
+	_, err = Encrypt(sub.P256dh, sub.Auth, make([]byte, MaxPayloadSize+1))
+	assert.Error(t, err, "1レコードに収まらないペイロードは送れない")
+}
+
+func TestClientSend(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		expectedErr error
+		expectErr   bool
+	}{
+		{name: "正常系：201 Created", status: http.StatusCreated},
+		{name: "失効：410 Gone", status: http.StatusGone, expectedErr: ErrSubscriptionGone, expectErr: true},
+		{name: "失効：404 Not Found", status: http.StatusNotFound, expectedErr: ErrSubscriptionGone, expectErr: true},
+		{name: "異常系：429 は失効扱いにしない", status: http.StatusTooManyRequests, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t)
+			b := newBrowser(t)
+			var received []byte
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "aes128gcm", r.Header.Get("Content-Encoding"))
+				assert.NotEmpty(t, r.Header.Get("TTL"))
+				verifyVAPID(t, r, client.PublicKey(), "http://"+r.Host)
+				received, _ = io.ReadAll(r.Body)
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			err := client.Send(context.Background(), b.subscription(server.URL+"/push/abc"), []byte("hello"), Options{})
+
+			if !tt.expectErr {
+				require.NoError(t, err)
+				assert.Equal(t, []byte("hello"), b.decrypt(t, received))
+				return
+			}
+			require.Error(t, err)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				assert.False(t, errors.Is(err, ErrSubscriptionGone))
+			}
+		})
+	}
+}
+
+func TestNewClient_RejectsMismatchedKeys(t *testing.T) {
+	pub, _, err := GenerateKeys()
+	require.NoError(t, err)
+	_, priv, err := GenerateKeys()
+	require.NoError(t, err)
+
+	_, err = NewClient(Config{PublicKey: pub, PrivateKey: priv, Subject: "mailto:ops@example.com"})
+	assert.Error(t, err)
+}
+
+// verifyVAPID はプッシュサービスと同じ手順で Authorization ヘッダーを検証します
+func verifyVAPID(t *testing.T, r *http.Request, publicKey, audience string) {
+	header := r.Header.Get("Authorization")
+	require.True(t, strings.HasPrefix(header, "vapid t="), header)
+	parts := strings.SplitN(strings.TrimPrefix(header, "vapid t="), ", k=", 2)
+	require.Len(t, parts, 2)
+	assert.Equal(t, publicKey, parts[1])
+
+	raw, err := base64.RawURLEncoding.DecodeString(parts[1])
+	require.NoError(t, err)
+	pub, err := ecdsa.ParseUncompressedPublicKey(elliptic.P256(), raw)
+	require.NoError(t, err)
+
+	claims := &jwt.RegisteredClaims{}
+	_, err = jwt.ParseWithClaims(parts[0], claims, func(*jwt.Token) (interface{}, error) { return pub, nil },
+		jwt.WithValidMethods([]string{"ES256"}), jwt.WithAudience(audience))
+	require.NoError(t, err)
+	assert.Equal(t, "mailto:ops@example.com", claims.Subject)
+}
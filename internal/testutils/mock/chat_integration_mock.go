@@ -0,0 +1,66 @@
+// internal/testutils/mock/chat_integration_mock.go
+package mock
+
+import (
+	"context"
+	"my-portfolio-2025/internal/app/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockChatIntegrationRepository は repository.ChatIntegrationRepository インターフェースのモックです
+type MockChatIntegrationRepository struct {
+	mock.Mock
+}
+
+// Create は ChatIntegrationRepository.Create のモック実装です
+func (m *MockChatIntegrationRepository) Create(ctx context.Context, integration *models.ChatIntegration) error {
+	args := m.Called(ctx, integration)
+	return args.Error(0)
+}
+
+// FindByID は ChatIntegrationRepository.FindByID のモック実装です
+func (m *MockChatIntegrationRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.ChatIntegration, error) {
+	args := m.Called(ctx, id)
+
+	var integration *models.ChatIntegration
+	if args.Get(0) != nil {
+		integration = args.Get(0).(*models.ChatIntegration)
+	}
+	return integration, args.Error(1)
+}
+
+// FindByUserID は ChatIntegrationRepository.FindByUserID のモック実装です
+func (m *MockChatIntegrationRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]models.ChatIntegration, error) {
+	args := m.Called(ctx, userID)
+
+	var integrations []models.ChatIntegration
+	if args.Get(0) != nil {
+		integrations = args.Get(0).([]models.ChatIntegration)
+	}
+	return integrations, args.Error(1)
+}
+
+// FindActiveByType は ChatIntegrationRepository.FindActiveByType のモック実装です
+func (m *MockChatIntegrationRepository) FindActiveByType(ctx context.Context, userID uuid.UUID, notificationType string) ([]models.ChatIntegration, error) {
+	args := m.Called(ctx, userID, notificationType)
+
+	var integrations []models.ChatIntegration
+	if args.Get(0) != nil {
+		integrations = args.Get(0).([]models.ChatIntegration)
+	}
+	return integrations, args.Error(1)
+}
+
+// Update は ChatIntegrationRepository.Update のモック実装です
+func (m *MockChatIntegrationRepository) Update(ctx context.Context, integration *models.ChatIntegration) error {
+	args := m.Called(ctx, integration)
+	return args.Error(0)
+}
+
+// Delete は ChatIntegrationRepository.Delete のモック実装です
+func (m *MockChatIntegrationRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
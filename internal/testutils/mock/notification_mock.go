@@ -0,0 +1,125 @@
+// internal/testutils/mock/notification_mock.go
+package mock
+
+import (
+	"context"
+	"my-portfolio-2025/internal/app/models"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockNotificationRepository は repository.NotificationRepository インターフェースのモックです
+type MockNotificationRepository struct {
+	mock.Mock
+}
+
+// Create は NotificationRepository.Create のモック実装です
+func (m *MockNotificationRepository) Create(ctx context.Context, n *models.Notification) (bool, error) {
+	args := m.Called(ctx, n)
+	return args.Bool(0), args.Error(1)
+}
+
+// FindByUserID は NotificationRepository.FindByUserID のモック実装です
+func (m *MockNotificationRepository) FindByUserID(ctx context.Context, userID uuid.UUID, filter models.NotificationFilter) ([]models.Notification, error) {
+	args := m.Called(ctx, userID, filter)
+
+	var notifications []models.Notification
+	if args.Get(0) != nil {
+		notifications = args.Get(0).([]models.Notification)
+	}
+	return notifications, args.Error(1)
+}
+
+// CountUnread は NotificationRepository.CountUnread のモック実装です
+func (m *MockNotificationRepository) CountUnread(ctx context.Context, userID uuid.UUID) (int64, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// MarkAsRead は NotificationRepository.MarkAsRead のモック実装です
+func (m *MockNotificationRepository) MarkAsRead(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
+	args := m.Called(ctx, id, userID)
+	return args.Error(0)
+}
+
+// MarkAllAsRead は NotificationRepository.MarkAllAsRead のモック実装です
+func (m *MockNotificationRepository) MarkAllAsRead(ctx context.Context, userID uuid.UUID) (int64, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// Delete は NotificationRepository.Delete のモック実装です
+func (m *MockNotificationRepository) Delete(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
+	args := m.Called(ctx, id, userID)
+	return args.Error(0)
+}
+
+// MarkAsAcked は NotificationRepository.MarkAsAcked のモック実装です
+func (m *MockNotificationRepository) MarkAsAcked(ctx context.Context, id uuid.UUID, userID uuid.UUID, ackedAt time.Time) error {
+	args := m.Called(ctx, id, userID, ackedAt)
+	return args.Error(0)
+}
+
+// FindChangedSince は NotificationRepository.FindChangedSince のモック実装です
+func (m *MockNotificationRepository) FindChangedSince(ctx context.Context, userID uuid.UUID, since time.Time) ([]models.Notification, error) {
+	args := m.Called(ctx, userID, since)
+
+	var notifications []models.Notification
+	if args.Get(0) != nil {
+		notifications = args.Get(0).([]models.Notification)
+	}
+	return notifications, args.Error(1)
+}
+
+// FindDueDeferred は NotificationRepository.FindDueDeferred のモック実装です
+func (m *MockNotificationRepository) FindDueDeferred(ctx context.Context, now time.Time, limit int) ([]models.Notification, error) {
+	args := m.Called(ctx, now, limit)
+
+	var notifications []models.Notification
+	if args.Get(0) != nil {
+		notifications = args.Get(0).([]models.Notification)
+	}
+	return notifications, args.Error(1)
+}
+
+// ClearDeferred は NotificationRepository.ClearDeferred のモック実装です
+func (m *MockNotificationRepository) ClearDeferred(ctx context.Context, id uuid.UUID) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+// FindByID は NotificationRepository.FindByID のモック実装です
+func (m *MockNotificationRepository) FindByID(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*models.Notification, error) {
+	args := m.Called(ctx, id, userID)
+
+	var n *models.Notification
+	if args.Get(0) != nil {
+		n = args.Get(0).(*models.Notification)
+	}
+	return n, args.Error(1)
+}
+
+// Snooze は NotificationRepository.Snooze のモック実装です
+func (m *MockNotificationRepository) Snooze(ctx context.Context, id uuid.UUID, userID uuid.UUID, until time.Time) error {
+	args := m.Called(ctx, id, userID, until)
+	return args.Error(0)
+}
+
+// MarkDelivered は NotificationRepository.MarkDelivered のモック実装です
+func (m *MockNotificationRepository) MarkDelivered(ctx context.Context, id uuid.UUID, deliveredAt time.Time) error {
+	args := m.Called(ctx, id, deliveredAt)
+	return args.Error(0)
+}
+
+// MockNotificationEventPublisher は service.NotificationEventPublisher インターフェースのモックです
+type MockNotificationEventPublisher struct {
+	mock.Mock
+}
+
+// PublishEvent は NotificationEventPublisher.PublishEvent のモック実装です
+func (m *MockNotificationEventPublisher) PublishEvent(ctx context.Context, userID uuid.UUID, taskID *uuid.UUID, eventType string, payload interface{}) error {
+	args := m.Called(ctx, userID, taskID, eventType, payload)
+	return args.Error(0)
+}
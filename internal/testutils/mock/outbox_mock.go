@@ -0,0 +1,50 @@
+// internal/testutils/mock/outbox_mock.go
+package mock
+
+import (
+	"context"
+	"my-portfolio-2025/internal/app/models"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockOutboxRepository は repository.OutboxRepository インターフェースのモックです
+type MockOutboxRepository struct {
+	mock.Mock
+}
+
+// Add は OutboxRepository.Add のモック実装です
+func (m *MockOutboxRepository) Add(ctx context.Context, msg *models.OutboxMessage) error {
+	args := m.Called(ctx, msg)
+	return args.Error(0)
+}
+
+// LockPending は OutboxRepository.LockPending のモック実装です
+func (m *MockOutboxRepository) LockPending(ctx context.Context, now time.Time, limit int) ([]models.OutboxMessage, error) {
+	args := m.Called(ctx, now, limit)
+
+	var msgs []models.OutboxMessage
+	if args.Get(0) != nil {
+		msgs = args.Get(0).([]models.OutboxMessage)
+	}
+	return msgs, args.Error(1)
+}
+
+// MarkSent は OutboxRepository.MarkSent のモック実装です
+func (m *MockOutboxRepository) MarkSent(ctx context.Context, id int64, sentAt time.Time) error {
+	args := m.Called(ctx, id, sentAt)
+	return args.Error(0)
+}
+
+// MarkFailed は OutboxRepository.MarkFailed のモック実装です
+func (m *MockOutboxRepository) MarkFailed(ctx context.Context, id int64, reason string, nextAttemptAt time.Time) error {
+	args := m.Called(ctx, id, reason, nextAttemptAt)
+	return args.Error(0)
+}
+
+// DeleteSentBefore は OutboxRepository.DeleteSentBefore のモック実装です
+func (m *MockOutboxRepository) DeleteSentBefore(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
@@ -0,0 +1,55 @@
+// internal/testutils/mock/push_mock.go
+package mock
+
+import (
+	"context"
+	"my-portfolio-2025/internal/app/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPushSubscriptionRepository は repository.PushSubscriptionRepository インターフェースのモックです
+type MockPushSubscriptionRepository struct {
+	mock.Mock
+}
+
+// Upsert は PushSubscriptionRepository.Upsert のモック実装です
+func (m *MockPushSubscriptionRepository) Upsert(ctx context.Context, sub *models.PushSubscription) error {
+	args := m.Called(ctx, sub)
+	return args.Error(0)
+}
+
+// FindByUserID は PushSubscriptionRepository.FindByUserID のモック実装です
+func (m *MockPushSubscriptionRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]models.PushSubscription, error) {
+	args := m.Called(ctx, userID)
+
+	var subs []models.PushSubscription
+	if args.Get(0) != nil {
+		subs = args.Get(0).([]models.PushSubscription)
+	}
+	return subs, args.Error(1)
+}
+
+// DeleteByEndpoint は PushSubscriptionRepository.DeleteByEndpoint のモック実装です
+func (m *MockPushSubscriptionRepository) DeleteByEndpoint(ctx context.Context, userID uuid.UUID, endpoint string) error {
+	args := m.Called(ctx, userID, endpoint)
+	return args.Error(0)
+}
+
+// Delete は PushSubscriptionRepository.Delete のモック実装です
+func (m *MockPushSubscriptionRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// MockPresenceChecker は service.PresenceChecker インターフェースのモックです
+type MockPresenceChecker struct {
+	mock.Mock
+}
+
+// IsOnline は PresenceChecker.IsOnline のモック実装です
+func (m *MockPresenceChecker) IsOnline(ctx context.Context, userID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, userID)
+	return args.Bool(0), args.Error(1)
+}
@@ -0,0 +1,45 @@
+// internal/testutils/mock/task_escalation_mock.go
+package mock
+
+import (
+	"context"
+	"my-portfolio-2025/internal/app/models"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockTaskEscalationRepository は repository.TaskEscalationRepository インターフェースのモックです
+type MockTaskEscalationRepository struct {
+	mock.Mock
+}
+
+// StartOverdue は TaskEscalationRepository.StartOverdue のモック実装です
+func (m *MockTaskEscalationRepository) StartOverdue(ctx context.Context, now time.Time, firstDelay time.Duration) error {
+	args := m.Called(ctx, now, firstDelay)
+	return args.Error(0)
+}
+
+// ClaimDue は TaskEscalationRepository.ClaimDue のモック実装です
+func (m *MockTaskEscalationRepository) ClaimDue(ctx context.Context, now time.Time, maxLevel int, limit int, next func(level int) time.Time) ([]models.TaskEscalation, error) {
+	args := m.Called(ctx, now, maxLevel, limit, next)
+
+	var escalations []models.TaskEscalation
+	if args.Get(0) != nil {
+		escalations = args.Get(0).([]models.TaskEscalation)
+	}
+	return escalations, args.Error(1)
+}
+
+// Unclaim は TaskEscalationRepository.Unclaim のモック実装です
+func (m *MockTaskEscalationRepository) Unclaim(ctx context.Context, taskID uuid.UUID, level int, now time.Time) error {
+	args := m.Called(ctx, taskID, level, now)
+	return args.Error(0)
+}
+
+// DeleteByTaskID は TaskEscalationRepository.DeleteByTaskID のモック実装です
+func (m *MockTaskEscalationRepository) DeleteByTaskID(ctx context.Context, taskID uuid.UUID) error {
+	args := m.Called(ctx, taskID)
+	return args.Error(0)
+}
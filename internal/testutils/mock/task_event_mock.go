@@ -0,0 +1,19 @@
+package mock
+
+import (
+	"context"
+	"my-portfolio-2025/internal/app/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockTaskEventPublisher は service.TaskEventPublisher インターフェースのモックです
+type MockTaskEventPublisher struct {
+	mock.Mock
+}
+
+// PublishTaskEvent は TaskEventPublisher.PublishTaskEvent のモック実装です
+func (m *MockTaskEventPublisher) PublishTaskEvent(ctx context.Context, event *models.TaskEvent) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
@@ -95,3 +95,30 @@ func (m *MockTaskRepository) UpdateLastNotifiedAt(ctx context.Context, taskID uu
 	args := m.Called(ctx, taskID, notifiedAt)
 	return args.Error(0)
 }
+
+// FindChangedSince は TaskRepository.FindChangedSince のモック実装です
+func (m *MockTaskRepository) FindChangedSince(ctx context.Context, userID uuid.UUID, since time.Time) ([]models.Task, error) {
+	args := m.Called(ctx, userID, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Task), args.Error(1)
+}
+
+// UpdateIfVersion は TaskRepository.UpdateIfVersion のモック実装です
+func (m *MockTaskRepository) UpdateIfVersion(ctx context.Context, task *models.Task, expectedVersion int) (bool, error) {
+	args := m.Called(ctx, task, expectedVersion)
+	return args.Bool(0), args.Error(1)
+}
+
+// CreateIfAbsent は TaskRepository.CreateIfAbsent のモック実装です
+func (m *MockTaskRepository) CreateIfAbsent(ctx context.Context, task *models.Task) (bool, error) {
+	args := m.Called(ctx, task)
+	return args.Bool(0), args.Error(1)
+}
+
+// DeleteIfVersion は TaskRepository.DeleteIfVersion のモック実装です
+func (m *MockTaskRepository) DeleteIfVersion(ctx context.Context, taskID uuid.UUID, expectedVersion int) (bool, error) {
+	args := m.Called(ctx, taskID, expectedVersion)
+	return args.Bool(0), args.Error(1)
+}
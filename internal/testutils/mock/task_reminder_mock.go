@@ -0,0 +1,56 @@
+// internal/testutils/mock/task_reminder_mock.go
+package mock
+
+import (
+	"context"
+	"my-portfolio-2025/internal/app/models"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockTaskReminderRepository は repository.TaskReminderRepository インターフェースのモックです
+type MockTaskReminderRepository struct {
+	mock.Mock
+}
+
+// FindByTaskID は TaskReminderRepository.FindByTaskID のモック実装です
+func (m *MockTaskReminderRepository) FindByTaskID(ctx context.Context, taskID uuid.UUID) ([]models.TaskReminder, error) {
+	args := m.Called(ctx, taskID)
+
+	var reminders []models.TaskReminder
+	if args.Get(0) != nil {
+		reminders = args.Get(0).([]models.TaskReminder)
+	}
+	return reminders, args.Error(1)
+}
+
+// ReplaceForTask は TaskReminderRepository.ReplaceForTask のモック実装です
+func (m *MockTaskReminderRepository) ReplaceForTask(ctx context.Context, taskID uuid.UUID, reminders []models.TaskReminder) error {
+	args := m.Called(ctx, taskID, reminders)
+	return args.Error(0)
+}
+
+// DeleteByTaskID は TaskReminderRepository.DeleteByTaskID のモック実装です
+func (m *MockTaskReminderRepository) DeleteByTaskID(ctx context.Context, taskID uuid.UUID) error {
+	args := m.Called(ctx, taskID)
+	return args.Error(0)
+}
+
+// ClaimDue は TaskReminderRepository.ClaimDue のモック実装です
+func (m *MockTaskReminderRepository) ClaimDue(ctx context.Context, now time.Time, limit int) ([]models.TaskReminder, error) {
+	args := m.Called(ctx, now, limit)
+
+	var reminders []models.TaskReminder
+	if args.Get(0) != nil {
+		reminders = args.Get(0).([]models.TaskReminder)
+	}
+	return reminders, args.Error(1)
+}
+
+// Unclaim は TaskReminderRepository.Unclaim のモック実装です
+func (m *MockTaskReminderRepository) Unclaim(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
@@ -50,6 +50,14 @@ func (m *TaskServiceMock) DeleteTask(userID, taskID uuid.UUID) error {
 	return args.Error(0)
 }
 
+func (m *TaskServiceMock) SnoozeTask(userID, taskID uuid.UUID, req *models.SnoozeRequest) (*models.Task, error) {
+	args := m.Called(userID, taskID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Task), args.Error(1)
+}
+
 func (m *TaskServiceMock) CheckAndQueueDeadlines(ctx context.Context) error {
 	args := m.Called(ctx)
 	return args.Error(0)
@@ -4,6 +4,7 @@ package mock
 import (
 	"my-portfolio-2025/internal/app/models" // モデルパッケージへのパスは適宜修正してください
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -36,3 +37,15 @@ func (m *MockUserRepository) FindByUsername(username string) (*models.User, erro
 
 	return user, args.Error(1)
 }
+
+// FindByID は UserRepository.FindByID のモック実装です
+func (m *MockUserRepository) FindByID(userID uuid.UUID) (*models.User, error) {
+	args := m.Called(userID)
+
+	var user *models.User
+	if args.Get(0) != nil {
+		user = args.Get(0).(*models.User)
+	}
+
+	return user, args.Error(1)
+}
@@ -0,0 +1,102 @@
+// internal/testutils/mock/webhook_mock.go
+package mock
+
+import (
+	"context"
+	"my-portfolio-2025/internal/app/models"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockWebhookRepository は repository.WebhookRepository インターフェースのモックです
+type MockWebhookRepository struct {
+	mock.Mock
+}
+
+// Create は WebhookRepository.Create のモック実装です
+func (m *MockWebhookRepository) Create(ctx context.Context, sub *models.WebhookSubscription) error {
+	args := m.Called(ctx, sub)
+	return args.Error(0)
+}
+
+// FindByID は WebhookRepository.FindByID のモック実装です
+func (m *MockWebhookRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.WebhookSubscription, error) {
+	args := m.Called(ctx, id)
+
+	var sub *models.WebhookSubscription
+	if args.Get(0) != nil {
+		sub = args.Get(0).(*models.WebhookSubscription)
+	}
+	return sub, args.Error(1)
+}
+
+// FindByUserID は WebhookRepository.FindByUserID のモック実装です
+func (m *MockWebhookRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]models.WebhookSubscription, error) {
+	args := m.Called(ctx, userID)
+
+	var subs []models.WebhookSubscription
+	if args.Get(0) != nil {
+		subs = args.Get(0).([]models.WebhookSubscription)
+	}
+	return subs, args.Error(1)
+}
+
+// FindActiveByEvent は WebhookRepository.FindActiveByEvent のモック実装です
+func (m *MockWebhookRepository) FindActiveByEvent(ctx context.Context, userID uuid.UUID, eventType string) ([]models.WebhookSubscription, error) {
+	args := m.Called(ctx, userID, eventType)
+
+	var subs []models.WebhookSubscription
+	if args.Get(0) != nil {
+		subs = args.Get(0).([]models.WebhookSubscription)
+	}
+	return subs, args.Error(1)
+}
+
+// Update は WebhookRepository.Update のモック実装です
+func (m *MockWebhookRepository) Update(ctx context.Context, sub *models.WebhookSubscription) error {
+	args := m.Called(ctx, sub)
+	return args.Error(0)
+}
+
+// Delete は WebhookRepository.Delete のモック実装です
+func (m *MockWebhookRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// RecordFailure は WebhookRepository.RecordFailure のモック実装です
+func (m *MockWebhookRepository) RecordFailure(ctx context.Context, id uuid.UUID) (int, error) {
+	args := m.Called(ctx, id)
+	return args.Int(0), args.Error(1)
+}
+
+// ResetFailures は WebhookRepository.ResetFailures のモック実装です
+func (m *MockWebhookRepository) ResetFailures(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// Disable は WebhookRepository.Disable のモック実装です
+func (m *MockWebhookRepository) Disable(ctx context.Context, id uuid.UUID, at time.Time) error {
+	args := m.Called(ctx, id, at)
+	return args.Error(0)
+}
+
+// CreateDelivery は WebhookRepository.CreateDelivery のモック実装です
+func (m *MockWebhookRepository) CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	args := m.Called(ctx, delivery)
+	return args.Error(0)
+}
+
+// FindDeliveries は WebhookRepository.FindDeliveries のモック実装です
+func (m *MockWebhookRepository) FindDeliveries(ctx context.Context, subscriptionID uuid.UUID, limit int) ([]models.WebhookDelivery, error) {
+	args := m.Called(ctx, subscriptionID, limit)
+
+	var deliveries []models.WebhookDelivery
+	if args.Get(0) != nil {
+		deliveries = args.Get(0).([]models.WebhookDelivery)
+	}
+	return deliveries, args.Error(1)
+}
@@ -0,0 +1,120 @@
+// internal/testutils/smtp_server.go
+package testutils
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// ReceivedMail は FakeSMTPServer が受信した1通のメールです
+type ReceivedMail struct {
+	From string
+	To   []string
+	Data string // DATA コマンドで受け取った生のメッセージ
+}
+
+// FakeSMTPServer はテスト用の最小限の SMTP サーバーです (STARTTLS・認証は非対応)
+// 実際の SMTP サーバーの代わりに、送信されたメールをメモリに記録します
+type FakeSMTPServer struct {
+	Addr string
+
+	listener net.Listener
+	mu       sync.Mutex
+	mails    []ReceivedMail
+}
+
+// NewFakeSMTPServer はローカルの空きポートで FakeSMTPServer を起動します
+// テスト終了時に自動で停止します
+func NewFakeSMTPServer(t *testing.T) *FakeSMTPServer {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("SMTP スタブの起動に失敗しました: %v", err)
+	}
+
+	s := &FakeSMTPServer{Addr: l.Addr().String(), listener: l}
+	go s.serve()
+	t.Cleanup(func() { l.Close() })
+	return s
+}
+
+// Mails は受信したメールの一覧を返します
+func (s *FakeSMTPServer) Mails() []ReceivedMail {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]ReceivedMail(nil), s.mails...)
+}
+
+func (s *FakeSMTPServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *FakeSMTPServer) handle(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+
+	reply("220 fake-smtp ready")
+	var current ReceivedMail
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(strings.TrimSpace(line))
+
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			reply("250 fake-smtp")
+		case strings.HasPrefix(cmd, "MAIL FROM:"):
+			current = ReceivedMail{From: extractAddress(line)}
+			reply("250 OK")
+		case strings.HasPrefix(cmd, "RCPT TO:"):
+			current.To = append(current.To, extractAddress(line))
+			reply("250 OK")
+		case cmd == "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(l, "."))
+			}
+			current.Data = data.String()
+			s.mu.Lock()
+			s.mails = append(s.mails, current)
+			s.mu.Unlock()
+			reply("250 OK")
+		case cmd == "RSET", cmd == "NOOP":
+			reply("250 OK")
+		case cmd == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("502 Command not implemented")
+		}
+	}
+}
+
+func extractAddress(line string) string {
+	start := strings.Index(line, "<")
+	end := strings.LastIndex(line, ">")
+	if start < 0 || end <= start {
+		return ""
+	}
+	return line[start+1 : end]
+}
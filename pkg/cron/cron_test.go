@@ -0,0 +1,76 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNext(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+	at := func(s string) time.Time {
+		v, err := time.ParseInLocation("2006-01-02 15:04", s, jst)
+		require.NoError(t, err)
+		return v
+	}
+
+	tests := []struct {
+		expr string
+		from string
+		want string
+	}{
+		{"* * * * *", "2026-03-10 12:00", "2026-03-10 12:01"},
+		{"*/15 * * * *", "2026-03-10 12:07", "2026-03-10 12:15"},
+		{"*/15 * * * *", "2026-03-10 12:50", "2026-03-10 13:00"},
+		{"0 3 * * *", "2026-03-10 03:00", "2026-03-11 03:00"},
+		{"30 9 * * MON-FRI", "2026-03-13 10:00", "2026-03-16 09:30"}, // 金曜日の後は月曜日
+		{"0 0 1 * *", "2026-01-31 12:00", "2026-02-01 00:00"},
+		{"0 0 29 2 *", "2026-03-01 00:00", "2028-02-29 00:00"}, // うるう年まで進む
+		{"0 12 1 * 1", "2026-03-03 00:00", "2026-03-09 12:00"}, // 日と曜日はどちらかに一致すればよい
+		{"0 0 * * 7", "2026-03-10 00:00", "2026-03-15 00:00"},  // 7 は日曜日
+		{"5/20 8-10 * JAN,MAR *", "2026-03-10 10:50", "2026-03-11 08:05"},
+		{"@hourly", "2026-03-10 12:59", "2026-03-10 13:00"},
+		{"@weekly", "2026-03-10 12:00", "2026-03-15 00:00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			s, err := Parse(tt.expr)
+			require.NoError(t, err)
+			assert.Equal(t, at(tt.want), s.Next(at(tt.from)))
+		})
+	}
+}
+
+func TestNext_IgnoresSeconds(t *testing.T) {
+	s, err := Parse("* * * * *")
+	require.NoError(t, err)
+	from := time.Date(2026, 3, 10, 12, 0, 30, 500, time.UTC)
+	assert.Equal(t, time.Date(2026, 3, 10, 12, 1, 0, 0, time.UTC), s.Next(from))
+}
+
+func TestNext_NoMatch(t *testing.T) {
+	s, err := Parse("0 0 30 2 *")
+	require.NoError(t, err)
+	assert.True(t, s.Next(time.Now()).IsZero())
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+		"@every 5m",
+	} {
+		_, err := Parse(expr)
+		assert.Error(t, err, expr)
+	}
+}